@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	"aviation-service/config"
+	"aviation-service/internal/repository"
+	"aviation-service/internal/service"
+
+	"aviation-service/pkg/logger"
+)
+
+const usage = `Usage:
+  apikey issue -name <name> -role <reader|editor|operator>
+  apikey revoke -id <id>
+  apikey list`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatalw("Failed to load configuration", "error", err)
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+
+	db, err := sqlx.Connect("postgres", cfg.DATABASE_URL)
+	if err != nil {
+		logger.Fatalw("Failed to connect to db", "error", err)
+	}
+	defer db.Close()
+
+	apiKeyService := service.NewAPIKeyService(log, repository.NewAPIKeyRepository(db))
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "issue":
+		cmd := flag.NewFlagSet("issue", flag.ExitOnError)
+		name := cmd.String("name", "", "Name of the caller the key is for, recorded as the actor of its changes")
+		role := cmd.String("role", "reader", "Role of the key: reader, editor or operator")
+		cmd.Parse(os.Args[2:])
+
+		key, apiKey, err := apiKeyService.Issue(ctx, *name, *role)
+		if err != nil {
+			logger.Fatalw("Failed to issue API key", "error", err)
+		}
+		log.Infow("API key issued", "id", apiKey.ID, "name", apiKey.Name, "role", apiKey.Role)
+		// The key is only stored hashed, this is the one time it is shown
+		fmt.Println(key)
+	case "revoke":
+		cmd := flag.NewFlagSet("revoke", flag.ExitOnError)
+		id := cmd.Int("id", 0, "ID of the key to revoke")
+		cmd.Parse(os.Args[2:])
+
+		if err := apiKeyService.Revoke(ctx, *id); err != nil {
+			logger.Fatalw("Failed to revoke API key", "error", err, "id", *id)
+		}
+		log.Infow("API key revoked", "id", *id)
+	case "list":
+		keys, err := apiKeyService.GetAll(ctx)
+		if err != nil {
+			logger.Fatalw("Failed to list API keys", "error", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tROLE\tCREATED\tREVOKED")
+		for _, key := range keys {
+			revoked := "-"
+			if key.RevokedAt != nil {
+				revoked = key.RevokedAt.Format("2006-01-02 15:04")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", key.ID, key.Name, key.Prefix, key.Role, key.CreatedAt.Format("2006-01-02 15:04"), revoked)
+		}
+		w.Flush()
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
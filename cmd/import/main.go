@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"os"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"aviation-service/config"
+	"aviation-service/internal/client"
+	"aviation-service/internal/dto"
+	"aviation-service/internal/repository"
+	"aviation-service/internal/service"
+	"aviation-service/internal/utils"
+
+	"aviation-service/pkg/logger"
+	"aviation-service/pkg/redis"
+	"aviation-service/pkg/tracing"
+)
+
+// Spans still buffered when the command exits get this long to reach the collector
+const tracingFlushTimeout = 5 * time.Second
+
+func main() {
+	file := flag.String("file", "", "Path of the airport file to import (NASR APT_BASE.csv, CSV or JSON)")
+	format := flag.String("format", "", "Import format: nasr, csv or json (default from the file extension)")
+	flag.Parse()
+
+	if *file == "" {
+		logger.Fatal("Missing --file")
+	}
+	if *format == "" {
+		*format = utils.ImportFormatFromFilename(*file)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatalw("Failed to load configuration", "error", err)
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options{
+		ServiceName:  "aviation-import",
+		Exporter:     cfg.TRACING_EXPORTER,
+		OTLPEndpoint: cfg.TRACING_OTLP_ENDPOINT,
+		SampleRatio:  cfg.TRACING_SAMPLE_RATIO,
+	})
+	if err != nil {
+		logger.Fatalw("Failed to set up tracing", "error", err)
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			log.Errorw("Failed to flush traces", "error", err)
+		}
+	}()
+
+	db, err := tracing.ConnectPostgres(cfg.DATABASE_URL)
+	if err != nil {
+		logger.Fatalw("Failed to connect to db", "error", err)
+	}
+	defer db.Close()
+
+	redisClient, err := redis.NewRedisClient(cfg.REDIS_URL)
+	if err != nil {
+		logger.Fatalw("Failed to connect to Redis", "error", err)
+	}
+	defer redisClient.Close()
+
+	f, err := os.Open(*file)
+	if err != nil {
+		logger.Fatalw("Failed to open import file", "error", err, "file", *file)
+	}
+	defer f.Close()
+
+	airportRepo := repository.NewAirportRepository(db)
+	limiter := client.NewRateLimiter(log, "aviationapi", redisClient, client.RateLimitOptionsFromConfig("aviationapi", cfg))
+	httpClient := client.NewResilientClient(log, client.NewRateLimitedClient(log, client.NewHTTPClient(http.DefaultClient, "aviationapi"), "aviationapi", limiter), client.OptionsFromConfig("aviationapi", cfg))
+	airportService := service.NewAirportService(log, airportRepo, cfg, httpClient, redisClient)
+	airportImportService := service.NewAirportImportService(log, airportRepo, airportService, utils.NewAirportValidator())
+
+	ctx := utils.WithActor(context.Background(), dto.ActorImport)
+	result, err := airportImportService.Import(ctx, *format, f)
+	if err != nil {
+		logger.Fatalw("Failed to import airports", "error", err, "file", *file)
+	}
+
+	for _, rowErr := range result.Errors {
+		log.Warnw("Airport row not imported", "row", rowErr.Row, "icao", rowErr.ICAO, "error", rowErr.Error)
+	}
+	log.Infow("Import finished", "file", *file, "format", result.Format, "total", result.Total, "created", result.Created,
+		"updated", result.Updated, "skipped", result.Skipped, "failed", result.Failed)
+}
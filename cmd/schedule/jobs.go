@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"aviation-service/config"
+	"aviation-service/internal/dto"
+	"aviation-service/internal/service"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+)
+
+// job is a named task of the scheduler, run on its cron spec or once with --once. An empty spec disables the job
+type job struct {
+	name    string
+	spec    string
+	timeout time.Duration
+	run     func(ctx context.Context) error
+}
+
+func newJobs(cfg config.Config, log *zap.SugaredLogger, aviationSyncService *service.AviationSyncService, airportService *service.AirportService) []job {
+	return []job{
+		{
+			name:    "sync",
+			spec:    cfg.SCHEDULE_SYNC_CRON,
+			timeout: cfg.SCHEDULE_SYNC_TIMEOUT,
+			run:     syncJob(log, aviationSyncService, strings.ToUpper(cfg.SCHEDULE_SYNC_MODE)),
+		},
+		{
+			// DONE airports older than SYNC_STALE_MAX_AGE are refreshed
+			name:    "stale-refresh",
+			spec:    cfg.SCHEDULE_STALE_REFRESH_CRON,
+			timeout: cfg.SCHEDULE_STALE_REFRESH_TIMEOUT,
+			run:     syncJob(log, aviationSyncService, dto.SyncModeStale),
+		},
+		{
+			name:    "cache-warm",
+			spec:    cfg.SCHEDULE_CACHE_WARM_CRON,
+			timeout: cfg.SCHEDULE_CACHE_WARM_TIMEOUT,
+			run: func(ctx context.Context) error {
+				_, err := airportService.WarmCache(ctx)
+				return err
+			},
+		},
+	}
+}
+
+func findJob(jobs []job, name string) (job, bool) {
+	for _, j := range jobs {
+		if j.name == name {
+			return j, true
+		}
+	}
+	return job{}, false
+}
+
+func syncJob(log *zap.SugaredLogger, aviationSyncService *service.AviationSyncService, mode string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		syncResponse, err := aviationSyncService.Sync(ctx, dto.SyncOptions{Trigger: dto.SyncTriggerCron, Mode: mode})
+		var inProgress *service.SyncInProgressError
+		if errors.As(err, &inProgress) {
+			log.Infow("Skipping sync, another sync is in progress", "error", err, "mode", mode)
+			return nil
+		}
+		if err != nil {
+			if syncResponse != nil {
+				log.Warnw("Sync stopped before its last batch", "sync", syncResponse, "error", err)
+			}
+			return err
+		}
+
+		if syncResponse == nil {
+			log.Infow("No airport data to sync", "mode", mode)
+		} else {
+			log.Infow("Sync airport data successfully", "sync", syncResponse)
+		}
+		return nil
+	}
+}
+
+// execute runs the job under its timeout, ctx is cancelled on shutdown so an in-flight sync stops starting batches.
+// Every run is the root span of its own trace
+func (j job) execute(ctx context.Context, log *zap.SugaredLogger) error {
+	ctx, cancel := context.WithTimeout(ctx, j.timeout)
+	defer cancel()
+	ctx, span := otel.Tracer("aviation-service/cmd/schedule").Start(ctx, "job "+j.name)
+	defer span.End()
+
+	start := time.Now()
+	log.Infow("Running job", "job", j.name, "trace_id", span.SpanContext().TraceID().String())
+	if err := j.run(ctx); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		log.Errorw("Job failed", "job", j.name, "error", err, "duration", time.Since(start))
+		return err
+	}
+	log.Infow("Job finished", "job", j.name, "duration", time.Since(start))
+	return nil
+}
+
+// cronLogger reports the cron internals through zap, its routine messages are debug only
+type cronLogger struct {
+	log *zap.SugaredLogger
+}
+
+func (l cronLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.log.Debugw(msg, keysAndValues...)
+}
+
+func (l cronLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.log.Errorw(msg, append(keysAndValues, "error", err)...)
+}
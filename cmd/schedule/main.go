@@ -1,23 +1,39 @@
 package main
 
 import (
-    "context"
-    "time"
-    "net/http"
+	"context"
+	"flag"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 
-    "github.com/robfig/cron/v3"
-    "github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/robfig/cron/v3"
 
 	"aviation-service/config"
+	"aviation-service/internal/client"
 	"aviation-service/internal/repository"
 	"aviation-service/internal/service"
-	
+
 	"aviation-service/pkg/logger"
+	"aviation-service/pkg/redis"
+	"aviation-service/pkg/tracing"
 )
 
+// Spans still buffered when the command exits get this long to reach the collector
+const tracingFlushTimeout = 5 * time.Second
+
 func main() {
-    cfg, err := config.Load()
+	once := flag.Bool("once", false, "Run a single job now and exit instead of scheduling the jobs")
+	jobName := flag.String("job", "sync", "Job run with --once: sync, stale-refresh or cache-warm")
+	flag.Parse()
+
+	// SIGTERM cancels running jobs, an in-flight sync stops starting batches and hands its airports back
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load()
 	if err != nil {
 		logger.Fatalw("Failed to load configuration", "error", err)
 	}
@@ -25,36 +41,79 @@ func main() {
 	log := logger.GetLogger()
 	defer log.Sync()
 
-	db, err := sqlx.Connect("postgres", cfg.DATABASE_URL)
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options{
+		ServiceName:  "aviation-schedule",
+		Exporter:     cfg.TRACING_EXPORTER,
+		OTLPEndpoint: cfg.TRACING_OTLP_ENDPOINT,
+		SampleRatio:  cfg.TRACING_SAMPLE_RATIO,
+	})
+	if err != nil {
+		logger.Fatalw("Failed to set up tracing", "error", err)
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			log.Errorw("Failed to flush traces", "error", err)
+		}
+	}()
+
+	db, err := tracing.ConnectPostgres(cfg.DATABASE_URL)
 	if err != nil {
 		logger.Fatalw("Failed to connect to db", "error", err)
 	}
 	defer db.Close()
 
-    airportRepo := repository.NewAirportRepository(db)
-    httpClient := http.DefaultClient
-    airportService := service.NewAirportService(log, airportRepo, cfg, httpClient, nil)
-    aviationSyncService := service.NewAviationSyncService(log, airportRepo, airportService)
-
-    c := cron.New()
-    c.AddFunc("0 5 * * *", func() {
-        ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
-        defer cancel()
-
-        syncResponse, err := aviationSyncService.Sync(ctx)
-        if err != nil {
-            log.Errorw("Failed to sync", "error", err)
-            return
-        }
-
-        if syncResponse == nil {
-            log.Info("No airport data to sync")
-        } else {
-            log.Infow("Sync airport data successfully", "sync", syncResponse)
-        }
-    })
-
-    log.Info("Starting aviation sync cron scheduler")
-    c.Start()
-    select {}
+	redisClient, err := redis.NewRedisClient(cfg.REDIS_URL)
+	if err != nil {
+		logger.Fatalw("Failed to connect to Redis", "error", err)
+	}
+	defer redisClient.Close()
+
+	airportRepo := repository.NewAirportRepository(db)
+	limiter := client.NewRateLimiter(log, "aviationapi", redisClient, client.RateLimitOptionsFromConfig("aviationapi", cfg))
+	httpClient := client.NewResilientClient(log, client.NewRateLimitedClient(log, client.NewHTTPClient(http.DefaultClient, "aviationapi"), "aviationapi", limiter), client.OptionsFromConfig("aviationapi", cfg))
+	airportService := service.NewAirportService(log, airportRepo, cfg, httpClient, redisClient)
+	syncRunRepo := repository.NewSyncRunRepository(db)
+	conflictRepo := repository.NewAirportConflictRepository(db)
+	aviationSyncService := service.NewAviationSyncService(log, airportRepo, syncRunRepo, conflictRepo, airportService, redisClient, service.SyncPolicyFromConfig(cfg))
+
+	jobs := newJobs(cfg, log, aviationSyncService, airportService)
+	if *once {
+		j, ok := findJob(jobs, *jobName)
+		if !ok {
+			logger.Fatalw("Unknown job", "job", *jobName)
+		}
+		if err := j.execute(ctx, log); err != nil {
+			logger.Fatalw("Failed to run job", "error", err, "job", j.name)
+		}
+		return
+	}
+
+	location, _ := time.LoadLocation(cfg.SCHEDULE_TIMEZONE)
+	c := cron.New(
+		cron.WithLocation(location),
+		cron.WithLogger(cronLogger{log: log}),
+		// A job still running when its next run is due skips that run
+		cron.WithChain(cron.SkipIfStillRunning(cronLogger{log: log})),
+	)
+	for _, j := range jobs {
+		if j.spec == "" {
+			log.Infow("Job disabled", "job", j.name)
+			continue
+		}
+		if _, err := c.AddFunc(j.spec, func() { j.execute(ctx, log) }); err != nil {
+			logger.Fatalw("Invalid job schedule", "error", err, "job", j.name, "schedule", j.spec)
+		}
+		log.Infow("Job scheduled", "job", j.name, "schedule", j.spec, "timezone", cfg.SCHEDULE_TIMEZONE, "timeout", j.timeout)
+	}
+
+	log.Info("Starting aviation sync cron scheduler")
+	c.Start()
+	<-ctx.Done()
+
+	// Running jobs see the cancelled context, the scheduler waits for them to wind down
+	log.Info("Stopping aviation sync cron scheduler")
+	<-c.Stop().Done()
+	log.Info("Aviation sync cron scheduler stopped")
 }
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"net/http"
+	"os/signal"
+	"syscall"
 
-	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"aviation-service/config"
+	"aviation-service/internal/client"
 	"aviation-service/internal/handler"
 	"aviation-service/internal/repository"
 	"aviation-service/internal/service"
@@ -14,7 +18,9 @@ import (
 	
 	"aviation-service/pkg/httpserver"
 	"aviation-service/pkg/logger"
+	"aviation-service/pkg/middleware"
 	"aviation-service/pkg/redis"
+	"aviation-service/pkg/tracing"
 )
 
 func main() {
@@ -26,7 +32,17 @@ func main() {
 	log := logger.GetLogger()
 	defer log.Sync()
 
-	db, err := sqlx.Connect("postgres", cfg.DATABASE_URL)
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options{
+		ServiceName:  "aviation-service",
+		Exporter:     cfg.TRACING_EXPORTER,
+		OTLPEndpoint: cfg.TRACING_OTLP_ENDPOINT,
+		SampleRatio:  cfg.TRACING_SAMPLE_RATIO,
+	})
+	if err != nil {
+		logger.Fatalw("Failed to set up tracing", "error", err)
+	}
+
+	db, err := tracing.ConnectPostgres(cfg.DATABASE_URL)
 	if err != nil {
 		logger.Fatalw("Failed to connect to db", "error", err)
 	}
@@ -39,27 +55,111 @@ func main() {
 	defer redisClient.Close()
 
 	airportRepo := repository.NewAirportRepository(db)
-	client := http.DefaultClient
-
-	airportService := service.NewAirportService(log, airportRepo, cfg, client, redisClient)
-	aviationSyncService := service.NewAviationSyncService(log, airportRepo, airportService)
-	weatherService := service.NewWeatherService(log, cfg, client, redisClient)
-	airportWeatherService := service.NewAirportWeatherService(log, airportService, weatherService)
+	syncRunRepo := repository.NewSyncRunRepository(db)
+	conflictRepo := repository.NewAirportConflictRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	aviationLimiter := client.NewRateLimiter(log, "aviationapi", redisClient, client.RateLimitOptionsFromConfig("aviationapi", cfg))
+	weatherLimiter := client.NewRateLimiter(log, "weatherapi", redisClient, client.RateLimitOptionsFromConfig("weatherapi", cfg))
+	aviationClient := client.NewResilientClient(log, client.NewRateLimitedClient(log, client.NewInstrumentedClient(client.NewHTTPClient(http.DefaultClient, "aviationapi"), "aviationapi"), "aviationapi", aviationLimiter), client.OptionsFromConfig("aviationapi", cfg))
+	weatherClient := client.NewResilientClient(log, client.NewRateLimitedClient(log, client.NewInstrumentedClient(client.NewHTTPClient(http.DefaultClient, "weatherapi"), "weatherapi"), "weatherapi", weatherLimiter), client.OptionsFromConfig("weatherapi", cfg))
 
+	airportService := service.NewAirportService(log, airportRepo, cfg, aviationClient, redisClient)
+	aviationSyncService := service.NewAviationSyncService(log, airportRepo, syncRunRepo, conflictRepo, airportService, redisClient, service.SyncPolicyFromConfig(cfg))
+	weatherService := service.NewWeatherService(log, cfg, weatherClient, redisClient)
+	aviationWeatherService := service.NewAviationWeatherService(log, cfg, aviationClient, redisClient)
+	airportWeatherService := service.NewAirportWeatherService(log, airportRepo, airportService, weatherService, aviationWeatherService)
 	airportValidator := utils.NewAirportValidator()
+	airportImportService := service.NewAirportImportService(log, airportRepo, airportService, airportValidator)
+	airportExportService := service.NewAirportExportService(log, airportRepo)
+	airportConflictService := service.NewAirportConflictService(log, conflictRepo, airportRepo, airportService)
+	apiKeyService := service.NewAPIKeyService(log, apiKeyRepo)
+
+	authenticators := []middleware.Authenticator{apiKeyService}
+	if jwtOptions := service.JWTOptionsFromConfig(cfg); jwtOptions.Enabled() {
+		jwtAuthenticator, err := service.NewJWTAuthenticator(context.Background(), log, client.NewHTTPClient(http.DefaultClient, "jwks"), jwtOptions)
+		if err != nil {
+			logger.Fatalw("Failed to load JWKS", "error", err)
+		}
+		authenticators = append(authenticators, jwtAuthenticator)
+	}
+	if cfg.AUTH_ANONYMOUS_ROLE != "" {
+		log.Warnw("Anonymous access enabled, requests without credentials are let through", "role", cfg.AUTH_ANONYMOUS_ROLE)
+	}
+
 	airportHandler := handler.NewAirportHandler(log, airportService, airportValidator)
 	aviationSyncHandler := handler.NewAviationSyncHandler(log, aviationSyncService)
 	weatherHandler := handler.NewWeatherHandler(log, weatherService)
 	airportWeatherHandler := handler.NewAirportWeatherHandler(log, airportWeatherService)
+	aviationWeatherHandler := handler.NewAviationWeatherHandler(log, aviationWeatherService)
+	airportImportHandler := handler.NewAirportImportHandler(log, airportImportService)
+	airportExportHandler := handler.NewAirportExportHandler(log, airportExportService)
+	airportConflictHandler := handler.NewAirportConflictHandler(log, airportConflictService)
+
+	healthHandler := handler.NewHealthHandler(log, []handler.HealthCheck{
+		{Name: "postgres", Check: db.PingContext},
+		{Name: "redis", Check: func(ctx context.Context) error { return redisClient.Ping(ctx).Err() }},
+	}, map[string]handler.CircuitReporter{
+		"aviationapi": aviationClient,
+		"weatherapi":  weatherClient,
+	})
+
+	prometheus.MustRegister(service.NewSyncMetrics(log, airportRepo, syncRunRepo))
+
+	routeCosts, err := middleware.ParseRouteCosts(cfg.RATE_LIMIT_ROUTE_COSTS)
+	if err != nil {
+		logger.Fatalw("Failed to load configuration", "error", err)
+	}
 
 	router := httpserver.NewRouter(
+		middleware.Authenticate(cfg.AUTH_ANONYMOUS_ROLE, authenticators...),
+		middleware.RateLimit(redisClient, middleware.RateLimitOptions{
+			Rate:              cfg.RATE_LIMIT_RATE,
+			Burst:             cfg.RATE_LIMIT_BURST,
+			Costs:             routeCosts,
+			TrustForwardedFor: cfg.RATE_LIMIT_TRUST_FORWARDED_FOR,
+		}),
+		healthHandler,
+		handler.NewMetricsHandler(),
 		airportHandler,
 		aviationSyncHandler,
 		weatherHandler,
 		airportWeatherHandler,
+		aviationWeatherHandler,
+		airportImportHandler,
+		airportExportHandler,
+		airportConflictHandler,
 	)
 
-	server := httpserver.NewServer(router, "8000")
-	logger.Info("Starting HTTP server")
-	server.Start()
+	server := httpserver.NewServer(router, cfg.SERVER_PORT, httpserver.Timeouts{
+		Read:       cfg.SERVER_READ_TIMEOUT,
+		ReadHeader: cfg.SERVER_READ_HEADER_TIMEOUT,
+		Write:      cfg.SERVER_WRITE_TIMEOUT,
+		Idle:       cfg.SERVER_IDLE_TIMEOUT,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		logger.Infow("Starting HTTP server", "port", cfg.SERVER_PORT)
+		if err := server.Start(); err != nil {
+			logger.Fatalw("HTTP server failed", "error", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	log.Infow("Shutting down HTTP server", "timeout", cfg.SERVER_SHUTDOWN_TIMEOUT)
+	healthHandler.Drain()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.SERVER_SHUTDOWN_TIMEOUT)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Errorw("Failed to drain in-flight requests", "error", err)
+	}
+	aviationSyncService.Shutdown(shutdownCtx)
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Errorw("Failed to flush traces", "error", err)
+	}
+	log.Info("HTTP server stopped")
 }
@@ -1,6 +1,10 @@
 package config
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -10,6 +14,60 @@ type Config struct {
 	AIRPORT_API_URL string
 	WEATHER_API_URL string
 	WEATHER_API_KEY string
+
+	UPSTREAM_MAX_RETRIES int
+	UPSTREAM_RETRY_BASE_DELAY time.Duration
+	UPSTREAM_RETRY_MAX_DELAY time.Duration
+	UPSTREAM_BREAKER_FAILURE_THRESHOLD int
+	UPSTREAM_BREAKER_OPEN_TIMEOUT time.Duration
+	UPSTREAM_RATE_LIMIT_SHARED bool
+	AIRPORT_API_RATE_LIMIT float64
+	AIRPORT_API_RATE_BURST int
+	WEATHER_API_RATE_LIMIT float64
+	WEATHER_API_RATE_BURST int
+
+	SYNC_MERGE_POLICY string
+	SYNC_STALE_MAX_AGE time.Duration
+	SYNC_MAX_ATTEMPTS int
+	SYNC_RETRY_BASE_DELAY time.Duration
+	SYNC_RETRY_MAX_DELAY time.Duration
+
+	SCHEDULE_TIMEZONE string
+	SCHEDULE_SYNC_CRON string
+	SCHEDULE_SYNC_MODE string
+	SCHEDULE_SYNC_TIMEOUT time.Duration
+	SCHEDULE_STALE_REFRESH_CRON string
+	SCHEDULE_STALE_REFRESH_TIMEOUT time.Duration
+	SCHEDULE_CACHE_WARM_CRON string
+	SCHEDULE_CACHE_WARM_TIMEOUT time.Duration
+
+	SERVER_PORT string
+	SERVER_READ_TIMEOUT time.Duration
+	SERVER_READ_HEADER_TIMEOUT time.Duration
+	SERVER_WRITE_TIMEOUT time.Duration
+	SERVER_IDLE_TIMEOUT time.Duration
+	SERVER_SHUTDOWN_TIMEOUT time.Duration
+
+	TRACING_EXPORTER string
+	TRACING_OTLP_ENDPOINT string
+	TRACING_SAMPLE_RATIO float64
+
+	AUTH_ANONYMOUS_ROLE string
+	AUTH_JWT_JWKS_FILE string
+	AUTH_JWT_JWKS_URL string
+	AUTH_JWT_JWKS_REFRESH_INTERVAL time.Duration
+	AUTH_JWT_ISSUER string
+	AUTH_JWT_AUDIENCE string
+	AUTH_JWT_CLOCK_SKEW time.Duration
+	AUTH_JWT_SCOPE_CLAIM string
+	AUTH_JWT_READER_SCOPE string
+	AUTH_JWT_EDITOR_SCOPE string
+	AUTH_JWT_OPERATOR_SCOPE string
+
+	RATE_LIMIT_RATE float64
+	RATE_LIMIT_BURST int
+	RATE_LIMIT_ROUTE_COSTS string
+	RATE_LIMIT_TRUST_FORWARDED_FOR bool
 }
 
 func Load() (Config, error) {
@@ -18,10 +76,102 @@ func Load() (Config, error) {
 	viper.SetConfigType("env")
 	viper.AddConfigPath(".")
 
+	viper.SetDefault("UPSTREAM_MAX_RETRIES", 3)
+	viper.SetDefault("UPSTREAM_RETRY_BASE_DELAY", 500*time.Millisecond)
+	viper.SetDefault("UPSTREAM_RETRY_MAX_DELAY", 10*time.Second)
+	viper.SetDefault("UPSTREAM_BREAKER_FAILURE_THRESHOLD", 5)
+	viper.SetDefault("UPSTREAM_BREAKER_OPEN_TIMEOUT", 30*time.Second)
+	viper.SetDefault("UPSTREAM_RATE_LIMIT_SHARED", true)
+	viper.SetDefault("AIRPORT_API_RATE_LIMIT", 5.0)
+	viper.SetDefault("AIRPORT_API_RATE_BURST", 10)
+	viper.SetDefault("WEATHER_API_RATE_LIMIT", 5.0)
+	viper.SetDefault("WEATHER_API_RATE_BURST", 10)
+	viper.SetDefault("SYNC_MERGE_POLICY", "manual-wins")
+	viper.SetDefault("SYNC_STALE_MAX_AGE", 30*24*time.Hour)
+	viper.SetDefault("SYNC_MAX_ATTEMPTS", 5)
+	viper.SetDefault("SYNC_RETRY_BASE_DELAY", 15*time.Minute)
+	viper.SetDefault("SYNC_RETRY_MAX_DELAY", 24*time.Hour)
+	viper.SetDefault("SCHEDULE_TIMEZONE", "UTC")
+	viper.SetDefault("SCHEDULE_SYNC_CRON", "0 5 * * *")
+	viper.SetDefault("SCHEDULE_SYNC_MODE", "pending")
+	viper.SetDefault("SCHEDULE_SYNC_TIMEOUT", 20*time.Minute)
+	viper.SetDefault("SCHEDULE_STALE_REFRESH_CRON", "0 6 * * 0")
+	viper.SetDefault("SCHEDULE_STALE_REFRESH_TIMEOUT", time.Hour)
+	viper.SetDefault("SCHEDULE_CACHE_WARM_CRON", "30 5 * * *")
+	viper.SetDefault("SCHEDULE_CACHE_WARM_TIMEOUT", 5*time.Minute)
+	viper.SetDefault("SERVER_PORT", "8000")
+	viper.SetDefault("SERVER_READ_TIMEOUT", 60*time.Second)
+	viper.SetDefault("SERVER_READ_HEADER_TIMEOUT", 5*time.Second)
+	viper.SetDefault("SERVER_WRITE_TIMEOUT", 60*time.Second)
+	viper.SetDefault("SERVER_IDLE_TIMEOUT", 120*time.Second)
+	viper.SetDefault("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second)
+	viper.SetDefault("TRACING_EXPORTER", "none")
+	viper.SetDefault("TRACING_OTLP_ENDPOINT", "")
+	viper.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+	viper.SetDefault("AUTH_ANONYMOUS_ROLE", "")
+	viper.SetDefault("AUTH_JWT_JWKS_FILE", "")
+	viper.SetDefault("AUTH_JWT_JWKS_URL", "")
+	viper.SetDefault("AUTH_JWT_JWKS_REFRESH_INTERVAL", time.Hour)
+	viper.SetDefault("AUTH_JWT_ISSUER", "")
+	viper.SetDefault("AUTH_JWT_AUDIENCE", "")
+	viper.SetDefault("AUTH_JWT_CLOCK_SKEW", time.Minute)
+	viper.SetDefault("AUTH_JWT_SCOPE_CLAIM", "scope")
+	viper.SetDefault("AUTH_JWT_READER_SCOPE", "aviation:read")
+	viper.SetDefault("AUTH_JWT_EDITOR_SCOPE", "aviation:write")
+	viper.SetDefault("AUTH_JWT_OPERATOR_SCOPE", "aviation:operate")
+	viper.SetDefault("RATE_LIMIT_RATE", 10.0)
+	viper.SetDefault("RATE_LIMIT_BURST", 60)
+	viper.SetDefault("RATE_LIMIT_ROUTE_COSTS", "")
+	viper.SetDefault("RATE_LIMIT_TRUST_FORWARDED_FOR", false)
+
 	if err := viper.ReadInConfig(); err != nil {
 		return config, err
 	}
 
-	err := viper.Unmarshal(&config)
-	return config, err
-}
\ No newline at end of file
+	if err := viper.Unmarshal(&config); err != nil {
+		return config, err
+	}
+
+	switch config.SYNC_MERGE_POLICY {
+	case "upstream-wins", "manual-wins", "flag-conflict":
+	default:
+		return config, fmt.Errorf("Invalid SYNC_MERGE_POLICY %q (expected upstream-wins, manual-wins or flag-conflict)", config.SYNC_MERGE_POLICY)
+	}
+	if config.SYNC_MAX_ATTEMPTS < 1 {
+		return config, fmt.Errorf("Invalid SYNC_MAX_ATTEMPTS %d (expected at least 1)", config.SYNC_MAX_ATTEMPTS)
+	}
+	switch strings.ToLower(config.SCHEDULE_SYNC_MODE) {
+	case "pending", "stale", "failed":
+	default:
+		return config, fmt.Errorf("Invalid SCHEDULE_SYNC_MODE %q (expected pending, stale or failed)", config.SCHEDULE_SYNC_MODE)
+	}
+	if _, err := time.LoadLocation(config.SCHEDULE_TIMEZONE); err != nil {
+		return config, fmt.Errorf("Invalid SCHEDULE_TIMEZONE %q: %s", config.SCHEDULE_TIMEZONE, err)
+	}
+	switch config.TRACING_EXPORTER {
+	case "none", "stdout", "otlp":
+	default:
+		return config, fmt.Errorf("Invalid TRACING_EXPORTER %q (expected none, stdout or otlp)", config.TRACING_EXPORTER)
+	}
+	if config.TRACING_SAMPLE_RATIO < 0 || config.TRACING_SAMPLE_RATIO > 1 {
+		return config, fmt.Errorf("Invalid TRACING_SAMPLE_RATIO %v (expected between 0 and 1)", config.TRACING_SAMPLE_RATIO)
+	}
+	switch config.AUTH_ANONYMOUS_ROLE {
+	case "", "reader", "editor", "operator":
+	default:
+		return config, fmt.Errorf("Invalid AUTH_ANONYMOUS_ROLE %q (expected empty, reader, editor or operator)", config.AUTH_ANONYMOUS_ROLE)
+	}
+	if config.AUTH_JWT_JWKS_FILE != "" && config.AUTH_JWT_JWKS_URL != "" {
+		return config, fmt.Errorf("Set either AUTH_JWT_JWKS_FILE or AUTH_JWT_JWKS_URL, not both")
+	}
+	if (config.AUTH_JWT_JWKS_FILE != "" || config.AUTH_JWT_JWKS_URL != "") && (config.AUTH_JWT_ISSUER == "" || config.AUTH_JWT_AUDIENCE == "") {
+		return config, fmt.Errorf("AUTH_JWT_ISSUER and AUTH_JWT_AUDIENCE are required with a JWKS")
+	}
+	if config.AUTH_JWT_JWKS_URL != "" && config.AUTH_JWT_JWKS_REFRESH_INTERVAL < time.Minute {
+		return config, fmt.Errorf("Invalid AUTH_JWT_JWKS_REFRESH_INTERVAL %s (expected at least 1m)", config.AUTH_JWT_JWKS_REFRESH_INTERVAL)
+	}
+	if config.RATE_LIMIT_RATE > 0 && config.RATE_LIMIT_BURST < 1 {
+		return config, fmt.Errorf("Invalid RATE_LIMIT_BURST %d (expected at least 1)", config.RATE_LIMIT_BURST)
+	}
+	return config, nil
+}
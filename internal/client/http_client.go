@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	neturl "net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "aviation-service/internal/client"
+
+// HTTPClient sends the requests of an upstream, it is the innermost client of the chain. Every request gets a client span
+// and carries the trace context upstream. The span leaves the query string out, WeatherAPI takes its key there
+type HTTPClient struct {
+	client *http.Client
+	name   string
+	tracer trace.Tracer
+}
+
+func NewHTTPClient(client *http.Client, name string) *HTTPClient {
+	return &HTTPClient{client: client, name: name, tracer: otel.Tracer(tracerName)}
+}
+
+func (c *HTTPClient) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := c.tracer.Start(ctx, "GET "+c.name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", http.MethodGet),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("url.path", req.URL.Path),
+			attribute.String("upstream", c.name),
+		),
+	)
+	defer span.End()
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// *url.Error repeats the whole URL, the span only keeps the cause
+		cause := err
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			cause = urlErr.Err
+		}
+		span.RecordError(cause)
+		span.SetStatus(codes.Error, cause.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
@@ -0,0 +1,56 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "aviation-service/internal/client"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHTTPClient_Get(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	resp, err := NewHTTPClient(server.Client(), "weatherapi").Get(ctx, server.URL+"/current.json?key=secret&q=KAVL")
+	parent.End()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected the client span and its parent, got %d spans", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET weatherapi" || span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("Expected span GET weatherapi under the parent, got %s under %s", span.Name(), span.Parent().SpanID())
+	}
+	if traceparent == "" || span.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("Expected the trace context sent upstream, got traceparent %q", traceparent)
+	}
+	for _, attr := range span.Attributes() {
+		if attr.Key == "url.path" && attr.Value.AsString() != "/current.json" {
+			t.Errorf("Expected url.path without the query, got %s", attr.Value.AsString())
+		}
+		if attr.Key == "http.response.status_code" && attr.Value.AsInt64() != http.StatusTooManyRequests {
+			t.Errorf("Expected status code %d, got %d", http.StatusTooManyRequests, attr.Value.AsInt64())
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"aviation-service/pkg/metrics"
+)
+
+// InstrumentedClient records the latency and failures of every request sent to an upstream. It sits below the
+// ResilientClient, so each retry is measured on its own
+type InstrumentedClient struct {
+	client Client
+	name   string
+}
+
+func NewInstrumentedClient(client Client, name string) *InstrumentedClient {
+	return &InstrumentedClient{client: client, name: name}
+}
+
+func (c *InstrumentedClient) Get(ctx context.Context, url string) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.client.Get(ctx, url)
+	elapsed := time.Since(start).Seconds()
+	if err != nil {
+		metrics.UpstreamRequestDuration.WithLabelValues(c.name, "error").Observe(elapsed)
+		metrics.UpstreamErrors.WithLabelValues(c.name, "network").Inc()
+		return nil, err
+	}
+
+	metrics.UpstreamRequestDuration.WithLabelValues(c.name, strconv.Itoa(resp.StatusCode)).Observe(elapsed)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		metrics.UpstreamErrors.WithLabelValues(c.name, statusClass(resp.StatusCode)).Inc()
+	}
+	return resp, nil
+}
+
+// statusClass keeps 429 apart from the other 4xx, it means the upstream quota ran out
+func statusClass(code int) string {
+	if code == http.StatusTooManyRequests {
+		return "429"
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
@@ -0,0 +1,37 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "aviation-service/internal/client"
+	"aviation-service/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestInstrumentedClient_Get(t *testing.T) {
+	upstream := &fakeClient{responses: []fakeResponse{
+		{statusCode: 200},
+		{statusCode: 429},
+		{statusCode: 503},
+		{err: errors.New("connection reset")},
+	}}
+	c := NewInstrumentedClient(upstream, "instrumented")
+
+	for i := 0; i < 4; i++ {
+		if resp, err := c.Get(context.Background(), "http://upstream"); err == nil {
+			resp.Body.Close()
+		}
+	}
+
+	if got := testutil.CollectAndCount(metrics.UpstreamRequestDuration.MustCurryWith(map[string]string{"upstream": "instrumented"})); got != 4 {
+		t.Errorf("Expected latency observed by 4 status codes, got %d", got)
+	}
+	for reason, expected := range map[string]float64{"429": 1, "5xx": 1, "network": 1, "2xx": 0} {
+		if got := testutil.ToFloat64(metrics.UpstreamErrors.WithLabelValues("instrumented", reason)); got != expected {
+			t.Errorf("Expected %v %s errors, got %v", expected, reason, got)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"aviation-service/config"
+	"aviation-service/pkg/redis"
+
+	"go.uber.org/zap"
+)
+
+// Limiter hands out upstream request slots, Reserve takes the next slot and returns how long the caller must wait for it
+type Limiter interface {
+	Reserve(ctx context.Context) (time.Duration, error)
+}
+
+type RateLimitOptions struct {
+	// Rate is the sustained number of requests per second, zero or less disables the limiter
+	Rate  float64
+	Burst int
+	// Shared coordinates the bucket through Redis so every replica draws from the same budget
+	Shared bool
+}
+
+func RateLimitOptionsFromConfig(name string, cfg config.Config) RateLimitOptions {
+	opts := RateLimitOptions{Shared: cfg.UPSTREAM_RATE_LIMIT_SHARED}
+	switch name {
+	case "aviationapi":
+		opts.Rate, opts.Burst = cfg.AIRPORT_API_RATE_LIMIT, cfg.AIRPORT_API_RATE_BURST
+	case "weatherapi":
+		opts.Rate, opts.Burst = cfg.WEATHER_API_RATE_LIMIT, cfg.WEATHER_API_RATE_BURST
+	}
+	return opts
+}
+
+// NewRateLimiter builds the limiter of an upstream, a local token bucket or one kept in Redis that falls back to the local
+// bucket while Redis is unavailable. It returns nil when the rate limit is disabled
+func NewRateLimiter(logger *zap.SugaredLogger, name string, redisClient redis.RedisClient, opts RateLimitOptions) Limiter {
+	if opts.Rate <= 0 {
+		return nil
+	}
+	local := NewTokenBucket(opts.Rate, opts.Burst)
+	if !opts.Shared || redisClient == nil {
+		return local
+	}
+	return &RedisTokenBucket{
+		logger:   logger,
+		client:   redisClient,
+		key:      "ratelimit:" + name,
+		rate:     opts.Rate,
+		burst:    local.burst,
+		fallback: local,
+	}
+}
+
+// TokenBucket refills rate tokens per second up to burst. A reservation may take a token that is not there yet,
+// callers then wait their turn in the order they reserved
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{rate: rate, burst: burst, tokens: float64(burst), last: time.Now()}
+}
+
+func (b *TokenBucket) Reserve(ctx context.Context) (time.Duration, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = math.Min(float64(b.burst), b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0, nil
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second)), nil
+}
+
+// RedisTokenBucket reserves from a token bucket kept in Redis, shared by every replica
+type RedisTokenBucket struct {
+	logger   *zap.SugaredLogger
+	client   redis.RedisClient
+	key      string
+	rate     float64
+	burst    int
+	fallback *TokenBucket
+}
+
+func (b *RedisTokenBucket) Reserve(ctx context.Context) (time.Duration, error) {
+	bucket, err := redis.TakeTokens(ctx, b.client, b.key, b.rate, b.burst, 1, true)
+	if err != nil {
+		b.logger.Warnw("Failed to reserve shared rate limit token, using the local bucket", "error", err, "key", b.key)
+		return b.fallback.Reserve(ctx)
+	}
+	return bucket.Wait, nil
+}
+
+// RateLimitedClient waits for a slot of the limiter before every request, wrapped by the ResilientClient each retry takes a slot
+type RateLimitedClient struct {
+	logger  *zap.SugaredLogger
+	client  Client
+	name    string
+	limiter Limiter
+}
+
+// NewRateLimitedClient wraps client with limiter, a nil limiter returns client unchanged
+func NewRateLimitedClient(logger *zap.SugaredLogger, client Client, name string, limiter Limiter) Client {
+	if limiter == nil {
+		return client
+	}
+	return &RateLimitedClient{
+		logger:  logger,
+		client:  client,
+		name:    name,
+		limiter: limiter,
+	}
+}
+
+func (c *RateLimitedClient) Get(ctx context.Context, url string) (*http.Response, error) {
+	wait, err := c.limiter.Reserve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if wait > 0 {
+		c.logger.Debugw("Waiting for upstream rate limit", "upstream", c.name, "wait", wait)
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+	return c.client.Get(ctx, url)
+}
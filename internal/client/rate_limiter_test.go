@@ -0,0 +1,154 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	. "aviation-service/internal/client"
+	. "aviation-service/internal/mock"
+	"aviation-service/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type fakeLimiter struct {
+	wait  time.Duration
+	calls int
+}
+
+func (f *fakeLimiter) Reserve(ctx context.Context) (time.Duration, error) {
+	f.calls++
+	return f.wait, nil
+}
+
+// scriptRedis answers every script with the same wait in milliseconds
+type scriptRedis struct {
+	MockRedis
+	waitMs int64
+	keys   []string
+}
+
+func (r *scriptRedis) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd {
+	r.keys = keys
+	return redis.NewCmdResult([]interface{}{int64(1), "-0.25", r.waitMs}, nil)
+}
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	bucket := NewTokenBucket(10, 2)
+
+	var waits []time.Duration
+	for i := 0; i < 4; i++ {
+		wait, err := bucket.Reserve(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		waits = append(waits, wait)
+	}
+
+	// The burst is free, every further request waits one more tenth of a second
+	expected := []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond}
+	for i, wait := range waits {
+		if wait > expected[i] || wait < expected[i]-10*time.Millisecond {
+			t.Errorf("Expected wait %v for request %d, got %v", expected[i], i+1, wait)
+		}
+	}
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	log := logger.GetLogger()
+	defer log.Sync()
+
+	tests := []struct {
+		name         string
+		redisClient  *scriptRedis
+		failing      bool
+		opts         RateLimitOptions
+		expectNil    bool
+		expectedWait time.Duration
+		expectedKey  string
+	}{
+		{
+			name:      "Disabled",
+			opts:      RateLimitOptions{Rate: 0, Burst: 10, Shared: true},
+			expectNil: true,
+		},
+		{
+			name:         "Local bucket",
+			opts:         RateLimitOptions{Rate: 5, Burst: 1},
+			expectedWait: 0,
+		},
+		{
+			name:         "Shared bucket",
+			redisClient:  &scriptRedis{waitMs: 250},
+			opts:         RateLimitOptions{Rate: 5, Burst: 1, Shared: true},
+			expectedWait: 250 * time.Millisecond,
+			expectedKey:  "ratelimit:weatherapi",
+		},
+		{
+			name:         "Shared bucket falls back to local",
+			failing:      true,
+			opts:         RateLimitOptions{Rate: 5, Burst: 1, Shared: true},
+			expectedWait: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var limiter Limiter
+			switch {
+			case tt.failing:
+				limiter = NewRateLimiter(log, "weatherapi", &MockRedisSetError{}, tt.opts)
+			case tt.redisClient != nil:
+				limiter = NewRateLimiter(log, "weatherapi", tt.redisClient, tt.opts)
+			default:
+				limiter = NewRateLimiter(log, "weatherapi", nil, tt.opts)
+			}
+			if (limiter == nil) != tt.expectNil {
+				t.Fatalf("Expected nil limiter %v, got %v", tt.expectNil, limiter)
+			}
+			if tt.expectNil {
+				return
+			}
+
+			wait, err := limiter.Reserve(context.Background())
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if wait != tt.expectedWait {
+				t.Errorf("Expected wait %v, got %v", tt.expectedWait, wait)
+			}
+			if tt.redisClient != nil && (len(tt.redisClient.keys) != 1 || tt.redisClient.keys[0] != tt.expectedKey) {
+				t.Errorf("Expected bucket key %s, got %v", tt.expectedKey, tt.redisClient.keys)
+			}
+		})
+	}
+}
+
+func TestRateLimitedClient_Get(t *testing.T) {
+	log := logger.GetLogger()
+	defer log.Sync()
+
+	upstream := &fakeClient{responses: []fakeResponse{{statusCode: 200}}}
+	if got := NewRateLimitedClient(log, upstream, "test", nil); got != Client(upstream) {
+		t.Errorf("Expected the client returned unchanged without a limiter, got %v", got)
+	}
+
+	limiter := &fakeLimiter{wait: 20 * time.Millisecond}
+	c := NewRateLimitedClient(log, upstream, "test", limiter)
+
+	start := time.Now()
+	resp, err := c.Get(context.Background(), "http://upstream")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || upstream.calls != 1 || limiter.calls != 1 {
+		t.Errorf("Expected one limited upstream call, got status %d, %d calls and %d reservations", resp.StatusCode, upstream.calls, limiter.calls)
+	}
+	if elapsed := time.Since(start); elapsed < limiter.wait {
+		t.Errorf("Expected the request held back %v, got %v", limiter.wait, elapsed)
+	}
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"aviation-service/config"
+	"aviation-service/pkg/metrics"
+
+	"go.uber.org/zap"
+)
+
+var ErrCircuitOpen = errors.New("Circuit breaker is open")
+
+type Client interface {
+	Get(ctx context.Context, url string) (*http.Response, error)
+}
+
+// StatusError is returned for non-2xx upstream responses, the body is already drained and closed
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Upstream responded with status %d", e.StatusCode)
+}
+
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+type Options struct {
+	Name             string
+	MaxRetries       int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	FailureThreshold int
+	OpenTimeout      time.Duration
+}
+
+func OptionsFromConfig(name string, cfg config.Config) Options {
+	return Options{
+		Name:             name,
+		MaxRetries:       cfg.UPSTREAM_MAX_RETRIES,
+		BaseDelay:        cfg.UPSTREAM_RETRY_BASE_DELAY,
+		MaxDelay:         cfg.UPSTREAM_RETRY_MAX_DELAY,
+		FailureThreshold: cfg.UPSTREAM_BREAKER_FAILURE_THRESHOLD,
+		OpenTimeout:      cfg.UPSTREAM_BREAKER_OPEN_TIMEOUT,
+	}
+}
+
+type ResilientClient struct {
+	logger  *zap.SugaredLogger
+	client  Client
+	opts    Options
+	breaker *CircuitBreaker
+}
+
+func NewResilientClient(logger *zap.SugaredLogger, client Client, opts Options) *ResilientClient {
+	return &ResilientClient{
+		logger:  logger,
+		client:  client,
+		opts:    opts,
+		breaker: NewCircuitBreaker(opts.FailureThreshold, opts.OpenTimeout),
+	}
+}
+
+func (c *ResilientClient) Get(ctx context.Context, url string) (*http.Response, error) {
+	if !c.breaker.Allow() {
+		metrics.UpstreamErrors.WithLabelValues(c.opts.Name, "circuit_open").Inc()
+		return nil, ErrCircuitOpen
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := c.backoff(attempt, lastErr)
+			if delay < 0 {
+				break
+			}
+			c.logger.Infow("Retrying upstream request", "upstream", c.opts.Name, "attempt", attempt, "delay", delay, "error", lastErr)
+			if err := sleep(ctx, delay); err != nil {
+				c.breaker.Cancel()
+				return nil, err
+			}
+		}
+
+		resp, err := c.client.Get(ctx, url)
+		if err != nil {
+			// The caller gave up, that says nothing about the upstream
+			if ctx.Err() != nil {
+				c.breaker.Cancel()
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			c.breaker.Success()
+			return resp, nil
+		}
+
+		statusErr := &StatusError{StatusCode: resp.StatusCode, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if !statusErr.Retryable() {
+			// The upstream answered, it is only the request that was rejected
+			c.breaker.Success()
+			return nil, statusErr
+		}
+		lastErr = statusErr
+	}
+
+	c.breaker.Failure()
+	c.logger.Errorw("Upstream request failed", "upstream", c.opts.Name, "error", lastErr, "circuit", c.breaker.State())
+	return nil, lastErr
+}
+
+func (c *ResilientClient) CircuitState() string {
+	return c.breaker.State()
+}
+
+// backoff returns the delay before the next attempt, or a negative value when Retry-After asks for longer than MaxDelay
+func (c *ResilientClient) backoff(attempt int, lastErr error) time.Duration {
+	var statusErr *StatusError
+	if errors.As(lastErr, &statusErr) && statusErr.RetryAfter > 0 {
+		if statusErr.RetryAfter > c.opts.MaxDelay {
+			return -1
+		}
+		return statusErr.RetryAfter
+	}
+
+	ceiling := c.opts.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > c.opts.MaxDelay {
+		ceiling = c.opts.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	// Full jitter keeps the 10 sync workers from retrying in lockstep
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// sleep waits for d unless ctx is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,207 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	. "aviation-service/internal/client"
+	"aviation-service/pkg/logger"
+)
+
+type fakeResponse struct {
+	statusCode int
+	retryAfter string
+	err        error
+}
+
+type fakeClient struct {
+	responses []fakeResponse
+	calls     int
+}
+
+func (f *fakeClient) Get(ctx context.Context, url string) (*http.Response, error) {
+	response := f.responses[len(f.responses)-1]
+	if f.calls < len(f.responses) {
+		response = f.responses[f.calls]
+	}
+	f.calls++
+
+	if response.err != nil {
+		return nil, response.err
+	}
+	header := http.Header{}
+	if response.retryAfter != "" {
+		header.Set("Retry-After", response.retryAfter)
+	}
+	return &http.Response{
+		StatusCode: response.statusCode,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewBufferString(`{}`)),
+	}, nil
+}
+
+func testOptions() Options {
+	return Options{
+		Name:             "test",
+		MaxRetries:       2,
+		BaseDelay:        time.Millisecond,
+		MaxDelay:         5 * time.Millisecond,
+		FailureThreshold: 2,
+		OpenTimeout:      20 * time.Millisecond,
+	}
+}
+
+func TestResilientClient_Get(t *testing.T) {
+	tests := []struct {
+		name          string
+		responses     []fakeResponse
+		expectedCalls int
+		expectedErr   error
+	}{
+		{
+			name:          "Success first attempt",
+			responses:     []fakeResponse{{statusCode: 200}},
+			expectedCalls: 1,
+		},
+		{
+			name:          "Success after transient errors",
+			responses:     []fakeResponse{{err: fmt.Errorf("connection reset")}, {statusCode: 503}, {statusCode: 200}},
+			expectedCalls: 3,
+		},
+		{
+			name:          "Success after retry after",
+			responses:     []fakeResponse{{statusCode: 429, retryAfter: "0"}, {statusCode: 200}},
+			expectedCalls: 2,
+		},
+		{
+			name:          "Error retries exhausted",
+			responses:     []fakeResponse{{statusCode: 502}},
+			expectedCalls: 3,
+			expectedErr:   &StatusError{StatusCode: 502},
+		},
+		{
+			name:          "Error client status not retried",
+			responses:     []fakeResponse{{statusCode: 404}},
+			expectedCalls: 1,
+			expectedErr:   &StatusError{StatusCode: 404},
+		},
+		{
+			name:          "Error retry after longer than max delay",
+			responses:     []fakeResponse{{statusCode: 429, retryAfter: "120"}},
+			expectedCalls: 1,
+			expectedErr:   &StatusError{StatusCode: 429},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeClient{responses: tt.responses}
+			c := NewResilientClient(log, fake, testOptions())
+
+			resp, err := c.Get(context.Background(), "http://123")
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err == nil && resp.StatusCode != 200 {
+				t.Errorf("Expected status 200, got %d", resp.StatusCode)
+			}
+
+			if fake.calls != tt.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tt.expectedCalls, fake.calls)
+			}
+		})
+	}
+}
+
+func TestResilientClient_CircuitBreaker(t *testing.T) {
+	log := logger.GetLogger()
+	defer log.Sync()
+
+	fake := &fakeClient{responses: []fakeResponse{{statusCode: 503}}}
+	c := NewResilientClient(log, fake, testOptions())
+
+	c.Get(context.Background(), "http://123")
+	c.Get(context.Background(), "http://123")
+	if c.CircuitState() != CircuitOpen {
+		t.Fatalf("Expected circuit %s, got %s", CircuitOpen, c.CircuitState())
+	}
+
+	calls := fake.calls
+	if _, err := c.Get(context.Background(), "http://123"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected error %v, got %v", ErrCircuitOpen, err)
+	}
+	if fake.calls != calls {
+		t.Errorf("Expected no upstream call while open, got %d", fake.calls-calls)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	if c.CircuitState() != CircuitHalfOpen {
+		t.Fatalf("Expected circuit %s, got %s", CircuitHalfOpen, c.CircuitState())
+	}
+
+	fake.responses = []fakeResponse{{statusCode: 200}}
+	if _, err := c.Get(context.Background(), "http://123"); err != nil {
+		t.Errorf("Expected probe to succeed, got %v", err)
+	}
+	if c.CircuitState() != CircuitClosed {
+		t.Errorf("Expected circuit %s, got %s", CircuitClosed, c.CircuitState())
+	}
+}
+
+func TestResilientClient_Cancelled(t *testing.T) {
+	log := logger.GetLogger()
+	defer log.Sync()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fake := &fakeClient{responses: []fakeResponse{{err: context.Canceled}}}
+	c := NewResilientClient(log, fake, testOptions())
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Get(ctx, "http://123"); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected error %v, got %v", context.Canceled, err)
+		}
+	}
+	// A caller giving up is neither retried nor counted against the upstream
+	if fake.calls != 3 || c.CircuitState() != CircuitClosed {
+		t.Errorf("Expected 3 calls and circuit %s, got %d calls and circuit %s", CircuitClosed, fake.calls, c.CircuitState())
+	}
+}
+
+func TestResilientClient_CancelledProbe(t *testing.T) {
+	log := logger.GetLogger()
+	defer log.Sync()
+
+	fake := &fakeClient{responses: []fakeResponse{{statusCode: 503}}}
+	c := NewResilientClient(log, fake, testOptions())
+	c.Get(context.Background(), "http://123")
+	c.Get(context.Background(), "http://123")
+	time.Sleep(25 * time.Millisecond)
+
+	// The probe is abandoned by its caller, a rate limit wait or a disconnect end the same way
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fake.responses = []fakeResponse{{err: context.Canceled}}
+	if _, err := c.Get(ctx, "http://123"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected error %v, got %v", context.Canceled, err)
+	}
+	if c.CircuitState() != CircuitHalfOpen {
+		t.Fatalf("Expected circuit %s after the open timeout, got %s", CircuitHalfOpen, c.CircuitState())
+	}
+
+	fake.responses = []fakeResponse{{statusCode: 200}}
+	if _, err := c.Get(context.Background(), "http://123"); err != nil {
+		t.Errorf("Expected the next probe to be let through, got %v", err)
+	}
+	if c.CircuitState() != CircuitClosed {
+		t.Errorf("Expected circuit %s, got %s", CircuitClosed, c.CircuitState())
+	}
+}
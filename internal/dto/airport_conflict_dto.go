@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	FieldSourceUpstream = "UPSTREAM"
+	FieldSourceManual   = "MANUAL"
+
+	MergePolicyUpstreamWins = "upstream-wins"
+	MergePolicyManualWins   = "manual-wins"
+	MergePolicyFlagConflict = "flag-conflict"
+
+	ConflictResolutionKeepManual     = "keep-manual"
+	ConflictResolutionAcceptUpstream = "accept-upstream"
+)
+
+// FieldSources maps airport JSON field names to FieldSourceUpstream or FieldSourceManual, it is stored as JSONB
+type FieldSources map[string]string
+
+func (f FieldSources) Value() (driver.Value, error) {
+	if f == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(f)
+}
+
+func (f *FieldSources) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, f)
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	case nil:
+		*f = nil
+		return nil
+	default:
+		return errors.New("Invalid field sources value")
+	}
+}
+
+type AirportConflict struct {
+	ID            int        `db:"id" json:"id"`
+	AirportID     int        `db:"airport_id" json:"airport_id"`
+	ICAO          string     `db:"icao" json:"icao_ident"`
+	Field         string     `db:"field" json:"field"`
+	ManualValue   *string    `db:"manual_value" json:"manual_value"`
+	UpstreamValue *string    `db:"upstream_value" json:"upstream_value"`
+	SyncRunID     *int       `db:"sync_run_id" json:"sync_run_id,omitempty"`
+	Resolution    *string    `db:"resolution" json:"resolution,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	ResolvedAt    *time.Time `db:"resolved_at" json:"resolved_at,omitempty"`
+}
+
+type ResolveConflictRequest struct {
+	Resolution string `json:"resolution"`
+}
@@ -1,22 +1,49 @@
 package dto
 
+import "time"
+
+// Airport statuses, the sync moves PENDING airports to SYNCING while a run holds them and then to DONE,
+// to RETRYING with a backoff when AviationAPI had no data or failed, or to FAILED once the attempts are used up
+const (
+	AirportStatusPending  = "PENDING"
+	AirportStatusSyncing  = "SYNCING"
+	AirportStatusDone     = "DONE"
+	AirportStatusRetrying = "RETRYING"
+	AirportStatusFailed   = "FAILED"
+)
+
 type Airport struct {
-	ID           int     `db:"id" json:"id"`
-	Type         *string `db:"type" json:"type,omitempty"`
-	FacilityName *string `db:"facility_name" json:"facility_name,omitempty"`
-	FAA          *string `db:"faa" json:"faa_ident,omitempty"`
-	ICAO         string  `db:"icao" json:"icao_ident"`
-	Region       *string `db:"region" json:"region,omitempty"`
-	State        *string `db:"state" json:"state_full,omitempty"`
-	County       *string `db:"county" json:"county,omitempty"`
-	City         *string `db:"city" json:"city,omitempty"`
-	Ownership    *string `db:"ownership" json:"ownership,omitempty"`
-	Use          *string `db:"use" json:"use,omitempty"`
-	Manager      *string `db:"manager" json:"manager,omitempty"`
-	ManagerPhone *string `db:"manager_phone" json:"manager_phone,omitempty"`
-	Latitude     *string `db:"latitude" json:"latitude,omitempty"`
-	Longitude    *string `db:"longitude" json:"longitude,omitempty"`
-	Status       string  `db:"status" json:"status"`
+	ID           int      `db:"id" json:"id"`
+	Type         *string  `db:"type" json:"type,omitempty"`
+	FacilityName *string  `db:"facility_name" json:"facility_name,omitempty"`
+	FAA          *string  `db:"faa" json:"faa_ident,omitempty"`
+	ICAO         string   `db:"icao" json:"icao_ident"`
+	Region       *string  `db:"region" json:"region,omitempty"`
+	State        *string  `db:"state" json:"state_full,omitempty"`
+	County       *string  `db:"county" json:"county,omitempty"`
+	City         *string  `db:"city" json:"city,omitempty"`
+	Ownership    *string  `db:"ownership" json:"ownership,omitempty"`
+	Use          *string  `db:"use" json:"use,omitempty"`
+	Manager      *string  `db:"manager" json:"manager,omitempty"`
+	ManagerPhone *string  `db:"manager_phone" json:"manager_phone,omitempty"`
+	Latitude     *string  `db:"latitude" json:"latitude,omitempty"`
+	Longitude    *string  `db:"longitude" json:"longitude,omitempty"`
+	LatitudeDeg  *float64 `db:"latitude_deg" json:"latitude_deg,omitempty"`
+	LongitudeDeg *float64 `db:"longitude_deg" json:"longitude_deg,omitempty"`
+	Status       string   `db:"status" json:"status"`
+	// FieldSources records per field whether the value came from AviationAPI or was curated, it is maintained by the repository
+	FieldSources FieldSources `db:"field_sources" json:"field_sources,omitempty"`
+	// LastSyncedAt is set whenever a sync writes the airport, DONE airports older than the stale max age are synced again
+	LastSyncedAt *time.Time `db:"last_synced_at" json:"last_synced_at,omitempty"`
+	// AttemptCount, LastError and NextAttemptAt track the failed sync attempts since the airport was last written successfully
+	AttemptCount  int        `db:"attempt_count" json:"attempt_count,omitempty"`
+	LastError     *string    `db:"last_error" json:"last_error,omitempty"`
+	NextAttemptAt *time.Time `db:"next_attempt_at" json:"next_attempt_at,omitempty"`
+}
+
+type NearbyAirport struct {
+	Airport
+	DistanceNm float64 `db:"distance_nm" json:"distance_nm"`
 }
 
 type AirportDataResponse map[string][]Airport
@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	AirportHistoryActionInsert = "INSERT"
+	AirportHistoryActionUpdate = "UPDATE"
+	AirportHistoryActionDelete = "DELETE"
+
+	ActorAPI         = "api"
+	ActorImport      = "import"
+	ActorAviationAPI = "aviationapi"
+)
+
+type FieldChange struct {
+	Field  string  `json:"field"`
+	Before *string `json:"before"`
+	After  *string `json:"after"`
+}
+
+// FieldChanges is stored as a JSONB array in airport_history.changes
+type FieldChanges []FieldChange
+
+func (c FieldChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(c)
+}
+
+func (c *FieldChanges) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	case nil:
+		*c = nil
+		return nil
+	default:
+		return errors.New("Invalid field changes value")
+	}
+}
+
+type AirportHistory struct {
+	ID        int          `db:"id" json:"id"`
+	AirportID int          `db:"airport_id" json:"airport_id"`
+	ICAO      string       `db:"icao" json:"icao_ident"`
+	Action    string       `db:"action" json:"action"`
+	Actor     string       `db:"actor" json:"actor"`
+	Changes   FieldChanges `db:"changes" json:"changes"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+}
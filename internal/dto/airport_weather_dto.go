@@ -1,7 +1,20 @@
 package dto
 
+const (
+	FlightCategoryVFR  = "VFR"
+	FlightCategoryMVFR = "MVFR"
+	FlightCategoryIFR  = "IFR"
+	FlightCategoryLIFR = "LIFR"
+)
+
+const (
+	FlightCategorySourceMetar      = "METAR"
+	FlightCategorySourceWeatherAPI = "WEATHERAPI"
+)
+
 type AirportWeather struct {
-	Airport Airport  `json:"airport"`
-	Weather *Weather `json:"weather,omitempty"`
+	Airport              Airport  `json:"airport"`
+	Weather              *Weather `json:"weather,omitempty"`
+	FlightCategory       string   `json:"flight_category,omitempty"`
+	FlightCategorySource string   `json:"flight_category_source,omitempty"`
 }
-
@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+type APIKey struct {
+	ID        int        `db:"id" json:"id"`
+	Name      string     `db:"name" json:"name"`
+	Prefix    string     `db:"prefix" json:"prefix"`
+	KeyHash   string     `db:"key_hash" json:"-"`
+	Role      string     `db:"role" json:"role"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	RevokedAt *time.Time `db:"revoked_at" json:"revoked_at,omitempty"`
+}
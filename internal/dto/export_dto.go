@@ -0,0 +1,18 @@
+package dto
+
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatGeoJSON = "geojson"
+	ExportFormatKML     = "kml"
+)
+
+type GeoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type GeoJSONFeature struct {
+	Type       string        `json:"type"`
+	Geometry   *GeoJSONPoint `json:"geometry"`
+	Properties *Airport      `json:"properties"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+const (
+	HealthStatusOK          = "OK"
+	HealthStatusUnavailable = "UNAVAILABLE"
+	HealthStatusDraining    = "DRAINING"
+)
+
+type Readiness struct {
+	Status   string            `json:"status"`
+	Checks   map[string]string `json:"checks,omitempty"`
+	Circuits map[string]string `json:"circuits,omitempty"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatNASR = "nasr"
+	ImportFormatJSON = "json"
+)
+
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	ICAO  string `json:"icao,omitempty"`
+	Error string `json:"error"`
+}
+
+type ImportResult struct {
+	Format  string           `json:"format"`
+	Total   int              `json:"total"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Skipped int              `json:"skipped"`
+	Failed  int              `json:"failed"`
+	Errors  []ImportRowError `json:"errors,omitempty"`
+}
@@ -0,0 +1,89 @@
+package dto
+
+import "time"
+
+const (
+	VisibilityLessThan    = "LESS_THAN"
+	VisibilityGreaterThan = "GREATER_THAN"
+)
+
+const (
+	TafChangeBase  = "BASE"
+	TafChangeFrom  = "FM"
+	TafChangeBecmg = "BECMG"
+	TafChangeTempo = "TEMPO"
+	TafChangeProb  = "PROB"
+)
+
+type Wind struct {
+	DirectionDeg    *int `json:"direction_deg"`
+	Variable        bool `json:"variable"`
+	SpeedKt         int  `json:"speed_kt"`
+	GustKt          *int `json:"gust_kt,omitempty"`
+	VariableFromDeg *int `json:"variable_from_deg,omitempty"`
+	VariableToDeg   *int `json:"variable_to_deg,omitempty"`
+}
+
+type Visibility struct {
+	StatuteMiles float64 `json:"statute_miles"`
+	Meters       int     `json:"meters"`
+	Qualifier    string  `json:"qualifier,omitempty"`
+}
+
+type CloudLayer struct {
+	Cover  string `json:"cover"`
+	BaseFt *int   `json:"base_ft,omitempty"`
+	Type   string `json:"type,omitempty"`
+}
+
+type Altimeter struct {
+	InHg float64 `json:"in_hg"`
+	HPa  float64 `json:"hpa"`
+}
+
+// Conditions holds the groups shared by METAR bodies and TAF forecast periods
+type Conditions struct {
+	Wind       *Wind        `json:"wind,omitempty"`
+	Visibility *Visibility  `json:"visibility,omitempty"`
+	Weather    []string     `json:"weather,omitempty"`
+	Clouds     []CloudLayer `json:"clouds,omitempty"`
+}
+
+type Metar struct {
+	Station    string    `json:"station"`
+	Type       string    `json:"type"`
+	ObservedAt time.Time `json:"observed_at"`
+	Auto       bool      `json:"auto"`
+	Conditions
+	TemperatureC *int       `json:"temperature_c"`
+	DewpointC    *int       `json:"dewpoint_c"`
+	Altimeter    *Altimeter `json:"altimeter,omitempty"`
+	Trend        string     `json:"trend,omitempty"`
+	Remarks      string     `json:"remarks,omitempty"`
+	Raw          string     `json:"raw"`
+}
+
+type TafForecast struct {
+	Change      string     `json:"change"`
+	Probability *int       `json:"probability,omitempty"`
+	From        *time.Time `json:"from,omitempty"`
+	To          *time.Time `json:"to,omitempty"`
+	Conditions
+}
+
+type Taf struct {
+	Station   string        `json:"station"`
+	IssuedAt  time.Time     `json:"issued_at"`
+	ValidFrom time.Time     `json:"valid_from"`
+	ValidTo   time.Time     `json:"valid_to"`
+	Amended   bool          `json:"amended"`
+	Forecasts []TafForecast `json:"forecasts"`
+	Remarks   string        `json:"remarks,omitempty"`
+	Raw       string        `json:"raw"`
+}
+
+// AviationWeatherDataResponse is the body AviationAPI returns for /weather/metar and /weather/taf
+type AviationWeatherDataResponse struct {
+	StationID string `json:"station_id"`
+	Raw       string `json:"raw"`
+}
@@ -1,8 +1,102 @@
 package dto
 
+import "time"
+
+const (
+	SyncTriggerCron = "CRON"
+	SyncTriggerAPI  = "API"
+
+	SyncModePending = "PENDING"
+	SyncModeStale   = "STALE"
+	// SyncModeFailed gives FAILED airports another sync, they stay FAILED when AviationAPI still has no data
+	SyncModeFailed = "FAILED"
+	// SyncModeTargeted syncs the requested ICAOs whatever their status
+	SyncModeTargeted = "TARGETED"
+
+	SyncRunStatusRunning = "RUNNING"
+	SyncRunStatusDone    = "DONE"
+	SyncRunStatusFailed  = "FAILED"
+	// SyncRunStatusCancelled is a run cancelled through its sync job, the batches it did not reach are recorded as CANCELLED
+	SyncRunStatusCancelled = "CANCELLED"
+
+	SyncOutcomeOK          = "OK"
+	SyncOutcomeMissing     = "MISSING"
+	SyncOutcomeFetchError  = "FETCH_ERROR"
+	SyncOutcomeUpdateError = "UPDATE_ERROR"
+	SyncOutcomeCancelled   = "CANCELLED"
+)
+
+type SyncOptions struct {
+	Trigger string
+	// Mode picks the airports to sync, PENDING airports (default) or DONE airports not synced within the stale max age
+	Mode string
+	// ICAOs limits the sync to these airports, it implies SyncModeTargeted
+	ICAOs []string
+}
+
+type SyncRequest struct {
+	ICAOs []string `json:"icaos"`
+}
+
+// AirportRefresh is an airport before and after it was synced on demand
+type AirportRefresh struct {
+	Before *Airport      `json:"before"`
+	After  *Airport      `json:"after"`
+	Sync   *SyncResponse `json:"sync"`
+}
+
 type SyncResponse struct {
-	Total   int `json:"total"`
-	Success int `json:"success"`
-	Failed  int `json:"failed"`
-	Error   int `json:"error"`
+	RunID   int    `json:"run_id,omitempty"`
+	Mode    string `json:"mode,omitempty"`
+	Total   int    `json:"total"`
+	Success int    `json:"success"`
+	Failed  int    `json:"failed"`
+	Error   int    `json:"error"`
+}
+
+// SyncJob is the progress of a sync run, its counters are live while the job runs in the process reporting it
+type SyncJob struct {
+	ID           int        `json:"id"`
+	Trigger      string     `json:"trigger"`
+	Mode         string     `json:"mode"`
+	Status       string     `json:"status"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	Total        int        `json:"total"`
+	Batches      int        `json:"batches"`
+	BatchesDone  int        `json:"batches_done"`
+	Success      int        `json:"success"`
+	Failed       int        `json:"failed"`
+	Error        int        `json:"error"`
+	ErrorMessage *string    `json:"error_message,omitempty"`
+}
+
+type SyncRun struct {
+	ID           int           `db:"id" json:"id"`
+	Trigger      string        `db:"trigger" json:"trigger"`
+	Mode         string        `db:"mode" json:"mode"`
+	Status       string        `db:"status" json:"status"`
+	StartedAt    time.Time     `db:"started_at" json:"started_at"`
+	FinishedAt   *time.Time    `db:"finished_at" json:"finished_at,omitempty"`
+	Total        int           `db:"total" json:"total"`
+	Success      int           `db:"success" json:"success"`
+	Failed       int           `db:"failed" json:"failed"`
+	Error        int           `db:"error" json:"error"`
+	ErrorMessage *string       `db:"error_message" json:"error_message,omitempty"`
+	Items        []SyncRunItem `db:"-" json:"items,omitempty"`
+}
+
+type SyncRunItem struct {
+	ID        int     `db:"id" json:"id"`
+	SyncRunID int     `db:"sync_run_id" json:"sync_run_id"`
+	Batch     int     `db:"batch" json:"batch"`
+	ICAO      string  `db:"icao" json:"icao_ident"`
+	Outcome   string  `db:"outcome" json:"outcome"`
+	Status    string  `db:"status" json:"status"`
+	Error     *string `db:"error" json:"error,omitempty"`
+	// HTTPStatus is the status code the upstream rejected a failed batch with
+	HTTPStatus *int `db:"http_status" json:"http_status,omitempty"`
+	// Changes lists the airport fields the sync actually changed
+	Changes   FieldChanges `db:"changes" json:"changes,omitempty"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
 }
@@ -0,0 +1,28 @@
+package dto
+
+import "strings"
+
+// Validation error codes, they let clients react to a violation without parsing its message
+const (
+	ValidationCodeRequired      = "required"
+	ValidationCodeInvalidValue  = "invalid_value"
+	ValidationCodeInvalidFormat = "invalid_format"
+)
+
+// FieldError is one violation of a request, Field is named like the JSON of the request
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors lists every violation of a request, the handlers respond with it as the error of the response
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
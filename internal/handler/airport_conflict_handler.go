@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"aviation-service/internal/dto"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type AirportConflictHandler struct {
+	logger  *zap.SugaredLogger
+	service AirportConflictService
+}
+
+type AirportConflictService interface {
+	GetAllConflict(ctx context.Context, icao string, includeResolved bool, limit, offset int) ([]dto.AirportConflict, error)
+	ResolveConflict(ctx context.Context, id int, resolution string) (*dto.AirportConflict, error)
+}
+
+func NewAirportConflictHandler(logger *zap.SugaredLogger, service AirportConflictService) *AirportConflictHandler {
+	return &AirportConflictHandler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+func (h *AirportConflictHandler) RegisterRoutes(r chi.Router) {
+	r.With(requireReader).Get("/airport/conflicts", h.GetAllConflict)
+	r.With(requireEditor).Post("/airport/conflicts/{id}/resolve", h.ResolveConflict)
+}
+
+func (h *AirportConflictHandler) GetAllConflict(w http.ResponseWriter, r *http.Request) {
+	icao := r.URL.Query().Get("icao")
+	includeResolved, _ := strconv.ParseBool(r.URL.Query().Get("includeResolved"))
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	conflicts, err := h.service.GetAllConflict(r.Context(), icao, includeResolved, pageSize, offset)
+	if err != nil {
+		h.logger.Errorw("Failed to get airport conflicts", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Failed to get airport conflicts")
+		return
+	}
+
+	h.logger.Info("Airport conflict data get successfully")
+	if conflicts == nil {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(nil, "No airport conflicts found"))
+	} else {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(dto.PaginatedResponse{
+			Page:     page,
+			PageSize: pageSize,
+			Data:     conflicts,
+		}, ""))
+	}
+}
+
+func (h *AirportConflictHandler) ResolveConflict(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.logger.Info("Failed to resolve airport conflict, invalid id")
+		respondWithError(w, http.StatusBadRequest, "Invalid id")
+		return
+	}
+
+	var request dto.ResolveConflictRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Info("Failed to resolve airport conflict, invalid request body")
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	defer r.Body.Close()
+	if request.Resolution != dto.ConflictResolutionKeepManual && request.Resolution != dto.ConflictResolutionAcceptUpstream {
+		h.logger.Info("Failed to resolve airport conflict, invalid resolution")
+		respondWithError(w, http.StatusBadRequest, "Invalid resolution")
+		return
+	}
+
+	conflict, err := h.service.ResolveConflict(r.Context(), id, request.Resolution)
+	if err != nil {
+		h.logger.Errorw("Failed to resolve airport conflict", "error", err, "id", id)
+		respondWithError(w, http.StatusBadRequest, "Failed to resolve airport conflict")
+		return
+	}
+
+	h.logger.Info("Airport conflict resolved successfully")
+	respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(conflict, ""))
+}
@@ -0,0 +1,185 @@
+package handler_test
+
+import (
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/handler"
+	utils "aviation-service/internal/testutils"
+	u "aviation-service/internal/utils"
+	"aviation-service/pkg/logger"
+	"aviation-service/pkg/middleware"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type mockAirportConflictService struct {
+	conflicts       []dto.AirportConflict
+	conflict        *dto.AirportConflict
+	includeResolved bool
+	resolution      string
+	err             error
+}
+
+func (m *mockAirportConflictService) GetAllConflict(ctx context.Context, icao string, includeResolved bool, limit, offset int) ([]dto.AirportConflict, error) {
+	m.includeResolved = includeResolved
+	return m.conflicts, m.err
+}
+
+func (m *mockAirportConflictService) ResolveConflict(ctx context.Context, id int, resolution string) (*dto.AirportConflict, error) {
+	m.resolution = resolution
+	return m.conflict, m.err
+}
+
+func TestAirportConflictHandler_GetAllConflict(t *testing.T) {
+	manualPhone, upstreamPhone := "828-000-0000", "828-684-2226"
+	conflicts := []dto.AirportConflict{{ID: 3, AirportID: 1, ICAO: "KAVL", Field: "manager_phone", ManualValue: &manualPhone, UpstreamValue: &upstreamPhone}}
+	tests := []struct {
+		name    string
+		service *mockAirportConflictService
+		path    string
+		utils.ExpectedResult
+	}{
+		{
+			name:    "Success with data",
+			service: &mockAirportConflictService{conflicts: conflicts},
+			path:    "/airport/conflicts?icao=KAVL&includeResolved=true",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data:   dto.PaginatedResponse{Page: 1, PageSize: 10, Data: conflicts},
+			},
+		},
+		{
+			name:    "No data",
+			service: &mockAirportConflictService{},
+			path:    "/airport/conflicts",
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusOK,
+				Message: "No airport conflicts found",
+			},
+		},
+		{
+			name:    "Service error",
+			service: &mockAirportConflictService{err: fmt.Errorf("DB error")},
+			path:    "/airport/conflicts",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Failed to get airport conflicts",
+			},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The airport routes are registered too so /airport/conflicts is not taken for an airport id
+			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
+			NewAirportHandler(log, nil, u.NewAirportValidator()).RegisterRoutes(r)
+			NewAirportConflictHandler(log, tt.service).RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
+				t.Error(err)
+			}
+			if tt.Data != nil && !tt.service.includeResolved {
+				t.Error("Expected resolved conflicts included")
+			}
+		})
+	}
+}
+
+func TestAirportConflictHandler_ResolveConflict(t *testing.T) {
+	resolution := dto.ConflictResolutionAcceptUpstream
+	resolved := &dto.AirportConflict{ID: 3, AirportID: 1, ICAO: "KAVL", Field: "manager_phone", Resolution: &resolution}
+	tests := []struct {
+		name    string
+		service *mockAirportConflictService
+		path    string
+		body    string
+		utils.ExpectedResult
+	}{
+		{
+			name:    "Success resolve",
+			service: &mockAirportConflictService{conflict: resolved},
+			path:    "/airport/conflicts/3/resolve",
+			body:    `{"resolution": "accept-upstream"}`,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data:   resolved,
+			},
+		},
+		{
+			name:    "Invalid id",
+			service: &mockAirportConflictService{},
+			path:    "/airport/conflicts/abc/resolve",
+			body:    `{"resolution": "keep-manual"}`,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid id",
+			},
+		},
+		{
+			name:    "Invalid request body",
+			service: &mockAirportConflictService{},
+			path:    "/airport/conflicts/3/resolve",
+			body:    `A`,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid request body",
+			},
+		},
+		{
+			name:    "Invalid resolution",
+			service: &mockAirportConflictService{},
+			path:    "/airport/conflicts/3/resolve",
+			body:    `{"resolution": "ignore"}`,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid resolution",
+			},
+		},
+		{
+			name:    "Service error",
+			service: &mockAirportConflictService{err: fmt.Errorf("No open conflict found with id 3")},
+			path:    "/airport/conflicts/3/resolve",
+			body:    `{"resolution": "keep-manual"}`,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Failed to resolve airport conflict",
+			},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
+			NewAirportHandler(log, nil, u.NewAirportValidator()).RegisterRoutes(r)
+			NewAirportConflictHandler(log, tt.service).RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
+				t.Error(err)
+			}
+			if tt.Data != nil && tt.service.resolution != dto.ConflictResolutionAcceptUpstream {
+				t.Errorf("Expected resolution accept-upstream, got %q", tt.service.resolution)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"aviation-service/internal/dto"
+	"aviation-service/internal/utils"
+)
+
+type AirportExportHandler struct {
+	logger  *zap.SugaredLogger
+	service AirportExportService
+}
+
+type AirportExportService interface {
+	Export(ctx context.Context, format, icao, facilityName string, w io.Writer) error
+}
+
+func NewAirportExportHandler(logger *zap.SugaredLogger, service AirportExportService) *AirportExportHandler {
+	return &AirportExportHandler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+func (h *AirportExportHandler) RegisterRoutes(r chi.Router) {
+	r.With(requireReader).Get("/airport/export", h.ExportAirport)
+}
+
+func (h *AirportExportHandler) ExportAirport(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = dto.ExportFormatCSV
+	}
+	if format != dto.ExportFormatCSV && format != dto.ExportFormatGeoJSON && format != dto.ExportFormatKML {
+		h.logger.Info("Failed to export airports, invalid export format")
+		respondWithError(w, http.StatusBadRequest, "Invalid export format")
+		return
+	}
+	icao := r.URL.Query().Get("icao")
+	facilityName := r.URL.Query().Get("facilityName")
+
+	// The export streams for as long as the search matches airports, the server write timeout would cut it off
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Debugw("Failed to clear the write deadline of the export", "error", err)
+	}
+
+	contentType, extension := utils.ExportContentType(format)
+	out := &exportResponseWriter{
+		w:           w,
+		contentType: contentType,
+		filename:    "airports." + extension,
+	}
+	if err := h.service.Export(r.Context(), format, icao, facilityName, out); err != nil {
+		h.logger.Errorw("Failed to export airports", "error", err, "format", format)
+		// Once the body has started the status is already sent, the client sees a truncated file
+		if !out.started {
+			respondWithError(w, http.StatusBadRequest, "Failed to export airports")
+		}
+		return
+	}
+
+	h.logger.Info("Airports exported successfully")
+}
+
+// exportResponseWriter sends the download headers with the first write, so a failure before any row can still be answered with a JSON error
+type exportResponseWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (e *exportResponseWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		e.w.Header().Set("Content-Type", e.contentType)
+		e.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename))
+		e.w.WriteHeader(http.StatusOK)
+	}
+	return e.w.Write(p)
+}
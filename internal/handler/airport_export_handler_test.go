@@ -0,0 +1,176 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/handler"
+	utils "aviation-service/internal/testutils"
+	u "aviation-service/internal/utils"
+	"aviation-service/pkg/logger"
+	"aviation-service/pkg/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type mockAirportExportService struct {
+	output       string
+	chunks       []string
+	delay        time.Duration
+	err          error
+	format       string
+	icao         string
+	facilityName string
+}
+
+func (m *mockAirportExportService) Export(ctx context.Context, format, icao, facilityName string, w io.Writer) error {
+	m.format, m.icao, m.facilityName = format, icao, facilityName
+	if m.output != "" {
+		io.WriteString(w, m.output)
+	}
+	for _, chunk := range m.chunks {
+		time.Sleep(m.delay)
+		if _, err := io.WriteString(w, chunk); err != nil {
+			return err
+		}
+	}
+	return m.err
+}
+
+func TestAirportExportHandler_ExportAirport(t *testing.T) {
+	tests := []struct {
+		name                string
+		service             *mockAirportExportService
+		path                string
+		expectedFormat      string
+		expectedStatus      int
+		expectedContentType string
+		expectedDisposition string
+		expectedBody        string
+		expectedError       string
+	}{
+		{
+			name:                "Success default CSV",
+			service:             &mockAirportExportService{output: "id,icao_ident\n1,KAVL\n"},
+			path:                "/airport/export?icao=KAVL&facilityName=asheville",
+			expectedFormat:      dto.ExportFormatCSV,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv",
+			expectedDisposition: `attachment; filename="airports.csv"`,
+			expectedBody:        "id,icao_ident\n1,KAVL\n",
+		},
+		{
+			name:                "Success GeoJSON",
+			service:             &mockAirportExportService{output: `{"type":"FeatureCollection","features":[]}`},
+			path:                "/airport/export?format=GeoJSON&icao=KAVL&facilityName=asheville",
+			expectedFormat:      dto.ExportFormatGeoJSON,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/geo+json",
+			expectedDisposition: `attachment; filename="airports.geojson"`,
+			expectedBody:        `{"type":"FeatureCollection","features":[]}`,
+		},
+		{
+			name:                "Error after streaming started keeps partial body",
+			service:             &mockAirportExportService{output: "<?xml", err: fmt.Errorf("DB error")},
+			path:                "/airport/export?format=kml&icao=KAVL&facilityName=asheville",
+			expectedFormat:      dto.ExportFormatKML,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/vnd.google-earth.kml+xml",
+			expectedDisposition: `attachment; filename="airports.kml"`,
+			expectedBody:        "<?xml",
+		},
+		{
+			name:           "Invalid format",
+			service:        &mockAirportExportService{},
+			path:           "/airport/export?format=xml",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid export format",
+		},
+		{
+			name:           "Service error before streaming",
+			service:        &mockAirportExportService{err: fmt.Errorf("DB error")},
+			path:           "/airport/export?icao=KAVL&facilityName=asheville",
+			expectedFormat: dto.ExportFormatCSV,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Failed to export airports",
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
+			NewAirportHandler(log, nil, u.NewAirportValidator()).RegisterRoutes(r)
+			NewAirportExportHandler(log, tt.service).RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			if tt.expectedError != "" {
+				if err := utils.AssertHandlerResponse(t, rr, utils.ExpectedResult{Status: tt.expectedStatus, Error: tt.expectedError}); err != nil {
+					t.Error(err)
+				}
+			} else {
+				if rr.Code != tt.expectedStatus {
+					t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+				}
+				if got := rr.Header().Get("Content-Type"); got != tt.expectedContentType {
+					t.Errorf("Expected content type %q, got %q", tt.expectedContentType, got)
+				}
+				if got := rr.Header().Get("Content-Disposition"); got != tt.expectedDisposition {
+					t.Errorf("Expected content disposition %q, got %q", tt.expectedDisposition, got)
+				}
+				if rr.Body.String() != tt.expectedBody {
+					t.Errorf("Expected body %q, got %q", tt.expectedBody, rr.Body.String())
+				}
+			}
+
+			if tt.service.format != tt.expectedFormat {
+				t.Errorf("Expected format %q, got %q", tt.expectedFormat, tt.service.format)
+			}
+			if tt.expectedFormat != "" && (tt.service.icao != "KAVL" || tt.service.facilityName != "asheville") {
+				t.Errorf("Expected filters KAVL and asheville, got %q and %q", tt.service.icao, tt.service.facilityName)
+			}
+		})
+	}
+}
+
+func TestAirportExportHandler_ExportAirportOutlivesWriteTimeout(t *testing.T) {
+	service := &mockAirportExportService{chunks: []string{"id,icao_ident\n", "1,KAVL\n", "2,KADT\n"}, delay: 40 * time.Millisecond}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	r := chi.NewRouter()
+	r.Use(middleware.Authenticate(middleware.RoleOperator))
+	r.Use(middleware.ZapLogger)
+	r.Use(middleware.Metrics)
+	NewAirportExportHandler(log, service).RegisterRoutes(r)
+
+	server := httptest.NewUnstartedServer(r)
+	server.Config.WriteTimeout = 50 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/airport/export")
+	if err != nil {
+		t.Fatalf("Expected the export response, got %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Expected the whole export read, got %v after %q", err, body)
+	}
+	if expected := "id,icao_ident\n1,KAVL\n2,KADT\n"; string(body) != expected {
+		t.Errorf("Expected body %q, got %q", expected, body)
+	}
+}
@@ -33,17 +33,21 @@ func NewAirportHandler(logger *zap.SugaredLogger, service service.IAirportServic
 
 func (h *AirportHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/airport", func(r chi.Router) {
-		r.Get("/", h.GetAllAirport)
-		r.Post("/", h.CreateAirport)
+		r.With(requireReader).Get("/", h.GetAllAirport)
+		r.With(requireEditor).Post("/", h.CreateAirport)
 
 		r.Route("/search", func(r chi.Router) {
-			r.Get("/", h.SearchAirport)
+			r.With(requireReader).Get("/", h.SearchAirport)
+		})
+		r.Route("/nearby", func(r chi.Router) {
+			r.With(requireReader).Get("/", h.GetNearbyAirport)
 		})
 
 		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", h.GetAirport)
-			r.Put("/", h.UpdateAirport)
-			r.Delete("/", h.DeleteAirport)
+			r.With(requireReader).Get("/", h.GetAirport)
+			r.With(requireEditor).Put("/", h.UpdateAirport)
+			r.With(requireEditor).Delete("/", h.DeleteAirport)
+			r.With(requireReader).Get("/history", h.GetAirportHistory)
 		})
 	})
 }
@@ -100,6 +104,42 @@ func (h *AirportHandler) GetAirport(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *AirportHandler) GetAirportHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.logger.Info("Failed to get airport history, invalid id")
+		respondWithError(w, http.StatusBadRequest, "Invalid id")
+		return
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	history, err := h.service.GetAirportHistory(r.Context(), id, pageSize, offset)
+	if err != nil {
+		h.logger.Errorw("Failed to get airport history", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Failed to get airport history")
+		return
+	}
+
+	h.logger.Info("Airport history get successfully")
+	if history == nil {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(nil, "No airport history found"))
+	} else {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(dto.PaginatedResponse{
+			Page:     page,
+			PageSize: pageSize,
+			Data:     history,
+		}, ""))
+	}
+}
+
 func (h *AirportHandler) SearchAirport(w http.ResponseWriter, r *http.Request) {
 	icao := r.URL.Query().Get("icao")
 	facilityName := r.URL.Query().Get("facilityName")
@@ -132,6 +172,55 @@ func (h *AirportHandler) SearchAirport(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *AirportHandler) GetNearbyAirport(w http.ResponseWriter, r *http.Request) {
+	lat, latErr := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(r.URL.Query().Get("lon"), 64)
+	if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		h.logger.Info("Failed to get nearby airports, invalid coordinates")
+		respondWithError(w, http.StatusBadRequest, "Invalid coordinates")
+		return
+	}
+
+	radiusNm := 50.0
+	if radius := r.URL.Query().Get("radiusNm"); radius != "" {
+		parsed, err := strconv.ParseFloat(radius, 64)
+		if err != nil || parsed <= 0 || parsed > 1000 {
+			h.logger.Info("Failed to get nearby airports, invalid radius")
+			respondWithError(w, http.StatusBadRequest, "Invalid radius")
+			return
+		}
+		radiusNm = parsed
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	airports, err := h.service.GetNearbyAirport(r.Context(), lat, lon, radiusNm, pageSize, offset)
+	if err != nil {
+		h.logger.Errorw("Failed to get nearby airports", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Failed to get nearby airports")
+		return
+	}
+
+	h.logger.Info("Nearby airport data get successfully")
+	if airports == nil {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(nil, "No airports found"))
+	} else {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(dto.PaginatedResponse{
+			Page:     page,
+			PageSize: pageSize,
+			Data:     airports,
+		}, ""))
+	}
+}
+
 func (h *AirportHandler) CreateAirport(w http.ResponseWriter, r *http.Request) {
 	var request dto.Airport
 
@@ -143,8 +232,8 @@ func (h *AirportHandler) CreateAirport(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	if err := h.validator.Validate(&request); err != nil {
-		h.logger.Errorw("Failed to validate create airport request", "error", err)
-		respondWithError(w, http.StatusBadRequest, "Failed to validate create airport request")
+		h.logger.Infow("Failed to validate create airport request", "error", err)
+		respondWithValidationError(w, err, "Failed to validate create airport request")
 		return
 	}
 
@@ -183,8 +272,8 @@ func (h *AirportHandler) UpdateAirport(w http.ResponseWriter, r *http.Request) {
 	request.ID = id
 
 	if err := h.validator.Validate(&request); err != nil {
-		h.logger.Errorw("Failed to validate update airport request", "error", err)
-		respondWithError(w, http.StatusBadRequest, "Failed to validate update airport request")
+		h.logger.Infow("Failed to validate update airport request", "error", err)
+		respondWithValidationError(w, err, "Failed to validate update airport request")
 		return
 	}
 
@@ -15,6 +15,7 @@ import (
 	"aviation-service/internal/service"
 	utils "aviation-service/internal/testutils"
 	"aviation-service/pkg/logger"
+	"aviation-service/pkg/middleware"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -32,6 +33,11 @@ func (v *mockAirportValidator) Validate(req *dto.Airport) error {
 	return v.validateErr
 }
 
+var airportFieldErrors = dto.ValidationErrors{
+	{Field: "icao_ident", Code: dto.ValidationCodeRequired, Message: "ICAO is required"},
+	{Field: "ownership", Code: dto.ValidationCodeInvalidValue, Message: "Ownership must be PU or PR"},
+}
+
 func TestAirportHandler_GetAllAirport(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -106,6 +112,7 @@ func TestAirportHandler_GetAllAirport(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
 			h := NewAirportHandler(log, tt.service, mockAirportValidator)
 			h.RegisterRoutes(r)
 
@@ -188,6 +195,7 @@ func TestAirportHandler_GetAirport(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
 			h := NewAirportHandler(log, tt.service, mockAirportValidator)
 			h.RegisterRoutes(r)
 
@@ -279,6 +287,7 @@ func TestAirportHandler_SearchAirport(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
 			h := NewAirportHandler(log, tt.service, mockAirportValidator)
 			h.RegisterRoutes(r)
 
@@ -294,6 +303,101 @@ func TestAirportHandler_SearchAirport(t *testing.T) {
 	}
 }
 
+func TestAirportHandler_GetNearbyAirport(t *testing.T) {
+	tests := []struct {
+		name        string
+		service     service.IAirportService
+		queryParams string
+		utils.ExpectedResult
+	}{
+		{
+			name: "Success with data and default radius",
+			service: &IAirportServiceMock{
+				GetNearbyAirportFunc: func(ctx context.Context, lat, lon, radiusNm float64, limit, offset int) ([]dto.NearbyAirport, error) {
+					if radiusNm != 50 {
+						return nil, fmt.Errorf("Unexpected radius %v", radiusNm)
+					}
+					return []dto.NearbyAirport{{Airport: dto.Airport{ID: 1, ICAO: "KADT"}, DistanceNm: 1.5}}, nil
+				},
+			},
+			queryParams: "?lat=33.1&lon=-88.2",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data: dto.PaginatedResponse{
+					Page:     1,
+					PageSize: 10,
+					Data:     []dto.NearbyAirport{{Airport: dto.Airport{ID: 1, ICAO: "KADT"}, DistanceNm: 1.5}},
+				},
+			},
+		},
+		{
+			name: "No data",
+			service: &IAirportServiceMock{
+				GetNearbyAirportFunc: func(ctx context.Context, lat, lon, radiusNm float64, limit, offset int) ([]dto.NearbyAirport, error) {
+					return nil, nil
+				},
+			},
+			queryParams: "?lat=33.1&lon=-88.2&radiusNm=5",
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusOK,
+				Message: "No airports found",
+			},
+		},
+		{
+			name:        "Invalid coordinates",
+			service:     &IAirportServiceMock{},
+			queryParams: "?lat=95&lon=-88.2",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid coordinates",
+			},
+		},
+		{
+			name:        "Invalid radius",
+			service:     &IAirportServiceMock{},
+			queryParams: "?lat=33.1&lon=-88.2&radiusNm=-1",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid radius",
+			},
+		},
+		{
+			name: "Service error",
+			service: &IAirportServiceMock{
+				GetNearbyAirportFunc: func(ctx context.Context, lat, lon, radiusNm float64, limit, offset int) ([]dto.NearbyAirport, error) {
+					return nil, fmt.Errorf("DB error")
+				},
+			},
+			queryParams: "?lat=33.1&lon=-88.2",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Failed to get nearby airports",
+			},
+		},
+	}
+
+	mockAirportValidator := &mockAirportValidator{}
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
+			h := NewAirportHandler(log, tt.service, mockAirportValidator)
+			h.RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/airport/nearby"+tt.queryParams, nil)
+			rr := httptest.NewRecorder()
+
+			h.GetNearbyAirport(rr, req)
+
+			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
 func TestAirportHandler_CreateAirport(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -358,6 +462,16 @@ func TestAirportHandler_CreateAirport(t *testing.T) {
 				Error:  "Failed to validate create airport request",
 			},
 		},
+		{
+			name:      "Validation error lists every field",
+			service:   &IAirportServiceMock{},
+			validator: &mockAirportValidator{isComplete: true, validateErr: airportFieldErrors},
+			body:      dto.Airport{},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusUnprocessableEntity,
+				Error:  airportFieldErrors,
+			},
+		},
 		{
 			name: "Service error",
 			service: &IAirportServiceMock{
@@ -379,6 +493,7 @@ func TestAirportHandler_CreateAirport(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
 			h := NewAirportHandler(log, tt.service, tt.validator)
 			h.RegisterRoutes(r)
 
@@ -465,6 +580,17 @@ func TestAirportHandler_UpdateAirport(t *testing.T) {
 				Error:  "Failed to validate update airport request",
 			},
 		},
+		{
+			name:      "Validation error lists every field",
+			service:   &IAirportServiceMock{},
+			params:    map[string]string{"id": "1"},
+			validator: &mockAirportValidator{isComplete: true, validateErr: airportFieldErrors},
+			body:      dto.Airport{},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusUnprocessableEntity,
+				Error:  airportFieldErrors,
+			},
+		},
 		{
 			name: "Service error",
 			service: &IAirportServiceMock{
@@ -502,6 +628,7 @@ func TestAirportHandler_UpdateAirport(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
 			h := NewAirportHandler(log, tt.service, tt.validator)
 			h.RegisterRoutes(r)
 
@@ -573,6 +700,7 @@ func TestAirportHandler_DeleteAirport(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
 			h := NewAirportHandler(log, tt.service, mockAirportValidator)
 			h.RegisterRoutes(r)
 
@@ -588,3 +716,90 @@ func TestAirportHandler_DeleteAirport(t *testing.T) {
 		})
 	}
 }
+
+func TestAirportHandler_GetAirportHistory(t *testing.T) {
+	done := "DONE"
+	history := []dto.AirportHistory{{ID: 2, AirportID: 1, ICAO: "KAVL", Action: dto.AirportHistoryActionUpdate, Actor: "sync_run:7",
+		Changes: dto.FieldChanges{{Field: "status", After: &done}}}}
+	tests := []struct {
+		name    string
+		service *IAirportServiceMock
+		path    string
+		utils.ExpectedResult
+	}{
+		{
+			name: "Success with data",
+			service: &IAirportServiceMock{
+				GetAirportHistoryFunc: func(ctx context.Context, id, limit, offset int) ([]dto.AirportHistory, error) {
+					return history, nil
+				},
+			},
+			path: "/airport/1/history?page=2&pageSize=5",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data: dto.PaginatedResponse{
+					Page:     2,
+					PageSize: 5,
+					Data:     history,
+				},
+			},
+		},
+		{
+			name: "No data",
+			service: &IAirportServiceMock{
+				GetAirportHistoryFunc: func(ctx context.Context, id, limit, offset int) ([]dto.AirportHistory, error) {
+					return nil, nil
+				},
+			},
+			path: "/airport/1/history",
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusOK,
+				Message: "No airport history found",
+			},
+		},
+		{
+			name:    "Invalid id",
+			service: &IAirportServiceMock{},
+			path:    "/airport/A/history",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid id",
+			},
+		},
+		{
+			name: "Service error",
+			service: &IAirportServiceMock{
+				GetAirportHistoryFunc: func(ctx context.Context, id, limit, offset int) ([]dto.AirportHistory, error) {
+					return nil, fmt.Errorf("DB error")
+				},
+			},
+			path: "/airport/1/history",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Failed to get airport history",
+			},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
+			NewAirportHandler(log, tt.service, &mockAirportValidator{}).RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
+				t.Error(err)
+			}
+			if calls := tt.service.GetAirportHistoryCalls(); len(calls) > 0 && calls[0].ID != 1 {
+				t.Errorf("Expected airport id 1, got %d", calls[0].ID)
+			}
+		})
+	}
+}
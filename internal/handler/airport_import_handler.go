@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"aviation-service/internal/dto"
+	"aviation-service/internal/utils"
+)
+
+const maxImportFileSize = 64 << 20
+
+type AirportImportHandler struct {
+	logger  *zap.SugaredLogger
+	service AirportImportService
+}
+
+type AirportImportService interface {
+	Import(ctx context.Context, format string, r io.Reader) (*dto.ImportResult, error)
+}
+
+func NewAirportImportHandler(logger *zap.SugaredLogger, service AirportImportService) *AirportImportHandler {
+	return &AirportImportHandler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+func (h *AirportImportHandler) RegisterRoutes(r chi.Router) {
+	r.With(requireEditor).Post("/airport/import", h.ImportAirport)
+}
+
+func (h *AirportImportHandler) ImportAirport(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		h.logger.Infow("Failed to import airports, invalid import file", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Invalid import file")
+		return
+	}
+	defer file.Close()
+
+	format := strings.ToLower(r.FormValue("format"))
+	if format == "" {
+		format = utils.ImportFormatFromFilename(header.Filename)
+	}
+	if format != dto.ImportFormatCSV && format != dto.ImportFormatNASR && format != dto.ImportFormatJSON {
+		h.logger.Info("Failed to import airports, invalid import format")
+		respondWithError(w, http.StatusBadRequest, "Invalid import format")
+		return
+	}
+
+	result, err := h.service.Import(r.Context(), format, file)
+	if err != nil {
+		h.logger.Errorw("Failed to import airports", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Failed to import airports")
+		return
+	}
+
+	h.logger.Info("Airports imported successfully")
+	respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(result, ""))
+}
@@ -0,0 +1,153 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/handler"
+	utils "aviation-service/internal/testutils"
+	u "aviation-service/internal/utils"
+	"aviation-service/pkg/logger"
+	"aviation-service/pkg/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type mockAirportImportService struct {
+	result *dto.ImportResult
+	format string
+	file   string
+	err    error
+}
+
+func (m *mockAirportImportService) Import(ctx context.Context, format string, r io.Reader) (*dto.ImportResult, error) {
+	body, _ := io.ReadAll(r)
+	m.format = format
+	m.file = string(body)
+	return m.result, m.err
+}
+
+func multipartImport(t *testing.T, filename, content, format string) (*bytes.Buffer, string) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if filename != "" {
+		part, err := writer.CreateFormFile("file", filename)
+		if err != nil {
+			t.Fatalf("Failed to create form file: %v", err)
+		}
+		part.Write([]byte(content))
+	}
+	if format != "" {
+		writer.WriteField("format", format)
+	}
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func TestAirportImportHandler_ImportAirport(t *testing.T) {
+	result := &dto.ImportResult{Format: dto.ImportFormatCSV, Total: 2, Created: 1, Failed: 1,
+		Errors: []dto.ImportRowError{{Row: 3, ICAO: "KJFK", Error: "Ownership must be PU or PR"}}}
+	tests := []struct {
+		name           string
+		service        *mockAirportImportService
+		filename       string
+		format         string
+		expectedFormat string
+		utils.ExpectedResult
+	}{
+		{
+			name:           "Success CSV import",
+			service:        &mockAirportImportService{result: result},
+			filename:       "airports.csv",
+			expectedFormat: dto.ImportFormatCSV,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data:   result,
+			},
+		},
+		{
+			name:           "Success format from extension",
+			service:        &mockAirportImportService{result: &dto.ImportResult{Format: dto.ImportFormatJSON}},
+			filename:       "airports.json",
+			expectedFormat: dto.ImportFormatJSON,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+			},
+		},
+		{
+			name:           "Success explicit NASR format",
+			service:        &mockAirportImportService{result: &dto.ImportResult{Format: dto.ImportFormatNASR}},
+			filename:       "APT_BASE.txt",
+			format:         "NASR",
+			expectedFormat: dto.ImportFormatNASR,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+			},
+		},
+		{
+			name:    "Missing file",
+			service: &mockAirportImportService{},
+			format:  dto.ImportFormatCSV,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid import file",
+			},
+		},
+		{
+			name:     "Invalid format",
+			service:  &mockAirportImportService{},
+			filename: "airports.csv",
+			format:   "xml",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid import format",
+			},
+		},
+		{
+			name:           "Service error",
+			service:        &mockAirportImportService{err: fmt.Errorf("Invalid CSV import file")},
+			filename:       "airports.csv",
+			expectedFormat: dto.ImportFormatCSV,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Failed to import airports",
+			},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
+			NewAirportHandler(log, nil, u.NewAirportValidator()).RegisterRoutes(r)
+			NewAirportImportHandler(log, tt.service).RegisterRoutes(r)
+
+			body, contentType := multipartImport(t, tt.filename, "icao\nKAVL\n", tt.format)
+			req := httptest.NewRequest(http.MethodPost, "/airport/import", body)
+			req.Header.Set("Content-Type", contentType)
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
+				t.Error(err)
+			}
+			if tt.service.format != tt.expectedFormat {
+				t.Errorf("Expected format %q, got %q", tt.expectedFormat, tt.service.format)
+			}
+			if tt.expectedFormat != "" && tt.service.file != "icao\nKAVL\n" {
+				t.Errorf("Expected uploaded file to reach the service, got %q", tt.service.file)
+			}
+		})
+	}
+}
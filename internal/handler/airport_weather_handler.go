@@ -2,13 +2,16 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 
 	"aviation-service/internal/dto"
+	"aviation-service/internal/service"
 )
 
 type AirportWeatherHandler struct {
@@ -17,7 +20,7 @@ type AirportWeatherHandler struct {
 }
 
 type AirportWeatherService interface {
-	SearchAirportWeather(ctx context.Context, icao, facilityName string, limit, offset int) ([]dto.AirportWeather, error)
+	SearchAirportWeather(ctx context.Context, icao, facilityName string, categories []string, limit, offset int) ([]dto.AirportWeather, error)
 }
 
 func NewAirportWeatherHandler(logger *zap.SugaredLogger, service AirportWeatherService) *AirportWeatherHandler {
@@ -29,13 +32,27 @@ func NewAirportWeatherHandler(logger *zap.SugaredLogger, service AirportWeatherS
 
 func (h *AirportWeatherHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/airport-weather", func(r chi.Router) {
-		r.Get("/", h.SearchAirportWeather)
+		r.With(requireReader).Get("/", h.SearchAirportWeather)
 	})
 }
 
 func (h *AirportWeatherHandler) SearchAirportWeather(w http.ResponseWriter, r *http.Request) {
 	icao := r.URL.Query().Get("icao")
 	facilityName := r.URL.Query().Get("facilityName")
+
+	var categories []string
+	if category := r.URL.Query().Get("category"); category != "" {
+		for _, c := range strings.Split(strings.ToUpper(category), ",") {
+			c = strings.TrimSpace(c)
+			if c != dto.FlightCategoryVFR && c != dto.FlightCategoryMVFR && c != dto.FlightCategoryIFR && c != dto.FlightCategoryLIFR {
+				h.logger.Info("Failed to get airport and weather, invalid category")
+				respondWithError(w, http.StatusBadRequest, "Invalid category")
+				return
+			}
+			categories = append(categories, c)
+		}
+	}
+
 	page, err := strconv.Atoi(r.URL.Query().Get("page"))
 	if err != nil || page < 1 {
 		page = 1
@@ -46,7 +63,13 @@ func (h *AirportWeatherHandler) SearchAirportWeather(w http.ResponseWriter, r *h
 	}
 	offset := (page - 1) * pageSize
 
-	airportWeathers, err := h.service.SearchAirportWeather(r.Context(), icao, facilityName, pageSize, offset)
+	airportWeathers, err := h.service.SearchAirportWeather(r.Context(), icao, facilityName, categories, pageSize, offset)
+	var scanLimit *service.CategoryScanLimitError
+	if errors.As(err, &scanLimit) {
+		h.logger.Infow("Failed to get airport and weather, category search too broad", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Too many airports to filter by category, narrow the search or ask for an earlier page")
+		return
+	}
 	if err != nil {
 		h.logger.Errorw("Failed to get airport and weather", "error", err)
 		respondWithError(w, http.StatusBadRequest, "Failed to get airport and weather")
@@ -5,30 +5,36 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	"aviation-service/internal/dto"
 	. "aviation-service/internal/handler"
+	"aviation-service/internal/service"
 	utils "aviation-service/internal/testutils"
 	"aviation-service/pkg/logger"
+	"aviation-service/pkg/middleware"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type mockAirportWeatherService struct {
-	response []dto.AirportWeather
-	err      error
+	response   []dto.AirportWeather
+	categories []string
+	err        error
 }
 
-func (m *mockAirportWeatherService) SearchAirportWeather(ctx context.Context, icao, facilityName string, limit, offset int) ([]dto.AirportWeather, error) {
+func (m *mockAirportWeatherService) SearchAirportWeather(ctx context.Context, icao, facilityName string, categories []string, limit, offset int) ([]dto.AirportWeather, error) {
+	m.categories = categories
 	return m.response, m.err
 }
 
 func TestAirportWeatherHandler_SearchAirportWeather(t *testing.T) {
 	tests := []struct {
 		name        string
-		service     AirportWeatherService
-		queryParams string
+		service            *mockAirportWeatherService
+		queryParams        string
+		expectedCategories []string
 		utils.ExpectedResult
 	}{
 		{
@@ -65,6 +71,33 @@ func TestAirportWeatherHandler_SearchAirportWeather(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Success with category filter",
+			service: &mockAirportWeatherService{response: []dto.AirportWeather{{
+				Airport:        dto.Airport{ICAO: "KAVL"},
+				FlightCategory: dto.FlightCategoryIFR}}},
+			queryParams:        "?category=ifr,LIFR",
+			expectedCategories: []string{dto.FlightCategoryIFR, dto.FlightCategoryLIFR},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data: dto.PaginatedResponse{
+					Page:     1,
+					PageSize: 10,
+					Data: []dto.AirportWeather{{
+						Airport:        dto.Airport{ICAO: "KAVL"},
+						FlightCategory: dto.FlightCategoryIFR}},
+				},
+			},
+		},
+		{
+			name:        "Invalid category",
+			service:     &mockAirportWeatherService{},
+			queryParams: "?category=IMC",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid category",
+			},
+		},
 		{
 			name:        "No data",
 			service:     &mockAirportWeatherService{response: nil},
@@ -74,6 +107,16 @@ func TestAirportWeatherHandler_SearchAirportWeather(t *testing.T) {
 				Message: "No airport and weather found",
 			},
 		},
+		{
+			name:               "Category search too broad",
+			service:            &mockAirportWeatherService{err: &service.CategoryScanLimitError{Scanned: 500}},
+			queryParams:        "?category=LIFR&page=50",
+			expectedCategories: []string{dto.FlightCategoryLIFR},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Too many airports to filter by category, narrow the search or ask for an earlier page",
+			},
+		},
 		{
 			name:        "Service error",
 			service:     &mockAirportWeatherService{err: fmt.Errorf("DB error")},
@@ -90,6 +133,7 @@ func TestAirportWeatherHandler_SearchAirportWeather(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
 			h := NewAirportWeatherHandler(log, tt.service)
 			h.RegisterRoutes(r)
 
@@ -101,6 +145,9 @@ func TestAirportWeatherHandler_SearchAirportWeather(t *testing.T) {
 			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
 				t.Error(err)
 			}
+			if !reflect.DeepEqual(tt.service.categories, tt.expectedCategories) {
+				t.Errorf("Expected categories %v, got %v", tt.expectedCategories, tt.service.categories)
+			}
 		})
 	}
 }
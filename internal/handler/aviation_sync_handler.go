@@ -2,8 +2,14 @@ package handler
 
 import (
 	"aviation-service/internal/dto"
+	"aviation-service/internal/service"
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
@@ -15,7 +21,12 @@ type AviationSyncHandler struct {
 }
 
 type AviationSyncService interface {
-	Sync(ctx context.Context) (*dto.SyncResponse, error)
+	StartSync(ctx context.Context, opts dto.SyncOptions) (*dto.SyncJob, error)
+	GetSyncJob(ctx context.Context, id int) (*dto.SyncJob, error)
+	CancelSyncJob(ctx context.Context, id int) (*dto.SyncJob, error)
+	RefreshAirport(ctx context.Context, id int) (*dto.AirportRefresh, error)
+	GetAllSyncRun(ctx context.Context, limit, offset int) ([]dto.SyncRun, error)
+	GetSyncRun(ctx context.Context, id int) (*dto.SyncRun, error)
 }
 
 func NewAviationSyncHandler(logger *zap.SugaredLogger, service AviationSyncService) *AviationSyncHandler {
@@ -27,22 +38,209 @@ func NewAviationSyncHandler(logger *zap.SugaredLogger, service AviationSyncServi
 
 func (h *AviationSyncHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/sync", func(r chi.Router) {
-		r.Post("/", h.Sync)
+		r.With(requireOperator).Post("/", h.Sync)
+		r.Route("/jobs", func(r chi.Router) {
+			r.With(requireReader).Get("/{id}", h.GetSyncJob)
+			r.With(requireOperator).Delete("/{id}", h.CancelSyncJob)
+		})
+		r.Route("/runs", func(r chi.Router) {
+			r.With(requireReader).Get("/", h.GetAllSyncRun)
+			r.With(requireReader).Get("/{id}", h.GetSyncRun)
+		})
 	})
+	r.With(requireOperator).Post("/airport/{id}/refresh", h.RefreshAirport)
 }
 
 func (h *AviationSyncHandler) Sync(w http.ResponseWriter, r *http.Request) {
-	syncResponse, err := h.service.Sync(r.Context())
+	mode := strings.ToUpper(r.URL.Query().Get("mode"))
+	if mode == "" {
+		mode = dto.SyncModePending
+	}
+	if mode != dto.SyncModePending && mode != dto.SyncModeStale && mode != dto.SyncModeFailed {
+		h.logger.Info("Failed to sync, invalid mode")
+		respondWithError(w, http.StatusBadRequest, "Invalid sync mode")
+		return
+	}
+
+	// The body is optional, an empty one syncs by mode
+	var request dto.SyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+		h.logger.Info("Failed to sync, invalid request body")
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if len(request.ICAOs) > 0 && r.URL.Query().Has("mode") {
+		h.logger.Info("Failed to sync, mode given with icaos")
+		respondWithError(w, http.StatusBadRequest, "Sync mode cannot be combined with icaos")
+		return
+	}
+
+	job, err := h.service.StartSync(r.Context(), dto.SyncOptions{Trigger: dto.SyncTriggerAPI, Mode: mode, ICAOs: request.ICAOs})
 	if err != nil {
-		h.logger.Errorw("Failed to sync", "error", err)
-		respondWithError(w, http.StatusBadRequest, "Failed to sync")
+		h.respondWithSyncError(w, err, "Failed to sync")
 		return
 	}
 
-	h.logger.Info("Sync airport data successfully")
-	if syncResponse == nil {
+	if job == nil {
+		h.logger.Info("No airport data to sync")
 		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(nil, "No airport data to sync"))
+		return
+	}
+	h.logger.Infow("Sync job started", "id", job.ID)
+	respondWithJSON(w, http.StatusAccepted, dto.NewSuccessResponse(job, "Sync job started"))
+}
+
+func (h *AviationSyncHandler) RefreshAirport(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.logger.Info("Failed to refresh airport, invalid id")
+		respondWithError(w, http.StatusBadRequest, "Invalid id")
+		return
+	}
+
+	refresh, serviceErr := h.service.RefreshAirport(r.Context(), id)
+	if serviceErr != nil {
+		h.respondWithSyncError(w, serviceErr, "Failed to refresh airport")
+		return
+	}
+
+	h.logger.Info("Airport refreshed successfully")
+	if refresh == nil {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(nil, "No airport found"))
+	} else {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(refresh, ""))
+	}
+}
+
+// respondWithSyncError answers a sync that could not start, a running sync is a conflict and unknown ICAOs are listed
+func (h *AviationSyncHandler) respondWithSyncError(w http.ResponseWriter, err error, message string) {
+	var inProgress *service.SyncInProgressError
+	var notFound *service.AirportsNotFoundError
+	switch {
+	case errors.As(err, &inProgress):
+		h.logger.Infow(message+", sync already in progress", "error", err)
+		respondWithJSON(w, http.StatusConflict, dto.Response{
+			Success: false,
+			Message: "Error occurred",
+			Data:    inProgress.Run,
+			Error:   "Sync already in progress",
+		})
+	case errors.As(err, &notFound):
+		h.logger.Infow(message+", airports not found", "error", err)
+		respondWithJSON(w, http.StatusBadRequest, dto.Response{
+			Success: false,
+			Message: "Error occurred",
+			Data:    dto.SyncRequest{ICAOs: notFound.ICAOs},
+			Error:   "Airports not found",
+		})
+	default:
+		h.logger.Errorw(message, "error", err)
+		respondWithError(w, http.StatusBadRequest, message)
+	}
+}
+
+func (h *AviationSyncHandler) GetSyncJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.logger.Info("Failed to get sync job, invalid id")
+		respondWithError(w, http.StatusBadRequest, "Invalid id")
+		return
+	}
+
+	job, serviceErr := h.service.GetSyncJob(r.Context(), id)
+	if serviceErr != nil {
+		h.logger.Errorw("Failed to get sync job", "error", serviceErr)
+		respondWithError(w, http.StatusBadRequest, "Failed to get sync job")
+		return
+	}
+
+	h.logger.Info("Sync job data get successfully")
+	if job == nil {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(nil, "No sync job found"))
+	} else {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(job, ""))
+	}
+}
+
+func (h *AviationSyncHandler) CancelSyncJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.logger.Info("Failed to cancel sync job, invalid id")
+		respondWithError(w, http.StatusBadRequest, "Invalid id")
+		return
+	}
+
+	job, serviceErr := h.service.CancelSyncJob(r.Context(), id)
+	if serviceErr != nil {
+		h.logger.Errorw("Failed to cancel sync job", "error", serviceErr)
+		respondWithError(w, http.StatusBadRequest, "Failed to cancel sync job")
+		return
+	}
+
+	// A job still running stops in the process that runs it, before its next batch
+	if job != nil && job.Status == dto.SyncRunStatusRunning {
+		h.logger.Infow("Sync job cancellation requested", "id", id)
+		respondWithJSON(w, http.StatusAccepted, dto.NewSuccessResponse(job, "Sync job cancellation requested"))
+		return
+	}
+
+	h.logger.Info("Sync job cancelled successfully")
+	if job == nil {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(nil, "No sync job found"))
+	} else {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(job, ""))
+	}
+}
+
+func (h *AviationSyncHandler) GetAllSyncRun(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	runs, err := h.service.GetAllSyncRun(r.Context(), pageSize, offset)
+	if err != nil {
+		h.logger.Errorw("Failed to get all sync runs", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Failed to get all sync runs")
+		return
+	}
+
+	h.logger.Info("All sync run data get successfully")
+	if runs == nil {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(nil, "No sync runs found"))
+	} else {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(dto.PaginatedResponse{
+			Page:     page,
+			PageSize: pageSize,
+			Data:     runs,
+		}, ""))
+	}
+}
+
+func (h *AviationSyncHandler) GetSyncRun(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.logger.Info("Failed to get sync run, invalid id")
+		respondWithError(w, http.StatusBadRequest, "Invalid id")
+		return
+	}
+
+	run, serviceErr := h.service.GetSyncRun(r.Context(), id)
+	if serviceErr != nil {
+		h.logger.Errorw("Failed to get sync run", "error", serviceErr)
+		respondWithError(w, http.StatusBadRequest, "Failed to get sync run")
+		return
+	}
+
+	h.logger.Info("Sync run data get successfully")
+	if run == nil {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(nil, "No sync run found"))
 	} else {
-		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(syncResponse, ""))
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(run, ""))
 	}
 }
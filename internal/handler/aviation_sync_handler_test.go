@@ -4,43 +4,158 @@ import (
 	"aviation-service/internal/dto"
 	utils "aviation-service/internal/testutils"
 	. "aviation-service/internal/handler"
+	"aviation-service/internal/service"
 	"aviation-service/pkg/logger"
+	"aviation-service/pkg/middleware"
 	"bytes"
 	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type mockSyncService struct {
-	response *dto.SyncResponse
-	err      error
+	opts    dto.SyncOptions
+	job     *dto.SyncJob
+	refresh *dto.AirportRefresh
+	runs    []dto.SyncRun
+	run     *dto.SyncRun
+	err     error
 }
 
-func (m *mockSyncService) Sync(ctx context.Context) (*dto.SyncResponse, error) {
-	return m.response, m.err
+func (m *mockSyncService) StartSync(ctx context.Context, opts dto.SyncOptions) (*dto.SyncJob, error) {
+	m.opts = opts
+	return m.job, m.err
+}
+
+func (m *mockSyncService) GetSyncJob(ctx context.Context, id int) (*dto.SyncJob, error) {
+	return m.job, m.err
+}
+
+func (m *mockSyncService) CancelSyncJob(ctx context.Context, id int) (*dto.SyncJob, error) {
+	return m.job, m.err
+}
+
+func (m *mockSyncService) GetAllSyncRun(ctx context.Context, limit, offset int) ([]dto.SyncRun, error) {
+	return m.runs, m.err
+}
+
+func (m *mockSyncService) RefreshAirport(ctx context.Context, id int) (*dto.AirportRefresh, error) {
+	return m.refresh, m.err
+}
+
+func (m *mockSyncService) GetSyncRun(ctx context.Context, id int) (*dto.SyncRun, error) {
+	return m.run, m.err
 }
 
 func TestAviationSyncHandler_Sync(t *testing.T) {
 	tests := []struct {
-		name    string
-		service AviationSyncService
+		name          string
+		service       *mockSyncService
+		path          string
+		body          string
+		expectedMode  string
+		expectedICAOs []string
 		utils.ExpectedResult
 	}{
 		{
-			name:    "Success with data",
-			service: &mockSyncService{response: &dto.SyncResponse{Total: 12, Success: 10, Failed: 2}, err: nil},
+			name:         "Success job started",
+			service:      &mockSyncService{job: &dto.SyncJob{ID: 3, Mode: dto.SyncModePending, Status: dto.SyncRunStatusRunning, Total: 12, Batches: 1}},
+			path:         "/sync",
+			expectedMode: dto.SyncModePending,
 			ExpectedResult: utils.ExpectedResult{
-				Status: http.StatusOK,
-				Data:   dto.SyncResponse{Total: 12, Success: 10, Failed: 2},
+				Status:  http.StatusAccepted,
+				Data:    dto.SyncJob{ID: 3, Mode: dto.SyncModePending, Status: dto.SyncRunStatusRunning, Total: 12, Batches: 1},
+				Message: "Sync job started",
+			},
+		},
+		{
+			name:         "Success stale mode",
+			service:      &mockSyncService{job: &dto.SyncJob{ID: 4, Mode: dto.SyncModeStale, Status: dto.SyncRunStatusRunning, Total: 3, Batches: 1}},
+			path:         "/sync?mode=stale",
+			expectedMode: dto.SyncModeStale,
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusAccepted,
+				Data:    dto.SyncJob{ID: 4, Mode: dto.SyncModeStale, Status: dto.SyncRunStatusRunning, Total: 3, Batches: 1},
+				Message: "Sync job started",
 			},
 		},
 		{
-			name:    "Success no data",
-			service: &mockSyncService{response: nil, err: nil},
+			name:         "Success failed mode",
+			service:      &mockSyncService{job: &dto.SyncJob{ID: 6, Mode: dto.SyncModeFailed, Status: dto.SyncRunStatusRunning, Total: 4, Batches: 1}},
+			path:         "/sync?mode=Failed",
+			expectedMode: dto.SyncModeFailed,
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusAccepted,
+				Data:    dto.SyncJob{ID: 6, Mode: dto.SyncModeFailed, Status: dto.SyncRunStatusRunning, Total: 4, Batches: 1},
+				Message: "Sync job started",
+			},
+		},
+		{
+			name:          "Success targeted ICAOs",
+			service:       &mockSyncService{job: &dto.SyncJob{ID: 5, Mode: dto.SyncModeTargeted, Status: dto.SyncRunStatusRunning, Total: 2, Batches: 1}},
+			path:          "/sync",
+			body:          `{"icaos": ["KADT", "KAIV"]}`,
+			expectedMode:  dto.SyncModePending,
+			expectedICAOs: []string{"KADT", "KAIV"},
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusAccepted,
+				Data:    dto.SyncJob{ID: 5, Mode: dto.SyncModeTargeted, Status: dto.SyncRunStatusRunning, Total: 2, Batches: 1},
+				Message: "Sync job started",
+			},
+		},
+		{
+			name:         "Unknown ICAOs",
+			service:      &mockSyncService{err: &service.AirportsNotFoundError{ICAOs: []string{"KXXX"}}},
+			path:         "/sync",
+			body:         `{"icaos": ["KADT", "KXXX"]}`,
+			expectedMode: dto.SyncModePending,
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusBadRequest,
+				Data:    dto.SyncRequest{ICAOs: []string{"KXXX"}},
+				Message: "Error occurred",
+				Error:   "Airports not found",
+			},
+			expectedICAOs: []string{"KADT", "KXXX"},
+		},
+		{
+			name:    "Invalid request body",
+			service: &mockSyncService{},
+			path:    "/sync",
+			body:    `{"icaos": "KADT"}`,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid request body",
+			},
+		},
+		{
+			name:    "Mode with ICAOs",
+			service: &mockSyncService{},
+			path:    "/sync?mode=stale",
+			body:    `{"icaos": ["KADT"]}`,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Sync mode cannot be combined with icaos",
+			},
+		},
+		{
+			name:    "Invalid mode",
+			service: &mockSyncService{},
+			path:    "/sync?mode=all",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid sync mode",
+			},
+		},
+		{
+			name:         "Success no data",
+			service:      &mockSyncService{},
+			path:         "/sync",
+			expectedMode: dto.SyncModePending,
 			ExpectedResult: utils.ExpectedResult{
 				Status:  http.StatusOK,
 				Data:    nil,
@@ -48,8 +163,22 @@ func TestAviationSyncHandler_Sync(t *testing.T) {
 			},
 		},
 		{
-			name:    "Failed to sync",
-			service: &mockSyncService{response: nil, err: fmt.Errorf("Sync error")},
+			name:         "Sync already in progress",
+			service:      &mockSyncService{err: &service.SyncInProgressError{Run: &dto.SyncRun{ID: 4, Status: dto.SyncRunStatusRunning}}},
+			path:         "/sync",
+			expectedMode: dto.SyncModePending,
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusConflict,
+				Data:    dto.SyncRun{ID: 4, Status: dto.SyncRunStatusRunning},
+				Message: "Error occurred",
+				Error:   "Sync already in progress",
+			},
+		},
+		{
+			name:         "Failed to sync",
+			service:      &mockSyncService{err: fmt.Errorf("Sync error")},
+			path:         "/sync",
+			expectedMode: dto.SyncModePending,
 			ExpectedResult: utils.ExpectedResult{
 				Status:  http.StatusBadRequest,
 				Data:    nil,
@@ -64,14 +193,374 @@ func TestAviationSyncHandler_Sync(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
 			h := NewAviationSyncHandler(log, tt.service)
 			h.RegisterRoutes(r)
 
-			req := httptest.NewRequest(http.MethodPost, "/sync", bytes.NewBuffer(nil))
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
 			rr := httptest.NewRecorder()
 
 			h.Sync(rr, req)
 
+			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
+				t.Error(err)
+			}
+			if tt.service.opts.Mode != tt.expectedMode {
+				t.Errorf("Expected mode %q, got %q", tt.expectedMode, tt.service.opts.Mode)
+			}
+			if !reflect.DeepEqual(tt.service.opts.ICAOs, tt.expectedICAOs) {
+				t.Errorf("Expected ICAOs %v, got %v", tt.expectedICAOs, tt.service.opts.ICAOs)
+			}
+		})
+	}
+}
+
+func TestAviationSyncHandler_RefreshAirport(t *testing.T) {
+	facilityName := "ATWOOD-RAWLINS COUNTY CITY-COUNTY"
+	before := &dto.Airport{ID: 1, ICAO: "KADT", Status: dto.AirportStatusFailed, AttemptCount: 5}
+	after := &dto.Airport{ID: 1, ICAO: "KADT", FacilityName: &facilityName, Status: dto.AirportStatusDone}
+	tests := []struct {
+		name    string
+		service AviationSyncService
+		params  map[string]string
+		utils.ExpectedResult
+	}{
+		{
+			name:    "Success refreshed",
+			service: &mockSyncService{refresh: &dto.AirportRefresh{Before: before, After: after, Sync: &dto.SyncResponse{RunID: 9, Mode: dto.SyncModeTargeted, Total: 1, Success: 1}}},
+			params:  map[string]string{"id": "1"},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data:   dto.AirportRefresh{Before: before, After: after, Sync: &dto.SyncResponse{RunID: 9, Mode: dto.SyncModeTargeted, Total: 1, Success: 1}},
+			},
+		},
+		{
+			name:    "No data",
+			service: &mockSyncService{},
+			params:  map[string]string{"id": "1"},
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusOK,
+				Message: "No airport found",
+			},
+		},
+		{
+			name:    "Sync already in progress",
+			service: &mockSyncService{err: &service.SyncInProgressError{Run: &dto.SyncRun{ID: 4, Status: dto.SyncRunStatusRunning}}},
+			params:  map[string]string{"id": "1"},
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusConflict,
+				Data:    dto.SyncRun{ID: 4, Status: dto.SyncRunStatusRunning},
+				Message: "Error occurred",
+				Error:   "Sync already in progress",
+			},
+		},
+		{
+			name:    "Invalid id",
+			service: &mockSyncService{},
+			params:  map[string]string{"id": "A"},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid id",
+			},
+		},
+		{
+			name:    "Service error",
+			service: &mockSyncService{err: fmt.Errorf("DB error")},
+			params:  map[string]string{"id": "1"},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Failed to refresh airport",
+			},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
+			h := NewAviationSyncHandler(log, tt.service)
+			h.RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodPost, "/airport/refresh", nil)
+			req.SetPathValue("id", tt.params["id"])
+			rr := httptest.NewRecorder()
+
+			h.RefreshAirport(rr, req)
+
+			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestAviationSyncHandler_GetAllSyncRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		service AviationSyncService
+		utils.ExpectedResult
+	}{
+		{
+			name:    "Success with data",
+			service: &mockSyncService{runs: []dto.SyncRun{{ID: 1, Trigger: dto.SyncTriggerCron, Status: dto.SyncRunStatusDone}}},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data: dto.PaginatedResponse{
+					Page:     1,
+					PageSize: 10,
+					Data:     []dto.SyncRun{{ID: 1, Trigger: dto.SyncTriggerCron, Status: dto.SyncRunStatusDone}},
+				},
+			},
+		},
+		{
+			name:    "No data",
+			service: &mockSyncService{},
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusOK,
+				Message: "No sync runs found",
+			},
+		},
+		{
+			name:    "Service error",
+			service: &mockSyncService{err: fmt.Errorf("DB error")},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Failed to get all sync runs",
+			},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
+			h := NewAviationSyncHandler(log, tt.service)
+			h.RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/sync/runs", nil)
+			rr := httptest.NewRecorder()
+
+			h.GetAllSyncRun(rr, req)
+
+			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestAviationSyncHandler_GetSyncRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		service AviationSyncService
+		params  map[string]string
+		utils.ExpectedResult
+	}{
+		{
+			name: "Success with data",
+			service: &mockSyncService{run: &dto.SyncRun{ID: 1, Status: dto.SyncRunStatusDone, Items: []dto.SyncRunItem{
+				{SyncRunID: 1, ICAO: "KAVL", Outcome: dto.SyncOutcomeFetchError, Status: "PENDING"},
+			}}},
+			params: map[string]string{"id": "1"},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data: &dto.SyncRun{ID: 1, Status: dto.SyncRunStatusDone, Items: []dto.SyncRunItem{
+					{SyncRunID: 1, ICAO: "KAVL", Outcome: dto.SyncOutcomeFetchError, Status: "PENDING"},
+				}},
+			},
+		},
+		{
+			name:    "No data",
+			service: &mockSyncService{},
+			params:  map[string]string{"id": "1"},
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusOK,
+				Message: "No sync run found",
+			},
+		},
+		{
+			name:    "Invalid id",
+			service: &mockSyncService{},
+			params:  map[string]string{"id": "A"},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid id",
+			},
+		},
+		{
+			name:    "Service error",
+			service: &mockSyncService{err: fmt.Errorf("DB error")},
+			params:  map[string]string{"id": "1"},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Failed to get sync run",
+			},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
+			h := NewAviationSyncHandler(log, tt.service)
+			h.RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/sync/runs/", nil)
+			req.SetPathValue("id", tt.params["id"])
+			rr := httptest.NewRecorder()
+
+			h.GetSyncRun(rr, req)
+
+			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestAviationSyncHandler_GetSyncJob(t *testing.T) {
+	tests := []struct {
+		name    string
+		service AviationSyncService
+		params  map[string]string
+		utils.ExpectedResult
+	}{
+		{
+			name:    "Success with data",
+			service: &mockSyncService{job: &dto.SyncJob{ID: 1, Status: dto.SyncRunStatusRunning, Total: 65, Batches: 3, BatchesDone: 2, Success: 58, Failed: 2}},
+			params:  map[string]string{"id": "1"},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data:   dto.SyncJob{ID: 1, Status: dto.SyncRunStatusRunning, Total: 65, Batches: 3, BatchesDone: 2, Success: 58, Failed: 2},
+			},
+		},
+		{
+			name:    "No data",
+			service: &mockSyncService{},
+			params:  map[string]string{"id": "1"},
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusOK,
+				Message: "No sync job found",
+			},
+		},
+		{
+			name:    "Invalid id",
+			service: &mockSyncService{},
+			params:  map[string]string{"id": "A"},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid id",
+			},
+		},
+		{
+			name:    "Service error",
+			service: &mockSyncService{err: fmt.Errorf("DB error")},
+			params:  map[string]string{"id": "1"},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Failed to get sync job",
+			},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
+			h := NewAviationSyncHandler(log, tt.service)
+			h.RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/sync/jobs/", nil)
+			req.SetPathValue("id", tt.params["id"])
+			rr := httptest.NewRecorder()
+
+			h.GetSyncJob(rr, req)
+
+			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestAviationSyncHandler_CancelSyncJob(t *testing.T) {
+	tests := []struct {
+		name    string
+		service AviationSyncService
+		params  map[string]string
+		utils.ExpectedResult
+	}{
+		{
+			name:    "Success cancelled",
+			service: &mockSyncService{job: &dto.SyncJob{ID: 1, Status: dto.SyncRunStatusCancelled, Total: 65, Batches: 3, BatchesDone: 1, Success: 30}},
+			params:  map[string]string{"id": "1"},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data:   dto.SyncJob{ID: 1, Status: dto.SyncRunStatusCancelled, Total: 65, Batches: 3, BatchesDone: 1, Success: 30},
+			},
+		},
+		{
+			name:    "No data",
+			service: &mockSyncService{},
+			params:  map[string]string{"id": "1"},
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusOK,
+				Message: "No sync job found",
+			},
+		},
+		{
+			name:    "Cancellation requested of a job still running",
+			service: &mockSyncService{job: &dto.SyncJob{ID: 1, Status: dto.SyncRunStatusRunning, Total: 65, Batches: 3}},
+			params:  map[string]string{"id": "1"},
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusAccepted,
+				Message: "Sync job cancellation requested",
+				Data:    dto.SyncJob{ID: 1, Status: dto.SyncRunStatusRunning, Total: 65, Batches: 3},
+			},
+		},
+		{
+			name:    "Invalid id",
+			service: &mockSyncService{},
+			params:  map[string]string{"id": "A"},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid id",
+			},
+		},
+		{
+			name:    "Service error",
+			service: &mockSyncService{err: fmt.Errorf("DB error")},
+			params:  map[string]string{"id": "1"},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Failed to cancel sync job",
+			},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
+			h := NewAviationSyncHandler(log, tt.service)
+			h.RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodDelete, "/sync/jobs/", nil)
+			req.SetPathValue("id", tt.params["id"])
+			rr := httptest.NewRecorder()
+
+			h.CancelSyncJob(rr, req)
+
 			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
 				t.Error(err)
 			}
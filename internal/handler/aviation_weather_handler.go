@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"aviation-service/internal/dto"
+	"aviation-service/internal/service"
+)
+
+var icaoPattern = regexp.MustCompile(`^[A-Z0-9]{3,4}$`)
+
+type AviationWeatherHandler struct {
+	logger  *zap.SugaredLogger
+	service service.IAviationWeatherService
+}
+
+func NewAviationWeatherHandler(logger *zap.SugaredLogger, service service.IAviationWeatherService) *AviationWeatherHandler {
+	return &AviationWeatherHandler{
+		logger:  logger,
+		service: service,
+	}
+}
+
+func (h *AviationWeatherHandler) RegisterRoutes(r chi.Router) {
+	r.With(requireReader).Get("/airport/{icao}/metar", h.GetMetar)
+	r.With(requireReader).Get("/airport/{icao}/taf", h.GetTaf)
+}
+
+func (h *AviationWeatherHandler) GetMetar(w http.ResponseWriter, r *http.Request) {
+	icao := strings.ToUpper(r.PathValue("icao"))
+	if !icaoPattern.MatchString(icao) {
+		h.logger.Info("Failed to get METAR, invalid icao")
+		respondWithError(w, http.StatusBadRequest, "Invalid icao")
+		return
+	}
+
+	metar, err := h.service.GetMetar(r.Context(), icao)
+	if err != nil {
+		h.logger.Errorw("Failed to get METAR", "error", err, "icao", icao)
+		respondWithError(w, http.StatusBadRequest, "Failed to get METAR")
+		return
+	}
+
+	h.logger.Info("METAR data get successfully")
+	if metar == nil {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(nil, "No METAR found"))
+	} else {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(metar, ""))
+	}
+}
+
+func (h *AviationWeatherHandler) GetTaf(w http.ResponseWriter, r *http.Request) {
+	icao := strings.ToUpper(r.PathValue("icao"))
+	if !icaoPattern.MatchString(icao) {
+		h.logger.Info("Failed to get TAF, invalid icao")
+		respondWithError(w, http.StatusBadRequest, "Invalid icao")
+		return
+	}
+
+	taf, err := h.service.GetTaf(r.Context(), icao)
+	if err != nil {
+		h.logger.Errorw("Failed to get TAF", "error", err, "icao", icao)
+		respondWithError(w, http.StatusBadRequest, "Failed to get TAF")
+		return
+	}
+
+	h.logger.Info("TAF data get successfully")
+	if taf == nil {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(nil, "No TAF found"))
+	} else {
+		respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(taf, ""))
+	}
+}
@@ -0,0 +1,168 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/handler"
+	"aviation-service/internal/service"
+	utils "aviation-service/internal/testutils"
+	u "aviation-service/internal/utils"
+	"aviation-service/pkg/logger"
+	"aviation-service/pkg/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type mockAviationWeatherService struct {
+	metar *dto.Metar
+	taf   *dto.Taf
+	icao  string
+	err   error
+}
+
+func (m *mockAviationWeatherService) GetMetar(ctx context.Context, icao string) (*dto.Metar, error) {
+	m.icao = icao
+	return m.metar, m.err
+}
+
+func (m *mockAviationWeatherService) GetTaf(ctx context.Context, icao string) (*dto.Taf, error) {
+	m.icao = icao
+	return m.taf, m.err
+}
+
+func TestAviationWeatherHandler_GetMetar(t *testing.T) {
+	observedAt := time.Date(2025, time.October, 16, 18, 54, 0, 0, time.UTC)
+	metar := &dto.Metar{Station: "KAVL", Type: "METAR", ObservedAt: observedAt, Raw: "KAVL 161854Z 36006KT"}
+	tests := []struct {
+		name    string
+		service *mockAviationWeatherService
+		path    string
+		utils.ExpectedResult
+	}{
+		{
+			name:    "Success with data",
+			service: &mockAviationWeatherService{metar: metar},
+			path:    "/airport/kavl/metar",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data:   metar,
+			},
+		},
+		{
+			name:    "No data",
+			service: &mockAviationWeatherService{},
+			path:    "/airport/KAVL/metar",
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusOK,
+				Message: "No METAR found",
+			},
+		},
+		{
+			name:    "Invalid icao",
+			service: &mockAviationWeatherService{},
+			path:    "/airport/K-AVL/metar",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Invalid icao",
+			},
+		},
+		{
+			name:    "Service error",
+			service: &mockAviationWeatherService{err: fmt.Errorf("API error")},
+			path:    "/airport/KAVL/metar",
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Failed to get METAR",
+			},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The airport routes are registered too so the nested paths are resolved the same way as in the server
+			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
+			NewAirportHandler(log, nil, u.NewAirportValidator()).RegisterRoutes(r)
+			NewAviationWeatherHandler(log, tt.service).RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
+				t.Error(err)
+			}
+			if tt.Status == http.StatusOK && tt.service.icao != "KAVL" {
+				t.Errorf("Expected icao KAVL, got %q", tt.service.icao)
+			}
+		})
+	}
+}
+
+func TestAviationWeatherHandler_GetTaf(t *testing.T) {
+	validFrom := time.Date(2025, time.October, 16, 18, 0, 0, 0, time.UTC)
+	taf := &dto.Taf{
+		Station:   "KAVL",
+		ValidFrom: validFrom,
+		ValidTo:   validFrom.Add(24 * time.Hour),
+		Forecasts: []dto.TafForecast{{Change: dto.TafChangeBase}},
+		Raw:       "TAF KAVL 161720Z 1618/1718 36006KT",
+	}
+	tests := []struct {
+		name    string
+		service service.IAviationWeatherService
+		utils.ExpectedResult
+	}{
+		{
+			name:    "Success with data",
+			service: &mockAviationWeatherService{taf: taf},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data:   taf,
+			},
+		},
+		{
+			name:    "No data",
+			service: &mockAviationWeatherService{},
+			ExpectedResult: utils.ExpectedResult{
+				Status:  http.StatusOK,
+				Message: "No TAF found",
+			},
+		},
+		{
+			name:    "Service error",
+			service: &mockAviationWeatherService{err: fmt.Errorf("API error")},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusBadRequest,
+				Error:  "Failed to get TAF",
+			},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAviationWeatherHandler(log, tt.service)
+
+			req := httptest.NewRequest(http.MethodGet, "/airport/KAVL/taf", nil)
+			req.SetPathValue("icao", "KAVL")
+			rr := httptest.NewRecorder()
+
+			h.GetTaf(rr, req)
+
+			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
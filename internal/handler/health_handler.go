@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"aviation-service/internal/dto"
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// Every readiness check gets this long before its dependency counts as down
+const healthCheckTimeout = 2 * time.Second
+
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type CircuitReporter interface {
+	CircuitState() string
+}
+
+type HealthHandler struct {
+	logger   *zap.SugaredLogger
+	checks   []HealthCheck
+	circuits map[string]CircuitReporter
+	draining atomic.Bool
+}
+
+func NewHealthHandler(logger *zap.SugaredLogger, checks []HealthCheck, circuits map[string]CircuitReporter) *HealthHandler {
+	return &HealthHandler{
+		logger:   logger,
+		checks:   checks,
+		circuits: circuits,
+	}
+}
+
+func (h *HealthHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/healthz", h.Liveness)
+	r.Get("/readyz", h.Readiness)
+}
+
+// Drain makes the readiness probe fail, so the load balancer stops sending requests while the server shuts down
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+func (h *HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(dto.Readiness{Status: dto.HealthStatusOK}, ""))
+}
+
+// Readiness pings every dependency, an open upstream circuit is reported but does not make the service unready
+// since searches still answer from the database
+func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	readiness := dto.Readiness{Status: dto.HealthStatusOK, Checks: map[string]string{}, Circuits: map[string]string{}}
+	for _, check := range h.checks {
+		ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+		err := check.Check(ctx)
+		cancel()
+		if err != nil {
+			h.logger.Errorw("Readiness check failed", "check", check.Name, "error", err)
+			readiness.Status = dto.HealthStatusUnavailable
+			readiness.Checks[check.Name] = err.Error()
+			continue
+		}
+		readiness.Checks[check.Name] = dto.HealthStatusOK
+	}
+	for name, circuit := range h.circuits {
+		readiness.Circuits[name] = circuit.CircuitState()
+	}
+
+	if h.draining.Load() {
+		readiness.Status = dto.HealthStatusDraining
+	}
+	if readiness.Status != dto.HealthStatusOK {
+		respondWithJSON(w, http.StatusServiceUnavailable, dto.Response{
+			Success: false,
+			Message: "Error occurred",
+			Data:    readiness,
+			Error:   "Service not ready",
+		})
+		return
+	}
+	respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(readiness, ""))
+}
@@ -0,0 +1,116 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/handler"
+	utils "aviation-service/internal/testutils"
+	"aviation-service/pkg/logger"
+)
+
+type mockCircuit struct {
+	state string
+}
+
+func (m mockCircuit) CircuitState() string {
+	return m.state
+}
+
+func TestHealthHandler_Liveness(t *testing.T) {
+	log := logger.GetLogger()
+	defer log.Sync()
+
+	h := NewHealthHandler(log, []HealthCheck{{Name: "postgres", Check: func(ctx context.Context) error { return errors.New("connection refused") }}}, nil)
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rr := httptest.NewRecorder()
+
+	h.Liveness(rr, req)
+
+	// Liveness never depends on the dependencies
+	if err := utils.AssertHandlerResponse(t, rr, utils.ExpectedResult{
+		Status: http.StatusOK,
+		Data:   dto.Readiness{Status: dto.HealthStatusOK},
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHealthHandler_Readiness(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	circuits := map[string]CircuitReporter{"aviationapi": mockCircuit{state: "OPEN"}}
+
+	tests := []struct {
+		name     string
+		checks   []HealthCheck
+		draining bool
+		utils.ExpectedResult
+	}{
+		{
+			name:   "Ready",
+			checks: []HealthCheck{{Name: "postgres", Check: ok}, {Name: "redis", Check: ok}},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data: dto.Readiness{
+					Status:   dto.HealthStatusOK,
+					Checks:   map[string]string{"postgres": dto.HealthStatusOK, "redis": dto.HealthStatusOK},
+					Circuits: map[string]string{"aviationapi": "OPEN"},
+				},
+			},
+		},
+		{
+			name: "Dependency down",
+			checks: []HealthCheck{
+				{Name: "postgres", Check: ok},
+				{Name: "redis", Check: func(ctx context.Context) error { return errors.New("Cache ping failed") }},
+			},
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusServiceUnavailable,
+				Data: dto.Readiness{
+					Status:   dto.HealthStatusUnavailable,
+					Checks:   map[string]string{"postgres": dto.HealthStatusOK, "redis": "Cache ping failed"},
+					Circuits: map[string]string{"aviationapi": "OPEN"},
+				},
+				Error: "Service not ready",
+			},
+		},
+		{
+			name:     "Draining",
+			checks:   []HealthCheck{{Name: "postgres", Check: ok}},
+			draining: true,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusServiceUnavailable,
+				Data: dto.Readiness{
+					Status:   dto.HealthStatusDraining,
+					Checks:   map[string]string{"postgres": dto.HealthStatusOK},
+					Circuits: map[string]string{"aviationapi": "OPEN"},
+				},
+				Error: "Service not ready",
+			},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler(log, tt.checks, circuits)
+			if tt.draining {
+				h.Drain()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			rr := httptest.NewRecorder()
+
+			h.Readiness(rr, req)
+
+			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
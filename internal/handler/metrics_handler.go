@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsHandler exposes the Prometheus default registry
+type MetricsHandler struct{}
+
+func NewMetricsHandler() *MetricsHandler {
+	return &MetricsHandler{}
+}
+
+func (h *MetricsHandler) RegisterRoutes(r chi.Router) {
+	r.Handle("/metrics", promhttp.Handler())
+}
@@ -2,10 +2,12 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"aviation-service/internal/dto"
 	"aviation-service/pkg/logger"
+	"aviation-service/pkg/middleware"
 )
 
 func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
@@ -24,3 +26,52 @@ func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 func respondWithError(w http.ResponseWriter, code int, message interface{}) {
 	respondWithJSON(w, code, dto.NewErrorResponse(message, "Error occurred"))
 }
+
+// respondWithValidationError lists every violated field with 422, a validator error without field details falls
+// back to 400 with message
+func respondWithValidationError(w http.ResponseWriter, err error, message string) {
+	var validationErrs dto.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		respondWithError(w, http.StatusUnprocessableEntity, validationErrs)
+		return
+	}
+	respondWithError(w, http.StatusBadRequest, message)
+}
+
+var (
+	requireReader   = requireRole(middleware.RoleReader)
+	requireEditor   = requireRole(middleware.RoleEditor)
+	requireOperator = requireRole(middleware.RoleOperator)
+)
+
+// requireRole rejects callers the auth middleware did not grant role, with 401 when the credentials are missing
+// or invalid and 403 when the caller is known but its role is too low
+func requireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := middleware.AuthErrorFromContext(r.Context()); err != nil {
+				var credentialsErr *middleware.CredentialsError
+				if errors.As(err, &credentialsErr) {
+					logger.Infow("Rejected request credentials", "reason", credentialsErr.Reason, "path", r.URL.Path)
+					respondWithError(w, http.StatusUnauthorized, credentialsErr.Reason)
+					return
+				}
+				logger.Errorw("Failed to authenticate request", "error", err, "path", r.URL.Path)
+				respondWithError(w, http.StatusInternalServerError, "Failed to authenticate")
+				return
+			}
+
+			identity := middleware.IdentityFromContext(r.Context())
+			if identity == nil {
+				respondWithError(w, http.StatusUnauthorized, "Missing credentials")
+				return
+			}
+			if !identity.Grants(role) {
+				logger.Infow("Rejected request role", "actor", identity.Actor(), "role", identity.Role, "required", role, "path", r.URL.Path)
+				respondWithError(w, http.StatusForbidden, "Insufficient role")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -5,6 +5,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"aviation-service/internal/dto"
+	utils "aviation-service/internal/testutils"
+	"aviation-service/pkg/middleware"
 )
 
 type fakeWriter struct{}
@@ -50,3 +54,125 @@ func TestRespondWithJSON(t *testing.T) {
 		})
 	}
 }
+
+type fakeAuthenticator struct {
+	identity *middleware.Identity
+	err      error
+}
+
+func (a *fakeAuthenticator) Authenticate(r *http.Request) (*middleware.Identity, error) {
+	return a.identity, a.err
+}
+
+func TestRequireRole(t *testing.T) {
+	editor := &middleware.Identity{Subject: "ci", Role: middleware.RoleEditor, Method: middleware.AuthMethodAPIKey}
+	tests := []struct {
+		name          string
+		anonymousRole string
+		authenticator *fakeAuthenticator
+		required      string
+		utils.ExpectedResult
+	}{
+		{
+			name:          "Success with sufficient role",
+			authenticator: &fakeAuthenticator{identity: editor},
+			required:      middleware.RoleEditor,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data:   map[string]string{"actor": "api_key:ci"},
+			},
+		},
+		{
+			name:          "Success with higher role",
+			authenticator: &fakeAuthenticator{identity: editor},
+			required:      middleware.RoleReader,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+			},
+		},
+		{
+			name:          "Success anonymous reader",
+			anonymousRole: middleware.RoleReader,
+			authenticator: &fakeAuthenticator{},
+			required:      middleware.RoleReader,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusOK,
+				Data:   map[string]string{"actor": "anonymous:anonymous"},
+			},
+		},
+		{
+			name:          "Error insufficient role",
+			authenticator: &fakeAuthenticator{identity: editor},
+			required:      middleware.RoleOperator,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusForbidden,
+				Error:  "Insufficient role",
+			},
+		},
+		{
+			name:          "Error token without service scope",
+			authenticator: &fakeAuthenticator{identity: &middleware.Identity{Subject: "jdoe", Method: middleware.AuthMethodJWT}},
+			required:      middleware.RoleReader,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusForbidden,
+				Error:  "Insufficient role",
+			},
+		},
+		{
+			name:          "Error anonymous writer",
+			anonymousRole: middleware.RoleReader,
+			authenticator: &fakeAuthenticator{},
+			required:      middleware.RoleEditor,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusForbidden,
+				Error:  "Insufficient role",
+			},
+		},
+		{
+			name:          "Error missing credentials",
+			authenticator: &fakeAuthenticator{},
+			required:      middleware.RoleReader,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusUnauthorized,
+				Error:  "Missing credentials",
+			},
+		},
+		{
+			name:          "Error invalid credentials are not anonymous",
+			anonymousRole: middleware.RoleReader,
+			authenticator: &fakeAuthenticator{err: &middleware.CredentialsError{Reason: "Invalid API key"}},
+			required:      middleware.RoleReader,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusUnauthorized,
+				Error:  "Invalid API key",
+			},
+		},
+		{
+			name:          "Error authenticator failed",
+			authenticator: &fakeAuthenticator{err: fmt.Errorf("connection refused")},
+			required:      middleware.RoleReader,
+			ExpectedResult: utils.ExpectedResult{
+				Status: http.StatusInternalServerError,
+				Error:  "Failed to authenticate",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				identity := middleware.IdentityFromContext(r.Context())
+				respondWithJSON(w, http.StatusOK, dto.NewSuccessResponse(map[string]string{"actor": identity.Actor()}, ""))
+			})
+			h := middleware.Authenticate(tt.anonymousRole, tt.authenticator)(requireRole(tt.required)(next))
+
+			req := httptest.NewRequest(http.MethodGet, "/airport", nil)
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+
+			if err := utils.AssertHandlerResponse(t, rr, tt.ExpectedResult); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -24,7 +24,7 @@ func NewWeatherHandler(logger *zap.SugaredLogger, service service.IWeatherServic
 
 func (h *WeatherHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/weather", func(r chi.Router) {
-		r.Get("/", h.GetWeather)
+		r.With(requireReader).Get("/", h.GetWeather)
 	})
 }
 
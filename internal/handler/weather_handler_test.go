@@ -12,6 +12,7 @@ import (
 	utils "aviation-service/internal/testutils"
 	. "aviation-service/internal/handler"
 	"aviation-service/pkg/logger"
+	"aviation-service/pkg/middleware"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -25,6 +26,10 @@ func (m *mockWeatherService) GetWeather(ctx context.Context, city string) (*dto.
 	return m.weatherResponse, m.err
 }
 
+func (m *mockWeatherService) GetWeatherByCoordinates(ctx context.Context, lat, lon float64) (*dto.Weather, error) {
+	return m.weatherResponse, m.err
+}
+
 func TestWeatherHandler_GetWeather(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -66,6 +71,7 @@ func TestWeatherHandler_GetWeather(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := chi.NewRouter()
+			r.Use(middleware.Authenticate(middleware.RoleOperator))
 			h := NewWeatherHandler(log, tt.service)
 			h.RegisterRoutes(r)
 
@@ -0,0 +1,257 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mock
+
+import (
+	"aviation-service/internal/dto"
+	"aviation-service/internal/repository"
+	"context"
+	"sync"
+)
+
+// Ensure, that IAirportConflictRepositoryMock does implement repository.IAirportConflictRepository.
+// If this is not the case, regenerate this file with moq.
+var _ repository.IAirportConflictRepository = &IAirportConflictRepositoryMock{}
+
+// IAirportConflictRepositoryMock is a mock implementation of repository.IAirportConflictRepository.
+//
+//	func TestSomethingThatUsesIAirportConflictRepository(t *testing.T) {
+//
+//		// make and configure a mocked repository.IAirportConflictRepository
+//		mockedIAirportConflictRepository := &IAirportConflictRepositoryMock{
+//			GetAllFunc: func(ctx context.Context, icao string, includeResolved bool, limit int, offset int) ([]dto.AirportConflict, error) {
+//				panic("mock out the GetAll method")
+//			},
+//			GetByIdFunc: func(ctx context.Context, id int) (*dto.AirportConflict, error) {
+//				panic("mock out the GetById method")
+//			},
+//			ResolveFunc: func(ctx context.Context, id int, resolution string) error {
+//				panic("mock out the Resolve method")
+//			},
+//			UpsertFunc: func(ctx context.Context, conflicts []dto.AirportConflict) error {
+//				panic("mock out the Upsert method")
+//			},
+//		}
+//
+//		// use mockedIAirportConflictRepository in code that requires repository.IAirportConflictRepository
+//		// and then make assertions.
+//
+//	}
+type IAirportConflictRepositoryMock struct {
+	// GetAllFunc mocks the GetAll method.
+	GetAllFunc func(ctx context.Context, icao string, includeResolved bool, limit int, offset int) ([]dto.AirportConflict, error)
+
+	// GetByIdFunc mocks the GetById method.
+	GetByIdFunc func(ctx context.Context, id int) (*dto.AirportConflict, error)
+
+	// ResolveFunc mocks the Resolve method.
+	ResolveFunc func(ctx context.Context, id int, resolution string) error
+
+	// UpsertFunc mocks the Upsert method.
+	UpsertFunc func(ctx context.Context, conflicts []dto.AirportConflict) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetAll holds details about calls to the GetAll method.
+		GetAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Icao is the icao argument value.
+			Icao string
+			// IncludeResolved is the includeResolved argument value.
+			IncludeResolved bool
+			// Limit is the limit argument value.
+			Limit int
+			// Offset is the offset argument value.
+			Offset int
+		}
+		// GetById holds details about calls to the GetById method.
+		GetById []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+		}
+		// Resolve holds details about calls to the Resolve method.
+		Resolve []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+			// Resolution is the resolution argument value.
+			Resolution string
+		}
+		// Upsert holds details about calls to the Upsert method.
+		Upsert []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Conflicts is the conflicts argument value.
+			Conflicts []dto.AirportConflict
+		}
+	}
+	lockGetAll  sync.RWMutex
+	lockGetById sync.RWMutex
+	lockResolve sync.RWMutex
+	lockUpsert  sync.RWMutex
+}
+
+// GetAll calls GetAllFunc.
+func (mock *IAirportConflictRepositoryMock) GetAll(ctx context.Context, icao string, includeResolved bool, limit int, offset int) ([]dto.AirportConflict, error) {
+	if mock.GetAllFunc == nil {
+		panic("IAirportConflictRepositoryMock.GetAllFunc: method is nil but IAirportConflictRepository.GetAll was just called")
+	}
+	callInfo := struct {
+		Ctx             context.Context
+		Icao            string
+		IncludeResolved bool
+		Limit           int
+		Offset          int
+	}{
+		Ctx:             ctx,
+		Icao:            icao,
+		IncludeResolved: includeResolved,
+		Limit:           limit,
+		Offset:          offset,
+	}
+	mock.lockGetAll.Lock()
+	mock.calls.GetAll = append(mock.calls.GetAll, callInfo)
+	mock.lockGetAll.Unlock()
+	return mock.GetAllFunc(ctx, icao, includeResolved, limit, offset)
+}
+
+// GetAllCalls gets all the calls that were made to GetAll.
+// Check the length with:
+//
+//	len(mockedIAirportConflictRepository.GetAllCalls())
+func (mock *IAirportConflictRepositoryMock) GetAllCalls() []struct {
+	Ctx             context.Context
+	Icao            string
+	IncludeResolved bool
+	Limit           int
+	Offset          int
+} {
+	var calls []struct {
+		Ctx             context.Context
+		Icao            string
+		IncludeResolved bool
+		Limit           int
+		Offset          int
+	}
+	mock.lockGetAll.RLock()
+	calls = mock.calls.GetAll
+	mock.lockGetAll.RUnlock()
+	return calls
+}
+
+// GetById calls GetByIdFunc.
+func (mock *IAirportConflictRepositoryMock) GetById(ctx context.Context, id int) (*dto.AirportConflict, error) {
+	if mock.GetByIdFunc == nil {
+		panic("IAirportConflictRepositoryMock.GetByIdFunc: method is nil but IAirportConflictRepository.GetById was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetById.Lock()
+	mock.calls.GetById = append(mock.calls.GetById, callInfo)
+	mock.lockGetById.Unlock()
+	return mock.GetByIdFunc(ctx, id)
+}
+
+// GetByIdCalls gets all the calls that were made to GetById.
+// Check the length with:
+//
+//	len(mockedIAirportConflictRepository.GetByIdCalls())
+func (mock *IAirportConflictRepositoryMock) GetByIdCalls() []struct {
+	Ctx context.Context
+	ID  int
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int
+	}
+	mock.lockGetById.RLock()
+	calls = mock.calls.GetById
+	mock.lockGetById.RUnlock()
+	return calls
+}
+
+// Resolve calls ResolveFunc.
+func (mock *IAirportConflictRepositoryMock) Resolve(ctx context.Context, id int, resolution string) error {
+	if mock.ResolveFunc == nil {
+		panic("IAirportConflictRepositoryMock.ResolveFunc: method is nil but IAirportConflictRepository.Resolve was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		ID         int
+		Resolution string
+	}{
+		Ctx:        ctx,
+		ID:         id,
+		Resolution: resolution,
+	}
+	mock.lockResolve.Lock()
+	mock.calls.Resolve = append(mock.calls.Resolve, callInfo)
+	mock.lockResolve.Unlock()
+	return mock.ResolveFunc(ctx, id, resolution)
+}
+
+// ResolveCalls gets all the calls that were made to Resolve.
+// Check the length with:
+//
+//	len(mockedIAirportConflictRepository.ResolveCalls())
+func (mock *IAirportConflictRepositoryMock) ResolveCalls() []struct {
+	Ctx        context.Context
+	ID         int
+	Resolution string
+} {
+	var calls []struct {
+		Ctx        context.Context
+		ID         int
+		Resolution string
+	}
+	mock.lockResolve.RLock()
+	calls = mock.calls.Resolve
+	mock.lockResolve.RUnlock()
+	return calls
+}
+
+// Upsert calls UpsertFunc.
+func (mock *IAirportConflictRepositoryMock) Upsert(ctx context.Context, conflicts []dto.AirportConflict) error {
+	if mock.UpsertFunc == nil {
+		panic("IAirportConflictRepositoryMock.UpsertFunc: method is nil but IAirportConflictRepository.Upsert was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Conflicts []dto.AirportConflict
+	}{
+		Ctx:       ctx,
+		Conflicts: conflicts,
+	}
+	mock.lockUpsert.Lock()
+	mock.calls.Upsert = append(mock.calls.Upsert, callInfo)
+	mock.lockUpsert.Unlock()
+	return mock.UpsertFunc(ctx, conflicts)
+}
+
+// UpsertCalls gets all the calls that were made to Upsert.
+// Check the length with:
+//
+//	len(mockedIAirportConflictRepository.UpsertCalls())
+func (mock *IAirportConflictRepositoryMock) UpsertCalls() []struct {
+	Ctx       context.Context
+	Conflicts []dto.AirportConflict
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Conflicts []dto.AirportConflict
+	}
+	mock.lockUpsert.RLock()
+	calls = mock.calls.Upsert
+	mock.lockUpsert.RUnlock()
+	return calls
+}
@@ -8,6 +8,7 @@ import (
 	"aviation-service/internal/repository"
 	"context"
 	"sync"
+	"time"
 )
 
 // Ensure, that IAirportRepositoryMock does implement repository.IAirportRepository.
@@ -20,30 +21,57 @@ var _ repository.IAirportRepository = &IAirportRepositoryMock{}
 //
 //		// make and configure a mocked repository.IAirportRepository
 //		mockedIAirportRepository := &IAirportRepositoryMock{
+//			ClaimDueFunc: func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
+//				panic("mock out the ClaimDue method")
+//			},
+//			CountByStatusFunc: func(ctx context.Context) (map[string]int, error) {
+//				panic("mock out the CountByStatus method")
+//			},
 //			DeleteFunc: func(ctx context.Context, id int) error {
 //				panic("mock out the Delete method")
 //			},
 //			GetAllFunc: func(ctx context.Context, limit int, offset int) ([]dto.Airport, error) {
 //				panic("mock out the GetAll method")
 //			},
-//			GetAllPendingFunc: func(ctx context.Context) ([]dto.Airport, error) {
-//				panic("mock out the GetAllPending method")
-//			},
 //			GetByICAOOrFacilityNameFunc: func(ctx context.Context, icao string, facilityName string, limit int, offset int) ([]dto.Airport, error) {
 //				panic("mock out the GetByICAOOrFacilityName method")
 //			},
+//			GetByICAOsFunc: func(ctx context.Context, icaos []string) ([]dto.Airport, error) {
+//				panic("mock out the GetByICAOs method")
+//			},
 //			GetByIdFunc: func(ctx context.Context, id int) (*dto.Airport, error) {
 //				panic("mock out the GetById method")
 //			},
+//			GetByStatusFunc: func(ctx context.Context, status string) ([]dto.Airport, error) {
+//				panic("mock out the GetByStatus method")
+//			},
+//			GetHistoryFunc: func(ctx context.Context, airportID int, limit int, offset int) ([]dto.AirportHistory, error) {
+//				panic("mock out the GetHistory method")
+//			},
+//			GetNearbyFunc: func(ctx context.Context, lat float64, lon float64, radiusNm float64, limit int, offset int) ([]dto.NearbyAirport, error) {
+//				panic("mock out the GetNearby method")
+//			},
+//			GetStaleFunc: func(ctx context.Context, syncedBefore time.Time) ([]dto.Airport, error) {
+//				panic("mock out the GetStale method")
+//			},
 //			InsertFunc: func(ctx context.Context, airport *dto.Airport) (*dto.Airport, error) {
 //				panic("mock out the Insert method")
 //			},
+//			StreamByICAOOrFacilityNameFunc: func(ctx context.Context, icao string, facilityName string, fn func(airport *dto.Airport) error) error {
+//				panic("mock out the StreamByICAOOrFacilityName method")
+//			},
 //			UpdateByICAOFunc: func(ctx context.Context, airports []dto.Airport) error {
 //				panic("mock out the UpdateByICAO method")
 //			},
 //			UpdateByIdFunc: func(ctx context.Context, airport *dto.Airport) (*dto.Airport, error) {
 //				panic("mock out the UpdateById method")
 //			},
+//			UpdateSyncStateFunc: func(ctx context.Context, airports []dto.Airport) error {
+//				panic("mock out the UpdateSyncState method")
+//			},
+//			UpsertByICAOFunc: func(ctx context.Context, airport *dto.Airport) (bool, error) {
+//				panic("mock out the UpsertByICAO method")
+//			},
 //		}
 //
 //		// use mockedIAirportRepository in code that requires repository.IAirportRepository
@@ -51,32 +79,71 @@ var _ repository.IAirportRepository = &IAirportRepositoryMock{}
 //
 //	}
 type IAirportRepositoryMock struct {
+	// ClaimDueFunc mocks the ClaimDue method.
+	ClaimDueFunc func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error)
+
+	// CountByStatusFunc mocks the CountByStatus method.
+	CountByStatusFunc func(ctx context.Context) (map[string]int, error)
+
 	// DeleteFunc mocks the Delete method.
 	DeleteFunc func(ctx context.Context, id int) error
 
 	// GetAllFunc mocks the GetAll method.
 	GetAllFunc func(ctx context.Context, limit int, offset int) ([]dto.Airport, error)
 
-	// GetAllPendingFunc mocks the GetAllPending method.
-	GetAllPendingFunc func(ctx context.Context) ([]dto.Airport, error)
-
 	// GetByICAOOrFacilityNameFunc mocks the GetByICAOOrFacilityName method.
 	GetByICAOOrFacilityNameFunc func(ctx context.Context, icao string, facilityName string, limit int, offset int) ([]dto.Airport, error)
 
+	// GetByICAOsFunc mocks the GetByICAOs method.
+	GetByICAOsFunc func(ctx context.Context, icaos []string) ([]dto.Airport, error)
+
 	// GetByIdFunc mocks the GetById method.
 	GetByIdFunc func(ctx context.Context, id int) (*dto.Airport, error)
 
+	// GetByStatusFunc mocks the GetByStatus method.
+	GetByStatusFunc func(ctx context.Context, status string) ([]dto.Airport, error)
+
+	// GetHistoryFunc mocks the GetHistory method.
+	GetHistoryFunc func(ctx context.Context, airportID int, limit int, offset int) ([]dto.AirportHistory, error)
+
+	// GetNearbyFunc mocks the GetNearby method.
+	GetNearbyFunc func(ctx context.Context, lat float64, lon float64, radiusNm float64, limit int, offset int) ([]dto.NearbyAirport, error)
+
+	// GetStaleFunc mocks the GetStale method.
+	GetStaleFunc func(ctx context.Context, syncedBefore time.Time) ([]dto.Airport, error)
+
 	// InsertFunc mocks the Insert method.
 	InsertFunc func(ctx context.Context, airport *dto.Airport) (*dto.Airport, error)
 
+	// StreamByICAOOrFacilityNameFunc mocks the StreamByICAOOrFacilityName method.
+	StreamByICAOOrFacilityNameFunc func(ctx context.Context, icao string, facilityName string, fn func(airport *dto.Airport) error) error
+
 	// UpdateByICAOFunc mocks the UpdateByICAO method.
 	UpdateByICAOFunc func(ctx context.Context, airports []dto.Airport) error
 
 	// UpdateByIdFunc mocks the UpdateById method.
 	UpdateByIdFunc func(ctx context.Context, airport *dto.Airport) (*dto.Airport, error)
 
+	// UpdateSyncStateFunc mocks the UpdateSyncState method.
+	UpdateSyncStateFunc func(ctx context.Context, airports []dto.Airport) error
+
+	// UpsertByICAOFunc mocks the UpsertByICAO method.
+	UpsertByICAOFunc func(ctx context.Context, airport *dto.Airport) (bool, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
+		// ClaimDue holds details about calls to the ClaimDue method.
+		ClaimDue []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// LeaseUntil is the leaseUntil argument value.
+			LeaseUntil time.Time
+		}
+		// CountByStatus holds details about calls to the CountByStatus method.
+		CountByStatus []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
 		// Delete holds details about calls to the Delete method.
 		Delete []struct {
 			// Ctx is the ctx argument value.
@@ -93,11 +160,6 @@ type IAirportRepositoryMock struct {
 			// Offset is the offset argument value.
 			Offset int
 		}
-		// GetAllPending holds details about calls to the GetAllPending method.
-		GetAllPending []struct {
-			// Ctx is the ctx argument value.
-			Ctx context.Context
-		}
 		// GetByICAOOrFacilityName holds details about calls to the GetByICAOOrFacilityName method.
 		GetByICAOOrFacilityName []struct {
 			// Ctx is the ctx argument value.
@@ -111,6 +173,13 @@ type IAirportRepositoryMock struct {
 			// Offset is the offset argument value.
 			Offset int
 		}
+		// GetByICAOs holds details about calls to the GetByICAOs method.
+		GetByICAOs []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Icaos is the icaos argument value.
+			Icaos []string
+		}
 		// GetById holds details about calls to the GetById method.
 		GetById []struct {
 			// Ctx is the ctx argument value.
@@ -118,6 +187,46 @@ type IAirportRepositoryMock struct {
 			// ID is the id argument value.
 			ID int
 		}
+		// GetByStatus holds details about calls to the GetByStatus method.
+		GetByStatus []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Status is the status argument value.
+			Status string
+		}
+		// GetHistory holds details about calls to the GetHistory method.
+		GetHistory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// AirportID is the airportID argument value.
+			AirportID int
+			// Limit is the limit argument value.
+			Limit int
+			// Offset is the offset argument value.
+			Offset int
+		}
+		// GetNearby holds details about calls to the GetNearby method.
+		GetNearby []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Lat is the lat argument value.
+			Lat float64
+			// Lon is the lon argument value.
+			Lon float64
+			// RadiusNm is the radiusNm argument value.
+			RadiusNm float64
+			// Limit is the limit argument value.
+			Limit int
+			// Offset is the offset argument value.
+			Offset int
+		}
+		// GetStale holds details about calls to the GetStale method.
+		GetStale []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SyncedBefore is the syncedBefore argument value.
+			SyncedBefore time.Time
+		}
 		// Insert holds details about calls to the Insert method.
 		Insert []struct {
 			// Ctx is the ctx argument value.
@@ -125,6 +234,17 @@ type IAirportRepositoryMock struct {
 			// Airport is the airport argument value.
 			Airport *dto.Airport
 		}
+		// StreamByICAOOrFacilityName holds details about calls to the StreamByICAOOrFacilityName method.
+		StreamByICAOOrFacilityName []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Icao is the icao argument value.
+			Icao string
+			// FacilityName is the facilityName argument value.
+			FacilityName string
+			// Fn is the fn argument value.
+			Fn func(airport *dto.Airport) error
+		}
 		// UpdateByICAO holds details about calls to the UpdateByICAO method.
 		UpdateByICAO []struct {
 			// Ctx is the ctx argument value.
@@ -139,15 +259,106 @@ type IAirportRepositoryMock struct {
 			// Airport is the airport argument value.
 			Airport *dto.Airport
 		}
+		// UpdateSyncState holds details about calls to the UpdateSyncState method.
+		UpdateSyncState []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Airports is the airports argument value.
+			Airports []dto.Airport
+		}
+		// UpsertByICAO holds details about calls to the UpsertByICAO method.
+		UpsertByICAO []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Airport is the airport argument value.
+			Airport *dto.Airport
+		}
+	}
+	lockClaimDue                   sync.RWMutex
+	lockCountByStatus              sync.RWMutex
+	lockDelete                     sync.RWMutex
+	lockGetAll                     sync.RWMutex
+	lockGetByICAOOrFacilityName    sync.RWMutex
+	lockGetByICAOs                 sync.RWMutex
+	lockGetById                    sync.RWMutex
+	lockGetByStatus                sync.RWMutex
+	lockGetHistory                 sync.RWMutex
+	lockGetNearby                  sync.RWMutex
+	lockGetStale                   sync.RWMutex
+	lockInsert                     sync.RWMutex
+	lockStreamByICAOOrFacilityName sync.RWMutex
+	lockUpdateByICAO               sync.RWMutex
+	lockUpdateById                 sync.RWMutex
+	lockUpdateSyncState            sync.RWMutex
+	lockUpsertByICAO               sync.RWMutex
+}
+
+// ClaimDue calls ClaimDueFunc.
+func (mock *IAirportRepositoryMock) ClaimDue(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
+	if mock.ClaimDueFunc == nil {
+		panic("IAirportRepositoryMock.ClaimDueFunc: method is nil but IAirportRepository.ClaimDue was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		LeaseUntil time.Time
+	}{
+		Ctx:        ctx,
+		LeaseUntil: leaseUntil,
+	}
+	mock.lockClaimDue.Lock()
+	mock.calls.ClaimDue = append(mock.calls.ClaimDue, callInfo)
+	mock.lockClaimDue.Unlock()
+	return mock.ClaimDueFunc(ctx, leaseUntil)
+}
+
+// ClaimDueCalls gets all the calls that were made to ClaimDue.
+// Check the length with:
+//
+//	len(mockedIAirportRepository.ClaimDueCalls())
+func (mock *IAirportRepositoryMock) ClaimDueCalls() []struct {
+	Ctx        context.Context
+	LeaseUntil time.Time
+} {
+	var calls []struct {
+		Ctx        context.Context
+		LeaseUntil time.Time
+	}
+	mock.lockClaimDue.RLock()
+	calls = mock.calls.ClaimDue
+	mock.lockClaimDue.RUnlock()
+	return calls
+}
+
+// CountByStatus calls CountByStatusFunc.
+func (mock *IAirportRepositoryMock) CountByStatus(ctx context.Context) (map[string]int, error) {
+	if mock.CountByStatusFunc == nil {
+		panic("IAirportRepositoryMock.CountByStatusFunc: method is nil but IAirportRepository.CountByStatus was just called")
 	}
-	lockDelete                  sync.RWMutex
-	lockGetAll                  sync.RWMutex
-	lockGetAllPending           sync.RWMutex
-	lockGetByICAOOrFacilityName sync.RWMutex
-	lockGetById                 sync.RWMutex
-	lockInsert                  sync.RWMutex
-	lockUpdateByICAO            sync.RWMutex
-	lockUpdateById              sync.RWMutex
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockCountByStatus.Lock()
+	mock.calls.CountByStatus = append(mock.calls.CountByStatus, callInfo)
+	mock.lockCountByStatus.Unlock()
+	return mock.CountByStatusFunc(ctx)
+}
+
+// CountByStatusCalls gets all the calls that were made to CountByStatus.
+// Check the length with:
+//
+//	len(mockedIAirportRepository.CountByStatusCalls())
+func (mock *IAirportRepositoryMock) CountByStatusCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockCountByStatus.RLock()
+	calls = mock.calls.CountByStatus
+	mock.lockCountByStatus.RUnlock()
+	return calls
 }
 
 // Delete calls DeleteFunc.
@@ -226,38 +437,6 @@ func (mock *IAirportRepositoryMock) GetAllCalls() []struct {
 	return calls
 }
 
-// GetAllPending calls GetAllPendingFunc.
-func (mock *IAirportRepositoryMock) GetAllPending(ctx context.Context) ([]dto.Airport, error) {
-	if mock.GetAllPendingFunc == nil {
-		panic("IAirportRepositoryMock.GetAllPendingFunc: method is nil but IAirportRepository.GetAllPending was just called")
-	}
-	callInfo := struct {
-		Ctx context.Context
-	}{
-		Ctx: ctx,
-	}
-	mock.lockGetAllPending.Lock()
-	mock.calls.GetAllPending = append(mock.calls.GetAllPending, callInfo)
-	mock.lockGetAllPending.Unlock()
-	return mock.GetAllPendingFunc(ctx)
-}
-
-// GetAllPendingCalls gets all the calls that were made to GetAllPending.
-// Check the length with:
-//
-//	len(mockedIAirportRepository.GetAllPendingCalls())
-func (mock *IAirportRepositoryMock) GetAllPendingCalls() []struct {
-	Ctx context.Context
-} {
-	var calls []struct {
-		Ctx context.Context
-	}
-	mock.lockGetAllPending.RLock()
-	calls = mock.calls.GetAllPending
-	mock.lockGetAllPending.RUnlock()
-	return calls
-}
-
 // GetByICAOOrFacilityName calls GetByICAOOrFacilityNameFunc.
 func (mock *IAirportRepositoryMock) GetByICAOOrFacilityName(ctx context.Context, icao string, facilityName string, limit int, offset int) ([]dto.Airport, error) {
 	if mock.GetByICAOOrFacilityNameFunc == nil {
@@ -306,6 +485,42 @@ func (mock *IAirportRepositoryMock) GetByICAOOrFacilityNameCalls() []struct {
 	return calls
 }
 
+// GetByICAOs calls GetByICAOsFunc.
+func (mock *IAirportRepositoryMock) GetByICAOs(ctx context.Context, icaos []string) ([]dto.Airport, error) {
+	if mock.GetByICAOsFunc == nil {
+		panic("IAirportRepositoryMock.GetByICAOsFunc: method is nil but IAirportRepository.GetByICAOs was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Icaos []string
+	}{
+		Ctx:   ctx,
+		Icaos: icaos,
+	}
+	mock.lockGetByICAOs.Lock()
+	mock.calls.GetByICAOs = append(mock.calls.GetByICAOs, callInfo)
+	mock.lockGetByICAOs.Unlock()
+	return mock.GetByICAOsFunc(ctx, icaos)
+}
+
+// GetByICAOsCalls gets all the calls that were made to GetByICAOs.
+// Check the length with:
+//
+//	len(mockedIAirportRepository.GetByICAOsCalls())
+func (mock *IAirportRepositoryMock) GetByICAOsCalls() []struct {
+	Ctx   context.Context
+	Icaos []string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Icaos []string
+	}
+	mock.lockGetByICAOs.RLock()
+	calls = mock.calls.GetByICAOs
+	mock.lockGetByICAOs.RUnlock()
+	return calls
+}
+
 // GetById calls GetByIdFunc.
 func (mock *IAirportRepositoryMock) GetById(ctx context.Context, id int) (*dto.Airport, error) {
 	if mock.GetByIdFunc == nil {
@@ -342,6 +557,174 @@ func (mock *IAirportRepositoryMock) GetByIdCalls() []struct {
 	return calls
 }
 
+// GetByStatus calls GetByStatusFunc.
+func (mock *IAirportRepositoryMock) GetByStatus(ctx context.Context, status string) ([]dto.Airport, error) {
+	if mock.GetByStatusFunc == nil {
+		panic("IAirportRepositoryMock.GetByStatusFunc: method is nil but IAirportRepository.GetByStatus was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Status string
+	}{
+		Ctx:    ctx,
+		Status: status,
+	}
+	mock.lockGetByStatus.Lock()
+	mock.calls.GetByStatus = append(mock.calls.GetByStatus, callInfo)
+	mock.lockGetByStatus.Unlock()
+	return mock.GetByStatusFunc(ctx, status)
+}
+
+// GetByStatusCalls gets all the calls that were made to GetByStatus.
+// Check the length with:
+//
+//	len(mockedIAirportRepository.GetByStatusCalls())
+func (mock *IAirportRepositoryMock) GetByStatusCalls() []struct {
+	Ctx    context.Context
+	Status string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Status string
+	}
+	mock.lockGetByStatus.RLock()
+	calls = mock.calls.GetByStatus
+	mock.lockGetByStatus.RUnlock()
+	return calls
+}
+
+// GetHistory calls GetHistoryFunc.
+func (mock *IAirportRepositoryMock) GetHistory(ctx context.Context, airportID int, limit int, offset int) ([]dto.AirportHistory, error) {
+	if mock.GetHistoryFunc == nil {
+		panic("IAirportRepositoryMock.GetHistoryFunc: method is nil but IAirportRepository.GetHistory was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		AirportID int
+		Limit     int
+		Offset    int
+	}{
+		Ctx:       ctx,
+		AirportID: airportID,
+		Limit:     limit,
+		Offset:    offset,
+	}
+	mock.lockGetHistory.Lock()
+	mock.calls.GetHistory = append(mock.calls.GetHistory, callInfo)
+	mock.lockGetHistory.Unlock()
+	return mock.GetHistoryFunc(ctx, airportID, limit, offset)
+}
+
+// GetHistoryCalls gets all the calls that were made to GetHistory.
+// Check the length with:
+//
+//	len(mockedIAirportRepository.GetHistoryCalls())
+func (mock *IAirportRepositoryMock) GetHistoryCalls() []struct {
+	Ctx       context.Context
+	AirportID int
+	Limit     int
+	Offset    int
+} {
+	var calls []struct {
+		Ctx       context.Context
+		AirportID int
+		Limit     int
+		Offset    int
+	}
+	mock.lockGetHistory.RLock()
+	calls = mock.calls.GetHistory
+	mock.lockGetHistory.RUnlock()
+	return calls
+}
+
+// GetNearby calls GetNearbyFunc.
+func (mock *IAirportRepositoryMock) GetNearby(ctx context.Context, lat float64, lon float64, radiusNm float64, limit int, offset int) ([]dto.NearbyAirport, error) {
+	if mock.GetNearbyFunc == nil {
+		panic("IAirportRepositoryMock.GetNearbyFunc: method is nil but IAirportRepository.GetNearby was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Lat      float64
+		Lon      float64
+		RadiusNm float64
+		Limit    int
+		Offset   int
+	}{
+		Ctx:      ctx,
+		Lat:      lat,
+		Lon:      lon,
+		RadiusNm: radiusNm,
+		Limit:    limit,
+		Offset:   offset,
+	}
+	mock.lockGetNearby.Lock()
+	mock.calls.GetNearby = append(mock.calls.GetNearby, callInfo)
+	mock.lockGetNearby.Unlock()
+	return mock.GetNearbyFunc(ctx, lat, lon, radiusNm, limit, offset)
+}
+
+// GetNearbyCalls gets all the calls that were made to GetNearby.
+// Check the length with:
+//
+//	len(mockedIAirportRepository.GetNearbyCalls())
+func (mock *IAirportRepositoryMock) GetNearbyCalls() []struct {
+	Ctx      context.Context
+	Lat      float64
+	Lon      float64
+	RadiusNm float64
+	Limit    int
+	Offset   int
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Lat      float64
+		Lon      float64
+		RadiusNm float64
+		Limit    int
+		Offset   int
+	}
+	mock.lockGetNearby.RLock()
+	calls = mock.calls.GetNearby
+	mock.lockGetNearby.RUnlock()
+	return calls
+}
+
+// GetStale calls GetStaleFunc.
+func (mock *IAirportRepositoryMock) GetStale(ctx context.Context, syncedBefore time.Time) ([]dto.Airport, error) {
+	if mock.GetStaleFunc == nil {
+		panic("IAirportRepositoryMock.GetStaleFunc: method is nil but IAirportRepository.GetStale was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		SyncedBefore time.Time
+	}{
+		Ctx:          ctx,
+		SyncedBefore: syncedBefore,
+	}
+	mock.lockGetStale.Lock()
+	mock.calls.GetStale = append(mock.calls.GetStale, callInfo)
+	mock.lockGetStale.Unlock()
+	return mock.GetStaleFunc(ctx, syncedBefore)
+}
+
+// GetStaleCalls gets all the calls that were made to GetStale.
+// Check the length with:
+//
+//	len(mockedIAirportRepository.GetStaleCalls())
+func (mock *IAirportRepositoryMock) GetStaleCalls() []struct {
+	Ctx          context.Context
+	SyncedBefore time.Time
+} {
+	var calls []struct {
+		Ctx          context.Context
+		SyncedBefore time.Time
+	}
+	mock.lockGetStale.RLock()
+	calls = mock.calls.GetStale
+	mock.lockGetStale.RUnlock()
+	return calls
+}
+
 // Insert calls InsertFunc.
 func (mock *IAirportRepositoryMock) Insert(ctx context.Context, airport *dto.Airport) (*dto.Airport, error) {
 	if mock.InsertFunc == nil {
@@ -378,6 +761,50 @@ func (mock *IAirportRepositoryMock) InsertCalls() []struct {
 	return calls
 }
 
+// StreamByICAOOrFacilityName calls StreamByICAOOrFacilityNameFunc.
+func (mock *IAirportRepositoryMock) StreamByICAOOrFacilityName(ctx context.Context, icao string, facilityName string, fn func(airport *dto.Airport) error) error {
+	if mock.StreamByICAOOrFacilityNameFunc == nil {
+		panic("IAirportRepositoryMock.StreamByICAOOrFacilityNameFunc: method is nil but IAirportRepository.StreamByICAOOrFacilityName was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		Icao         string
+		FacilityName string
+		Fn           func(airport *dto.Airport) error
+	}{
+		Ctx:          ctx,
+		Icao:         icao,
+		FacilityName: facilityName,
+		Fn:           fn,
+	}
+	mock.lockStreamByICAOOrFacilityName.Lock()
+	mock.calls.StreamByICAOOrFacilityName = append(mock.calls.StreamByICAOOrFacilityName, callInfo)
+	mock.lockStreamByICAOOrFacilityName.Unlock()
+	return mock.StreamByICAOOrFacilityNameFunc(ctx, icao, facilityName, fn)
+}
+
+// StreamByICAOOrFacilityNameCalls gets all the calls that were made to StreamByICAOOrFacilityName.
+// Check the length with:
+//
+//	len(mockedIAirportRepository.StreamByICAOOrFacilityNameCalls())
+func (mock *IAirportRepositoryMock) StreamByICAOOrFacilityNameCalls() []struct {
+	Ctx          context.Context
+	Icao         string
+	FacilityName string
+	Fn           func(airport *dto.Airport) error
+} {
+	var calls []struct {
+		Ctx          context.Context
+		Icao         string
+		FacilityName string
+		Fn           func(airport *dto.Airport) error
+	}
+	mock.lockStreamByICAOOrFacilityName.RLock()
+	calls = mock.calls.StreamByICAOOrFacilityName
+	mock.lockStreamByICAOOrFacilityName.RUnlock()
+	return calls
+}
+
 // UpdateByICAO calls UpdateByICAOFunc.
 func (mock *IAirportRepositoryMock) UpdateByICAO(ctx context.Context, airports []dto.Airport) error {
 	if mock.UpdateByICAOFunc == nil {
@@ -449,3 +876,75 @@ func (mock *IAirportRepositoryMock) UpdateByIdCalls() []struct {
 	mock.lockUpdateById.RUnlock()
 	return calls
 }
+
+// UpdateSyncState calls UpdateSyncStateFunc.
+func (mock *IAirportRepositoryMock) UpdateSyncState(ctx context.Context, airports []dto.Airport) error {
+	if mock.UpdateSyncStateFunc == nil {
+		panic("IAirportRepositoryMock.UpdateSyncStateFunc: method is nil but IAirportRepository.UpdateSyncState was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Airports []dto.Airport
+	}{
+		Ctx:      ctx,
+		Airports: airports,
+	}
+	mock.lockUpdateSyncState.Lock()
+	mock.calls.UpdateSyncState = append(mock.calls.UpdateSyncState, callInfo)
+	mock.lockUpdateSyncState.Unlock()
+	return mock.UpdateSyncStateFunc(ctx, airports)
+}
+
+// UpdateSyncStateCalls gets all the calls that were made to UpdateSyncState.
+// Check the length with:
+//
+//	len(mockedIAirportRepository.UpdateSyncStateCalls())
+func (mock *IAirportRepositoryMock) UpdateSyncStateCalls() []struct {
+	Ctx      context.Context
+	Airports []dto.Airport
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Airports []dto.Airport
+	}
+	mock.lockUpdateSyncState.RLock()
+	calls = mock.calls.UpdateSyncState
+	mock.lockUpdateSyncState.RUnlock()
+	return calls
+}
+
+// UpsertByICAO calls UpsertByICAOFunc.
+func (mock *IAirportRepositoryMock) UpsertByICAO(ctx context.Context, airport *dto.Airport) (bool, error) {
+	if mock.UpsertByICAOFunc == nil {
+		panic("IAirportRepositoryMock.UpsertByICAOFunc: method is nil but IAirportRepository.UpsertByICAO was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Airport *dto.Airport
+	}{
+		Ctx:     ctx,
+		Airport: airport,
+	}
+	mock.lockUpsertByICAO.Lock()
+	mock.calls.UpsertByICAO = append(mock.calls.UpsertByICAO, callInfo)
+	mock.lockUpsertByICAO.Unlock()
+	return mock.UpsertByICAOFunc(ctx, airport)
+}
+
+// UpsertByICAOCalls gets all the calls that were made to UpsertByICAO.
+// Check the length with:
+//
+//	len(mockedIAirportRepository.UpsertByICAOCalls())
+func (mock *IAirportRepositoryMock) UpsertByICAOCalls() []struct {
+	Ctx     context.Context
+	Airport *dto.Airport
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Airport *dto.Airport
+	}
+	mock.lockUpsertByICAO.RLock()
+	calls = mock.calls.UpsertByICAO
+	mock.lockUpsertByICAO.RUnlock()
+	return calls
+}
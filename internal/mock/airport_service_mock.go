@@ -26,15 +26,24 @@ var _ service.IAirportService = &IAirportServiceMock{}
 //			DeleteAirportFunc: func(ctx context.Context, id int) error {
 //				panic("mock out the DeleteAirport method")
 //			},
-//			FetchAirportDataFunc: func(icaos string) (*dto.AirportDataResponse, error) {
+//			FetchAirportDataFunc: func(ctx context.Context, icaos string) (*dto.AirportDataResponse, error) {
 //				panic("mock out the FetchAirportData method")
 //			},
 //			GetAirportFunc: func(ctx context.Context, id int) (*dto.Airport, error) {
 //				panic("mock out the GetAirport method")
 //			},
+//			GetAirportHistoryFunc: func(ctx context.Context, id int, limit int, offset int) ([]dto.AirportHistory, error) {
+//				panic("mock out the GetAirportHistory method")
+//			},
 //			GetAllAirportFunc: func(ctx context.Context, limit int, offset int) ([]dto.Airport, error) {
 //				panic("mock out the GetAllAirport method")
 //			},
+//			GetNearbyAirportFunc: func(ctx context.Context, lat float64, lon float64, radiusNm float64, limit int, offset int) ([]dto.NearbyAirport, error) {
+//				panic("mock out the GetNearbyAirport method")
+//			},
+//			InvalidateCacheFunc: func(ctx context.Context) error {
+//				panic("mock out the InvalidateCache method")
+//			},
 //			SearchAirportFunc: func(ctx context.Context, icao string, name string, limit int, offset int) ([]dto.Airport, error) {
 //				panic("mock out the SearchAirport method")
 //			},
@@ -55,14 +64,23 @@ type IAirportServiceMock struct {
 	DeleteAirportFunc func(ctx context.Context, id int) error
 
 	// FetchAirportDataFunc mocks the FetchAirportData method.
-	FetchAirportDataFunc func(icaos string) (*dto.AirportDataResponse, error)
+	FetchAirportDataFunc func(ctx context.Context, icaos string) (*dto.AirportDataResponse, error)
 
 	// GetAirportFunc mocks the GetAirport method.
 	GetAirportFunc func(ctx context.Context, id int) (*dto.Airport, error)
 
+	// GetAirportHistoryFunc mocks the GetAirportHistory method.
+	GetAirportHistoryFunc func(ctx context.Context, id int, limit int, offset int) ([]dto.AirportHistory, error)
+
 	// GetAllAirportFunc mocks the GetAllAirport method.
 	GetAllAirportFunc func(ctx context.Context, limit int, offset int) ([]dto.Airport, error)
 
+	// GetNearbyAirportFunc mocks the GetNearbyAirport method.
+	GetNearbyAirportFunc func(ctx context.Context, lat float64, lon float64, radiusNm float64, limit int, offset int) ([]dto.NearbyAirport, error)
+
+	// InvalidateCacheFunc mocks the InvalidateCache method.
+	InvalidateCacheFunc func(ctx context.Context) error
+
 	// SearchAirportFunc mocks the SearchAirport method.
 	SearchAirportFunc func(ctx context.Context, icao string, name string, limit int, offset int) ([]dto.Airport, error)
 
@@ -87,6 +105,8 @@ type IAirportServiceMock struct {
 		}
 		// FetchAirportData holds details about calls to the FetchAirportData method.
 		FetchAirportData []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Icaos is the icaos argument value.
 			Icaos string
 		}
@@ -97,6 +117,17 @@ type IAirportServiceMock struct {
 			// ID is the id argument value.
 			ID int
 		}
+		// GetAirportHistory holds details about calls to the GetAirportHistory method.
+		GetAirportHistory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+			// Limit is the limit argument value.
+			Limit int
+			// Offset is the offset argument value.
+			Offset int
+		}
 		// GetAllAirport holds details about calls to the GetAllAirport method.
 		GetAllAirport []struct {
 			// Ctx is the ctx argument value.
@@ -106,6 +137,26 @@ type IAirportServiceMock struct {
 			// Offset is the offset argument value.
 			Offset int
 		}
+		// GetNearbyAirport holds details about calls to the GetNearbyAirport method.
+		GetNearbyAirport []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Lat is the lat argument value.
+			Lat float64
+			// Lon is the lon argument value.
+			Lon float64
+			// RadiusNm is the radiusNm argument value.
+			RadiusNm float64
+			// Limit is the limit argument value.
+			Limit int
+			// Offset is the offset argument value.
+			Offset int
+		}
+		// InvalidateCache holds details about calls to the InvalidateCache method.
+		InvalidateCache []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
 		// SearchAirport holds details about calls to the SearchAirport method.
 		SearchAirport []struct {
 			// Ctx is the ctx argument value.
@@ -127,13 +178,16 @@ type IAirportServiceMock struct {
 			Request *dto.Airport
 		}
 	}
-	lockCreateAirport    sync.RWMutex
-	lockDeleteAirport    sync.RWMutex
-	lockFetchAirportData sync.RWMutex
-	lockGetAirport       sync.RWMutex
-	lockGetAllAirport    sync.RWMutex
-	lockSearchAirport    sync.RWMutex
-	lockUpdateAirport    sync.RWMutex
+	lockCreateAirport     sync.RWMutex
+	lockDeleteAirport     sync.RWMutex
+	lockFetchAirportData  sync.RWMutex
+	lockGetAirport        sync.RWMutex
+	lockGetAirportHistory sync.RWMutex
+	lockGetAllAirport     sync.RWMutex
+	lockGetNearbyAirport  sync.RWMutex
+	lockInvalidateCache   sync.RWMutex
+	lockSearchAirport     sync.RWMutex
+	lockUpdateAirport     sync.RWMutex
 }
 
 // CreateAirport calls CreateAirportFunc.
@@ -209,19 +263,21 @@ func (mock *IAirportServiceMock) DeleteAirportCalls() []struct {
 }
 
 // FetchAirportData calls FetchAirportDataFunc.
-func (mock *IAirportServiceMock) FetchAirportData(icaos string) (*dto.AirportDataResponse, error) {
+func (mock *IAirportServiceMock) FetchAirportData(ctx context.Context, icaos string) (*dto.AirportDataResponse, error) {
 	if mock.FetchAirportDataFunc == nil {
 		panic("IAirportServiceMock.FetchAirportDataFunc: method is nil but IAirportService.FetchAirportData was just called")
 	}
 	callInfo := struct {
+		Ctx   context.Context
 		Icaos string
 	}{
+		Ctx:   ctx,
 		Icaos: icaos,
 	}
 	mock.lockFetchAirportData.Lock()
 	mock.calls.FetchAirportData = append(mock.calls.FetchAirportData, callInfo)
 	mock.lockFetchAirportData.Unlock()
-	return mock.FetchAirportDataFunc(icaos)
+	return mock.FetchAirportDataFunc(ctx, icaos)
 }
 
 // FetchAirportDataCalls gets all the calls that were made to FetchAirportData.
@@ -229,9 +285,11 @@ func (mock *IAirportServiceMock) FetchAirportData(icaos string) (*dto.AirportDat
 //
 //	len(mockedIAirportService.FetchAirportDataCalls())
 func (mock *IAirportServiceMock) FetchAirportDataCalls() []struct {
+	Ctx   context.Context
 	Icaos string
 } {
 	var calls []struct {
+		Ctx   context.Context
 		Icaos string
 	}
 	mock.lockFetchAirportData.RLock()
@@ -276,6 +334,50 @@ func (mock *IAirportServiceMock) GetAirportCalls() []struct {
 	return calls
 }
 
+// GetAirportHistory calls GetAirportHistoryFunc.
+func (mock *IAirportServiceMock) GetAirportHistory(ctx context.Context, id int, limit int, offset int) ([]dto.AirportHistory, error) {
+	if mock.GetAirportHistoryFunc == nil {
+		panic("IAirportServiceMock.GetAirportHistoryFunc: method is nil but IAirportService.GetAirportHistory was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		ID     int
+		Limit  int
+		Offset int
+	}{
+		Ctx:    ctx,
+		ID:     id,
+		Limit:  limit,
+		Offset: offset,
+	}
+	mock.lockGetAirportHistory.Lock()
+	mock.calls.GetAirportHistory = append(mock.calls.GetAirportHistory, callInfo)
+	mock.lockGetAirportHistory.Unlock()
+	return mock.GetAirportHistoryFunc(ctx, id, limit, offset)
+}
+
+// GetAirportHistoryCalls gets all the calls that were made to GetAirportHistory.
+// Check the length with:
+//
+//	len(mockedIAirportService.GetAirportHistoryCalls())
+func (mock *IAirportServiceMock) GetAirportHistoryCalls() []struct {
+	Ctx    context.Context
+	ID     int
+	Limit  int
+	Offset int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		ID     int
+		Limit  int
+		Offset int
+	}
+	mock.lockGetAirportHistory.RLock()
+	calls = mock.calls.GetAirportHistory
+	mock.lockGetAirportHistory.RUnlock()
+	return calls
+}
+
 // GetAllAirport calls GetAllAirportFunc.
 func (mock *IAirportServiceMock) GetAllAirport(ctx context.Context, limit int, offset int) ([]dto.Airport, error) {
 	if mock.GetAllAirportFunc == nil {
@@ -316,6 +418,90 @@ func (mock *IAirportServiceMock) GetAllAirportCalls() []struct {
 	return calls
 }
 
+// GetNearbyAirport calls GetNearbyAirportFunc.
+func (mock *IAirportServiceMock) GetNearbyAirport(ctx context.Context, lat float64, lon float64, radiusNm float64, limit int, offset int) ([]dto.NearbyAirport, error) {
+	if mock.GetNearbyAirportFunc == nil {
+		panic("IAirportServiceMock.GetNearbyAirportFunc: method is nil but IAirportService.GetNearbyAirport was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Lat      float64
+		Lon      float64
+		RadiusNm float64
+		Limit    int
+		Offset   int
+	}{
+		Ctx:      ctx,
+		Lat:      lat,
+		Lon:      lon,
+		RadiusNm: radiusNm,
+		Limit:    limit,
+		Offset:   offset,
+	}
+	mock.lockGetNearbyAirport.Lock()
+	mock.calls.GetNearbyAirport = append(mock.calls.GetNearbyAirport, callInfo)
+	mock.lockGetNearbyAirport.Unlock()
+	return mock.GetNearbyAirportFunc(ctx, lat, lon, radiusNm, limit, offset)
+}
+
+// GetNearbyAirportCalls gets all the calls that were made to GetNearbyAirport.
+// Check the length with:
+//
+//	len(mockedIAirportService.GetNearbyAirportCalls())
+func (mock *IAirportServiceMock) GetNearbyAirportCalls() []struct {
+	Ctx      context.Context
+	Lat      float64
+	Lon      float64
+	RadiusNm float64
+	Limit    int
+	Offset   int
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Lat      float64
+		Lon      float64
+		RadiusNm float64
+		Limit    int
+		Offset   int
+	}
+	mock.lockGetNearbyAirport.RLock()
+	calls = mock.calls.GetNearbyAirport
+	mock.lockGetNearbyAirport.RUnlock()
+	return calls
+}
+
+// InvalidateCache calls InvalidateCacheFunc.
+func (mock *IAirportServiceMock) InvalidateCache(ctx context.Context) error {
+	if mock.InvalidateCacheFunc == nil {
+		panic("IAirportServiceMock.InvalidateCacheFunc: method is nil but IAirportService.InvalidateCache was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockInvalidateCache.Lock()
+	mock.calls.InvalidateCache = append(mock.calls.InvalidateCache, callInfo)
+	mock.lockInvalidateCache.Unlock()
+	return mock.InvalidateCacheFunc(ctx)
+}
+
+// InvalidateCacheCalls gets all the calls that were made to InvalidateCache.
+// Check the length with:
+//
+//	len(mockedIAirportService.InvalidateCacheCalls())
+func (mock *IAirportServiceMock) InvalidateCacheCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockInvalidateCache.RLock()
+	calls = mock.calls.InvalidateCache
+	mock.lockInvalidateCache.RUnlock()
+	return calls
+}
+
 // SearchAirport calls SearchAirportFunc.
 func (mock *IAirportServiceMock) SearchAirport(ctx context.Context, icao string, name string, limit int, offset int) ([]dto.Airport, error) {
 	if mock.SearchAirportFunc == nil {
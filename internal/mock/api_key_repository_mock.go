@@ -0,0 +1,227 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mock
+
+import (
+	"aviation-service/internal/dto"
+	"aviation-service/internal/repository"
+	"context"
+	"sync"
+)
+
+// Ensure, that IAPIKeyRepositoryMock does implement repository.IAPIKeyRepository.
+// If this is not the case, regenerate this file with moq.
+var _ repository.IAPIKeyRepository = &IAPIKeyRepositoryMock{}
+
+// IAPIKeyRepositoryMock is a mock implementation of repository.IAPIKeyRepository.
+//
+//	func TestSomethingThatUsesIAPIKeyRepository(t *testing.T) {
+//
+//		// make and configure a mocked repository.IAPIKeyRepository
+//		mockedIAPIKeyRepository := &IAPIKeyRepositoryMock{
+//			CreateFunc: func(ctx context.Context, key *dto.APIKey) (*dto.APIKey, error) {
+//				panic("mock out the Create method")
+//			},
+//			GetActiveByHashFunc: func(ctx context.Context, keyHash string) (*dto.APIKey, error) {
+//				panic("mock out the GetActiveByHash method")
+//			},
+//			GetAllFunc: func(ctx context.Context) ([]dto.APIKey, error) {
+//				panic("mock out the GetAll method")
+//			},
+//			RevokeFunc: func(ctx context.Context, id int) error {
+//				panic("mock out the Revoke method")
+//			},
+//		}
+//
+//		// use mockedIAPIKeyRepository in code that requires repository.IAPIKeyRepository
+//		// and then make assertions.
+//
+//	}
+type IAPIKeyRepositoryMock struct {
+	// CreateFunc mocks the Create method.
+	CreateFunc func(ctx context.Context, key *dto.APIKey) (*dto.APIKey, error)
+
+	// GetActiveByHashFunc mocks the GetActiveByHash method.
+	GetActiveByHashFunc func(ctx context.Context, keyHash string) (*dto.APIKey, error)
+
+	// GetAllFunc mocks the GetAll method.
+	GetAllFunc func(ctx context.Context) ([]dto.APIKey, error)
+
+	// RevokeFunc mocks the Revoke method.
+	RevokeFunc func(ctx context.Context, id int) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key *dto.APIKey
+		}
+		// GetActiveByHash holds details about calls to the GetActiveByHash method.
+		GetActiveByHash []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// KeyHash is the keyHash argument value.
+			KeyHash string
+		}
+		// GetAll holds details about calls to the GetAll method.
+		GetAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Revoke holds details about calls to the Revoke method.
+		Revoke []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+		}
+	}
+	lockCreate          sync.RWMutex
+	lockGetActiveByHash sync.RWMutex
+	lockGetAll          sync.RWMutex
+	lockRevoke          sync.RWMutex
+}
+
+// Create calls CreateFunc.
+func (mock *IAPIKeyRepositoryMock) Create(ctx context.Context, key *dto.APIKey) (*dto.APIKey, error) {
+	if mock.CreateFunc == nil {
+		panic("IAPIKeyRepositoryMock.CreateFunc: method is nil but IAPIKeyRepository.Create was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Key *dto.APIKey
+	}{
+		Ctx: ctx,
+		Key: key,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(ctx, key)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedIAPIKeyRepository.CreateCalls())
+func (mock *IAPIKeyRepositoryMock) CreateCalls() []struct {
+	Ctx context.Context
+	Key *dto.APIKey
+} {
+	var calls []struct {
+		Ctx context.Context
+		Key *dto.APIKey
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// GetActiveByHash calls GetActiveByHashFunc.
+func (mock *IAPIKeyRepositoryMock) GetActiveByHash(ctx context.Context, keyHash string) (*dto.APIKey, error) {
+	if mock.GetActiveByHashFunc == nil {
+		panic("IAPIKeyRepositoryMock.GetActiveByHashFunc: method is nil but IAPIKeyRepository.GetActiveByHash was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		KeyHash string
+	}{
+		Ctx:     ctx,
+		KeyHash: keyHash,
+	}
+	mock.lockGetActiveByHash.Lock()
+	mock.calls.GetActiveByHash = append(mock.calls.GetActiveByHash, callInfo)
+	mock.lockGetActiveByHash.Unlock()
+	return mock.GetActiveByHashFunc(ctx, keyHash)
+}
+
+// GetActiveByHashCalls gets all the calls that were made to GetActiveByHash.
+// Check the length with:
+//
+//	len(mockedIAPIKeyRepository.GetActiveByHashCalls())
+func (mock *IAPIKeyRepositoryMock) GetActiveByHashCalls() []struct {
+	Ctx     context.Context
+	KeyHash string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		KeyHash string
+	}
+	mock.lockGetActiveByHash.RLock()
+	calls = mock.calls.GetActiveByHash
+	mock.lockGetActiveByHash.RUnlock()
+	return calls
+}
+
+// GetAll calls GetAllFunc.
+func (mock *IAPIKeyRepositoryMock) GetAll(ctx context.Context) ([]dto.APIKey, error) {
+	if mock.GetAllFunc == nil {
+		panic("IAPIKeyRepositoryMock.GetAllFunc: method is nil but IAPIKeyRepository.GetAll was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetAll.Lock()
+	mock.calls.GetAll = append(mock.calls.GetAll, callInfo)
+	mock.lockGetAll.Unlock()
+	return mock.GetAllFunc(ctx)
+}
+
+// GetAllCalls gets all the calls that were made to GetAll.
+// Check the length with:
+//
+//	len(mockedIAPIKeyRepository.GetAllCalls())
+func (mock *IAPIKeyRepositoryMock) GetAllCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetAll.RLock()
+	calls = mock.calls.GetAll
+	mock.lockGetAll.RUnlock()
+	return calls
+}
+
+// Revoke calls RevokeFunc.
+func (mock *IAPIKeyRepositoryMock) Revoke(ctx context.Context, id int) error {
+	if mock.RevokeFunc == nil {
+		panic("IAPIKeyRepositoryMock.RevokeFunc: method is nil but IAPIKeyRepository.Revoke was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockRevoke.Lock()
+	mock.calls.Revoke = append(mock.calls.Revoke, callInfo)
+	mock.lockRevoke.Unlock()
+	return mock.RevokeFunc(ctx, id)
+}
+
+// RevokeCalls gets all the calls that were made to Revoke.
+// Check the length with:
+//
+//	len(mockedIAPIKeyRepository.RevokeCalls())
+func (mock *IAPIKeyRepositoryMock) RevokeCalls() []struct {
+	Ctx context.Context
+	ID  int
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int
+	}
+	mock.lockRevoke.RLock()
+	calls = mock.calls.Revoke
+	mock.lockRevoke.RUnlock()
+	return calls
+}
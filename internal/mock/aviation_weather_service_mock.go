@@ -0,0 +1,133 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mock
+
+import (
+	"aviation-service/internal/dto"
+	"aviation-service/internal/service"
+	"context"
+	"sync"
+)
+
+// Ensure, that IAviationWeatherServiceMock does implement service.IAviationWeatherService.
+// If this is not the case, regenerate this file with moq.
+var _ service.IAviationWeatherService = &IAviationWeatherServiceMock{}
+
+// IAviationWeatherServiceMock is a mock implementation of service.IAviationWeatherService.
+//
+//	func TestSomethingThatUsesIAviationWeatherService(t *testing.T) {
+//
+//		// make and configure a mocked service.IAviationWeatherService
+//		mockedIAviationWeatherService := &IAviationWeatherServiceMock{
+//			GetMetarFunc: func(ctx context.Context, icao string) (*dto.Metar, error) {
+//				panic("mock out the GetMetar method")
+//			},
+//			GetTafFunc: func(ctx context.Context, icao string) (*dto.Taf, error) {
+//				panic("mock out the GetTaf method")
+//			},
+//		}
+//
+//		// use mockedIAviationWeatherService in code that requires service.IAviationWeatherService
+//		// and then make assertions.
+//
+//	}
+type IAviationWeatherServiceMock struct {
+	// GetMetarFunc mocks the GetMetar method.
+	GetMetarFunc func(ctx context.Context, icao string) (*dto.Metar, error)
+
+	// GetTafFunc mocks the GetTaf method.
+	GetTafFunc func(ctx context.Context, icao string) (*dto.Taf, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetMetar holds details about calls to the GetMetar method.
+		GetMetar []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Icao is the icao argument value.
+			Icao string
+		}
+		// GetTaf holds details about calls to the GetTaf method.
+		GetTaf []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Icao is the icao argument value.
+			Icao string
+		}
+	}
+	lockGetMetar sync.RWMutex
+	lockGetTaf   sync.RWMutex
+}
+
+// GetMetar calls GetMetarFunc.
+func (mock *IAviationWeatherServiceMock) GetMetar(ctx context.Context, icao string) (*dto.Metar, error) {
+	if mock.GetMetarFunc == nil {
+		panic("IAviationWeatherServiceMock.GetMetarFunc: method is nil but IAviationWeatherService.GetMetar was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Icao string
+	}{
+		Ctx:  ctx,
+		Icao: icao,
+	}
+	mock.lockGetMetar.Lock()
+	mock.calls.GetMetar = append(mock.calls.GetMetar, callInfo)
+	mock.lockGetMetar.Unlock()
+	return mock.GetMetarFunc(ctx, icao)
+}
+
+// GetMetarCalls gets all the calls that were made to GetMetar.
+// Check the length with:
+//
+//	len(mockedIAviationWeatherService.GetMetarCalls())
+func (mock *IAviationWeatherServiceMock) GetMetarCalls() []struct {
+	Ctx  context.Context
+	Icao string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Icao string
+	}
+	mock.lockGetMetar.RLock()
+	calls = mock.calls.GetMetar
+	mock.lockGetMetar.RUnlock()
+	return calls
+}
+
+// GetTaf calls GetTafFunc.
+func (mock *IAviationWeatherServiceMock) GetTaf(ctx context.Context, icao string) (*dto.Taf, error) {
+	if mock.GetTafFunc == nil {
+		panic("IAviationWeatherServiceMock.GetTafFunc: method is nil but IAviationWeatherService.GetTaf was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Icao string
+	}{
+		Ctx:  ctx,
+		Icao: icao,
+	}
+	mock.lockGetTaf.Lock()
+	mock.calls.GetTaf = append(mock.calls.GetTaf, callInfo)
+	mock.lockGetTaf.Unlock()
+	return mock.GetTafFunc(ctx, icao)
+}
+
+// GetTafCalls gets all the calls that were made to GetTaf.
+// Check the length with:
+//
+//	len(mockedIAviationWeatherService.GetTafCalls())
+func (mock *IAviationWeatherServiceMock) GetTafCalls() []struct {
+	Ctx  context.Context
+	Icao string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Icao string
+	}
+	mock.lockGetTaf.RLock()
+	calls = mock.calls.GetTaf
+	mock.lockGetTaf.RUnlock()
+	return calls
+}
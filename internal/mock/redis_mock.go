@@ -3,6 +3,9 @@ package mock
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -10,9 +13,13 @@ import (
 
 type MockRedis struct {
 	Store map[string]string
+	// mu guards Store, the lock renewal reads it from its own goroutine
+	mu sync.Mutex
 }
 
 func (m *MockRedis) Get(ctx context.Context, key string) *redis.StringCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	val, ok := m.Store[key]
 	result := redis.NewStringResult(val, nil)
 	if !ok {
@@ -22,10 +29,49 @@ func (m *MockRedis) Get(ctx context.Context, key string) *redis.StringCmd {
 }
 
 func (m *MockRedis) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.Store[key] = value.(string)
 	return redis.NewStatusResult("OK", nil)
 }
 
+func (m *MockRedis) Incr(ctx context.Context, key string) *redis.IntCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	val, _ := strconv.ParseInt(m.Store[key], 10, 64)
+	val++
+	m.Store[key] = strconv.FormatInt(val, 10)
+	return redis.NewIntResult(val, nil)
+}
+
+func (m *MockRedis) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.Store[key]; ok {
+		return redis.NewBoolResult(false, nil)
+	}
+	m.Store[key] = value.(string)
+	return redis.NewBoolResult(true, nil)
+}
+
+// Eval runs the compare-and-set scripts of the Redis lock: the key must still hold ARGV[1],
+// scripts calling DEL delete it and the others only report success
+func (m *MockRedis) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Store[keys[0]] != args[0] {
+		return redis.NewCmdResult(int64(0), nil)
+	}
+	if strings.Contains(script, "DEL") {
+		delete(m.Store, keys[0])
+	}
+	return redis.NewCmdResult(int64(1), nil)
+}
+
+func (m *MockRedis) Ping(ctx context.Context) *redis.StatusCmd {
+	return redis.NewStatusResult("PONG", nil)
+}
+
 func (m *MockRedis) Close() error {
 	return nil
 }
@@ -40,4 +86,20 @@ func (m *MockRedisSetError) Set(ctx context.Context, key string, value interface
 	return redis.NewStatusResult("", fmt.Errorf("Cache set failed"))
 }
 
+func (m *MockRedisSetError) Incr(ctx context.Context, key string) *redis.IntCmd {
+	return redis.NewIntResult(0, fmt.Errorf("Cache incr failed"))
+}
+
+func (m *MockRedisSetError) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd {
+	return redis.NewBoolResult(false, fmt.Errorf("Cache set failed"))
+}
+
+func (m *MockRedisSetError) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd {
+	return redis.NewCmdResult(nil, fmt.Errorf("Cache eval failed"))
+}
+
+func (m *MockRedisSetError) Ping(ctx context.Context) *redis.StatusCmd {
+	return redis.NewStatusResult("", fmt.Errorf("Cache ping failed"))
+}
+
 func (m *MockRedisSetError) Close() error { return nil }
@@ -0,0 +1,378 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mock
+
+import (
+	"aviation-service/internal/dto"
+	"aviation-service/internal/repository"
+	"context"
+	"sync"
+	"time"
+)
+
+// Ensure, that ISyncRunRepositoryMock does implement repository.ISyncRunRepository.
+// If this is not the case, regenerate this file with moq.
+var _ repository.ISyncRunRepository = &ISyncRunRepositoryMock{}
+
+// ISyncRunRepositoryMock is a mock implementation of repository.ISyncRunRepository.
+//
+//	func TestSomethingThatUsesISyncRunRepository(t *testing.T) {
+//
+//		// make and configure a mocked repository.ISyncRunRepository
+//		mockedISyncRunRepository := &ISyncRunRepositoryMock{
+//			CreateFunc: func(ctx context.Context, run *dto.SyncRun) (*dto.SyncRun, error) {
+//				panic("mock out the Create method")
+//			},
+//			FinishFunc: func(ctx context.Context, run *dto.SyncRun) error {
+//				panic("mock out the Finish method")
+//			},
+//			GetAllFunc: func(ctx context.Context, limit int, offset int) ([]dto.SyncRun, error) {
+//				panic("mock out the GetAll method")
+//			},
+//			GetByIdFunc: func(ctx context.Context, id int) (*dto.SyncRun, error) {
+//				panic("mock out the GetById method")
+//			},
+//			GetLastSuccessFunc: func(ctx context.Context) (map[string]time.Time, error) {
+//				panic("mock out the GetLastSuccess method")
+//			},
+//			GetRunningFunc: func(ctx context.Context) (*dto.SyncRun, error) {
+//				panic("mock out the GetRunning method")
+//			},
+//			InsertItemsFunc: func(ctx context.Context, items []dto.SyncRunItem) error {
+//				panic("mock out the InsertItems method")
+//			},
+//		}
+//
+//		// use mockedISyncRunRepository in code that requires repository.ISyncRunRepository
+//		// and then make assertions.
+//
+//	}
+type ISyncRunRepositoryMock struct {
+	// CreateFunc mocks the Create method.
+	CreateFunc func(ctx context.Context, run *dto.SyncRun) (*dto.SyncRun, error)
+
+	// FinishFunc mocks the Finish method.
+	FinishFunc func(ctx context.Context, run *dto.SyncRun) error
+
+	// GetAllFunc mocks the GetAll method.
+	GetAllFunc func(ctx context.Context, limit int, offset int) ([]dto.SyncRun, error)
+
+	// GetByIdFunc mocks the GetById method.
+	GetByIdFunc func(ctx context.Context, id int) (*dto.SyncRun, error)
+
+	// GetLastSuccessFunc mocks the GetLastSuccess method.
+	GetLastSuccessFunc func(ctx context.Context) (map[string]time.Time, error)
+
+	// GetRunningFunc mocks the GetRunning method.
+	GetRunningFunc func(ctx context.Context) (*dto.SyncRun, error)
+
+	// InsertItemsFunc mocks the InsertItems method.
+	InsertItemsFunc func(ctx context.Context, items []dto.SyncRunItem) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Run is the run argument value.
+			Run *dto.SyncRun
+		}
+		// Finish holds details about calls to the Finish method.
+		Finish []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Run is the run argument value.
+			Run *dto.SyncRun
+		}
+		// GetAll holds details about calls to the GetAll method.
+		GetAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Limit is the limit argument value.
+			Limit int
+			// Offset is the offset argument value.
+			Offset int
+		}
+		// GetById holds details about calls to the GetById method.
+		GetById []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+		}
+		// GetLastSuccess holds details about calls to the GetLastSuccess method.
+		GetLastSuccess []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// GetRunning holds details about calls to the GetRunning method.
+		GetRunning []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// InsertItems holds details about calls to the InsertItems method.
+		InsertItems []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Items is the items argument value.
+			Items []dto.SyncRunItem
+		}
+	}
+	lockCreate         sync.RWMutex
+	lockFinish         sync.RWMutex
+	lockGetAll         sync.RWMutex
+	lockGetById        sync.RWMutex
+	lockGetLastSuccess sync.RWMutex
+	lockGetRunning     sync.RWMutex
+	lockInsertItems    sync.RWMutex
+}
+
+// Create calls CreateFunc.
+func (mock *ISyncRunRepositoryMock) Create(ctx context.Context, run *dto.SyncRun) (*dto.SyncRun, error) {
+	if mock.CreateFunc == nil {
+		panic("ISyncRunRepositoryMock.CreateFunc: method is nil but ISyncRunRepository.Create was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Run *dto.SyncRun
+	}{
+		Ctx: ctx,
+		Run: run,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(ctx, run)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedISyncRunRepository.CreateCalls())
+func (mock *ISyncRunRepositoryMock) CreateCalls() []struct {
+	Ctx context.Context
+	Run *dto.SyncRun
+} {
+	var calls []struct {
+		Ctx context.Context
+		Run *dto.SyncRun
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// Finish calls FinishFunc.
+func (mock *ISyncRunRepositoryMock) Finish(ctx context.Context, run *dto.SyncRun) error {
+	if mock.FinishFunc == nil {
+		panic("ISyncRunRepositoryMock.FinishFunc: method is nil but ISyncRunRepository.Finish was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Run *dto.SyncRun
+	}{
+		Ctx: ctx,
+		Run: run,
+	}
+	mock.lockFinish.Lock()
+	mock.calls.Finish = append(mock.calls.Finish, callInfo)
+	mock.lockFinish.Unlock()
+	return mock.FinishFunc(ctx, run)
+}
+
+// FinishCalls gets all the calls that were made to Finish.
+// Check the length with:
+//
+//	len(mockedISyncRunRepository.FinishCalls())
+func (mock *ISyncRunRepositoryMock) FinishCalls() []struct {
+	Ctx context.Context
+	Run *dto.SyncRun
+} {
+	var calls []struct {
+		Ctx context.Context
+		Run *dto.SyncRun
+	}
+	mock.lockFinish.RLock()
+	calls = mock.calls.Finish
+	mock.lockFinish.RUnlock()
+	return calls
+}
+
+// GetAll calls GetAllFunc.
+func (mock *ISyncRunRepositoryMock) GetAll(ctx context.Context, limit int, offset int) ([]dto.SyncRun, error) {
+	if mock.GetAllFunc == nil {
+		panic("ISyncRunRepositoryMock.GetAllFunc: method is nil but ISyncRunRepository.GetAll was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Limit  int
+		Offset int
+	}{
+		Ctx:    ctx,
+		Limit:  limit,
+		Offset: offset,
+	}
+	mock.lockGetAll.Lock()
+	mock.calls.GetAll = append(mock.calls.GetAll, callInfo)
+	mock.lockGetAll.Unlock()
+	return mock.GetAllFunc(ctx, limit, offset)
+}
+
+// GetAllCalls gets all the calls that were made to GetAll.
+// Check the length with:
+//
+//	len(mockedISyncRunRepository.GetAllCalls())
+func (mock *ISyncRunRepositoryMock) GetAllCalls() []struct {
+	Ctx    context.Context
+	Limit  int
+	Offset int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Limit  int
+		Offset int
+	}
+	mock.lockGetAll.RLock()
+	calls = mock.calls.GetAll
+	mock.lockGetAll.RUnlock()
+	return calls
+}
+
+// GetById calls GetByIdFunc.
+func (mock *ISyncRunRepositoryMock) GetById(ctx context.Context, id int) (*dto.SyncRun, error) {
+	if mock.GetByIdFunc == nil {
+		panic("ISyncRunRepositoryMock.GetByIdFunc: method is nil but ISyncRunRepository.GetById was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetById.Lock()
+	mock.calls.GetById = append(mock.calls.GetById, callInfo)
+	mock.lockGetById.Unlock()
+	return mock.GetByIdFunc(ctx, id)
+}
+
+// GetByIdCalls gets all the calls that were made to GetById.
+// Check the length with:
+//
+//	len(mockedISyncRunRepository.GetByIdCalls())
+func (mock *ISyncRunRepositoryMock) GetByIdCalls() []struct {
+	Ctx context.Context
+	ID  int
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int
+	}
+	mock.lockGetById.RLock()
+	calls = mock.calls.GetById
+	mock.lockGetById.RUnlock()
+	return calls
+}
+
+// GetLastSuccess calls GetLastSuccessFunc.
+func (mock *ISyncRunRepositoryMock) GetLastSuccess(ctx context.Context) (map[string]time.Time, error) {
+	if mock.GetLastSuccessFunc == nil {
+		panic("ISyncRunRepositoryMock.GetLastSuccessFunc: method is nil but ISyncRunRepository.GetLastSuccess was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetLastSuccess.Lock()
+	mock.calls.GetLastSuccess = append(mock.calls.GetLastSuccess, callInfo)
+	mock.lockGetLastSuccess.Unlock()
+	return mock.GetLastSuccessFunc(ctx)
+}
+
+// GetLastSuccessCalls gets all the calls that were made to GetLastSuccess.
+// Check the length with:
+//
+//	len(mockedISyncRunRepository.GetLastSuccessCalls())
+func (mock *ISyncRunRepositoryMock) GetLastSuccessCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetLastSuccess.RLock()
+	calls = mock.calls.GetLastSuccess
+	mock.lockGetLastSuccess.RUnlock()
+	return calls
+}
+
+// GetRunning calls GetRunningFunc.
+func (mock *ISyncRunRepositoryMock) GetRunning(ctx context.Context) (*dto.SyncRun, error) {
+	if mock.GetRunningFunc == nil {
+		panic("ISyncRunRepositoryMock.GetRunningFunc: method is nil but ISyncRunRepository.GetRunning was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetRunning.Lock()
+	mock.calls.GetRunning = append(mock.calls.GetRunning, callInfo)
+	mock.lockGetRunning.Unlock()
+	return mock.GetRunningFunc(ctx)
+}
+
+// GetRunningCalls gets all the calls that were made to GetRunning.
+// Check the length with:
+//
+//	len(mockedISyncRunRepository.GetRunningCalls())
+func (mock *ISyncRunRepositoryMock) GetRunningCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetRunning.RLock()
+	calls = mock.calls.GetRunning
+	mock.lockGetRunning.RUnlock()
+	return calls
+}
+
+// InsertItems calls InsertItemsFunc.
+func (mock *ISyncRunRepositoryMock) InsertItems(ctx context.Context, items []dto.SyncRunItem) error {
+	if mock.InsertItemsFunc == nil {
+		panic("ISyncRunRepositoryMock.InsertItemsFunc: method is nil but ISyncRunRepository.InsertItems was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Items []dto.SyncRunItem
+	}{
+		Ctx:   ctx,
+		Items: items,
+	}
+	mock.lockInsertItems.Lock()
+	mock.calls.InsertItems = append(mock.calls.InsertItems, callInfo)
+	mock.lockInsertItems.Unlock()
+	return mock.InsertItemsFunc(ctx, items)
+}
+
+// InsertItemsCalls gets all the calls that were made to InsertItems.
+// Check the length with:
+//
+//	len(mockedISyncRunRepository.InsertItemsCalls())
+func (mock *ISyncRunRepositoryMock) InsertItemsCalls() []struct {
+	Ctx   context.Context
+	Items []dto.SyncRunItem
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Items []dto.SyncRunItem
+	}
+	mock.lockInsertItems.RLock()
+	calls = mock.calls.InsertItems
+	mock.lockInsertItems.RUnlock()
+	return calls
+}
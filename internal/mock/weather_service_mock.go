@@ -23,6 +23,9 @@ var _ service.IWeatherService = &IWeatherServiceMock{}
 //			GetWeatherFunc: func(ctx context.Context, city string) (*dto.Weather, error) {
 //				panic("mock out the GetWeather method")
 //			},
+//			GetWeatherByCoordinatesFunc: func(ctx context.Context, lat float64, lon float64) (*dto.Weather, error) {
+//				panic("mock out the GetWeatherByCoordinates method")
+//			},
 //		}
 //
 //		// use mockedIWeatherService in code that requires service.IWeatherService
@@ -33,6 +36,9 @@ type IWeatherServiceMock struct {
 	// GetWeatherFunc mocks the GetWeather method.
 	GetWeatherFunc func(ctx context.Context, city string) (*dto.Weather, error)
 
+	// GetWeatherByCoordinatesFunc mocks the GetWeatherByCoordinates method.
+	GetWeatherByCoordinatesFunc func(ctx context.Context, lat float64, lon float64) (*dto.Weather, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// GetWeather holds details about calls to the GetWeather method.
@@ -42,8 +48,18 @@ type IWeatherServiceMock struct {
 			// City is the city argument value.
 			City string
 		}
+		// GetWeatherByCoordinates holds details about calls to the GetWeatherByCoordinates method.
+		GetWeatherByCoordinates []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Lat is the lat argument value.
+			Lat float64
+			// Lon is the lon argument value.
+			Lon float64
+		}
 	}
-	lockGetWeather sync.RWMutex
+	lockGetWeather              sync.RWMutex
+	lockGetWeatherByCoordinates sync.RWMutex
 }
 
 // GetWeather calls GetWeatherFunc.
@@ -81,3 +97,43 @@ func (mock *IWeatherServiceMock) GetWeatherCalls() []struct {
 	mock.lockGetWeather.RUnlock()
 	return calls
 }
+
+// GetWeatherByCoordinates calls GetWeatherByCoordinatesFunc.
+func (mock *IWeatherServiceMock) GetWeatherByCoordinates(ctx context.Context, lat float64, lon float64) (*dto.Weather, error) {
+	if mock.GetWeatherByCoordinatesFunc == nil {
+		panic("IWeatherServiceMock.GetWeatherByCoordinatesFunc: method is nil but IWeatherService.GetWeatherByCoordinates was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Lat float64
+		Lon float64
+	}{
+		Ctx: ctx,
+		Lat: lat,
+		Lon: lon,
+	}
+	mock.lockGetWeatherByCoordinates.Lock()
+	mock.calls.GetWeatherByCoordinates = append(mock.calls.GetWeatherByCoordinates, callInfo)
+	mock.lockGetWeatherByCoordinates.Unlock()
+	return mock.GetWeatherByCoordinatesFunc(ctx, lat, lon)
+}
+
+// GetWeatherByCoordinatesCalls gets all the calls that were made to GetWeatherByCoordinates.
+// Check the length with:
+//
+//	len(mockedIWeatherService.GetWeatherByCoordinatesCalls())
+func (mock *IWeatherServiceMock) GetWeatherByCoordinatesCalls() []struct {
+	Ctx context.Context
+	Lat float64
+	Lon float64
+} {
+	var calls []struct {
+		Ctx context.Context
+		Lat float64
+		Lon float64
+	}
+	mock.lockGetWeatherByCoordinates.RLock()
+	calls = mock.calls.GetWeatherByCoordinates
+	mock.lockGetWeatherByCoordinates.RUnlock()
+	return calls
+}
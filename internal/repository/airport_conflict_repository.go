@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"aviation-service/internal/dto"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:generate moq -out ../mock/airport_conflict_repository_mock.go -pkg=mock . IAirportConflictRepository
+type IAirportConflictRepository interface {
+	Upsert(ctx context.Context, conflicts []dto.AirportConflict) error
+	GetAll(ctx context.Context, icao string, includeResolved bool, limit, offset int) ([]dto.AirportConflict, error)
+	GetById(ctx context.Context, id int) (*dto.AirportConflict, error)
+	Resolve(ctx context.Context, id int, resolution string) error
+}
+
+const airportConflictColumns = `id, airport_id, icao, field, manual_value, upstream_value, sync_run_id, resolution, created_at, resolved_at`
+
+type AirportConflictRepository struct {
+	db *sqlx.DB
+}
+
+func NewAirportConflictRepository(db *sqlx.DB) *AirportConflictRepository {
+	return &AirportConflictRepository{db: db}
+}
+
+// Upsert records the conflicts, an open conflict on the same airport field is refreshed with the latest values
+func (r *AirportConflictRepository) Upsert(ctx context.Context, conflicts []dto.AirportConflict) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	values := []interface{}{}
+	placeholders := []string{}
+	for i, c := range conflicts {
+		base := i*6 + 1
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)",
+			base, base+1, base+2, base+3, base+4, base+5))
+		values = append(values, c.AirportID, c.ICAO, c.Field, c.ManualValue, c.UpstreamValue, c.SyncRunID)
+	}
+
+	query := `INSERT INTO airport_conflict (airport_id, icao, field, manual_value, upstream_value, sync_run_id)
+			  VALUES ` + strings.Join(placeholders, ",") + `
+			  ON CONFLICT (airport_id, field) WHERE resolved_at IS NULL DO UPDATE SET
+				manual_value = EXCLUDED.manual_value,
+				upstream_value = EXCLUDED.upstream_value,
+				sync_run_id = EXCLUDED.sync_run_id`
+	_, err := r.db.ExecContext(ctx, query, values...)
+	return err
+}
+
+func (r *AirportConflictRepository) GetAll(ctx context.Context, icao string, includeResolved bool, limit, offset int) ([]dto.AirportConflict, error) {
+	var conflicts []dto.AirportConflict
+	query := `SELECT ` + airportConflictColumns + `
+			  FROM airport_conflict`
+	var conditions []string
+	args := []interface{}{}
+
+	if icao != "" {
+		args = append(args, icao)
+		conditions = append(conditions, fmt.Sprintf("icao = $%d", len(args)))
+	}
+	if !includeResolved {
+		conditions = append(conditions, "resolved_at IS NULL")
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, limit, offset)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	err := r.db.SelectContext(ctx, &conflicts, query, args...)
+	return conflicts, err
+}
+
+func (r *AirportConflictRepository) GetById(ctx context.Context, id int) (*dto.AirportConflict, error) {
+	var conflict dto.AirportConflict
+	query := `SELECT ` + airportConflictColumns + `
+			  FROM airport_conflict
+			  WHERE id = $1`
+	err := r.db.GetContext(ctx, &conflict, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &conflict, nil
+}
+
+func (r *AirportConflictRepository) Resolve(ctx context.Context, id int, resolution string) error {
+	query := `UPDATE airport_conflict SET
+			resolution = $1,
+			resolved_at = NOW()
+			WHERE id = $2 AND resolved_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, resolution, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("No open conflict found with id %d", id)
+	}
+	return err
+}
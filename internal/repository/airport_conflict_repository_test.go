@@ -0,0 +1,253 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var airportConflictColumns = []string{
+	"id", "airport_id", "icao", "field", "manual_value", "upstream_value", "sync_run_id", "resolution", "created_at", "resolved_at",
+}
+
+func TestAirportConflictRepository_Upsert(t *testing.T) {
+	upstream := "999999"
+	runID := 7
+	tests := []struct {
+		name        string
+		conflicts   []dto.AirportConflict
+		mockError   error
+		expectedErr error
+	}{
+		{
+			name: "Success upsert conflicts",
+			conflicts: []dto.AirportConflict{
+				{AirportID: 1, ICAO: "KLAX", Field: "manager_phone", ManualValue: &managerPhone, UpstreamValue: &upstream, SyncRunID: &runID},
+			},
+		},
+		{
+			name: "Success nothing to record",
+		},
+		{
+			name: "Error DB",
+			conflicts: []dto.AirportConflict{
+				{AirportID: 1, ICAO: "KLAX", Field: "manager_phone", ManualValue: &managerPhone, UpstreamValue: &upstream, SyncRunID: &runID},
+			},
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAirportConflictRepository(db)
+			query := `INSERT INTO airport_conflict (.+) VALUES (.+) ON CONFLICT \(airport_id, field\) WHERE resolved_at IS NULL DO UPDATE`
+
+			if len(tt.conflicts) > 0 {
+				expected := mock.ExpectExec(query).WithArgs(1, "KLAX", "manager_phone", managerPhone, upstream, runID)
+				if tt.mockError != nil {
+					expected.WillReturnError(tt.mockError)
+				} else {
+					expected.WillReturnResult(sqlmock.NewResult(1, 1))
+				}
+			}
+
+			err := repo.Upsert(context.Background(), tt.conflicts)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAirportConflictRepository_GetAll(t *testing.T) {
+	createdAt := time.Date(2025, time.October, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name            string
+		icao            string
+		includeResolved bool
+		mockError       error
+		expectedQuery   string
+		expectedLen     int
+		expectedErr     error
+	}{
+		{
+			name:          "Success get open conflicts by icao",
+			icao:          "KLAX",
+			expectedQuery: `SELECT (.+) FROM airport_conflict WHERE icao = \$1 AND resolved_at IS NULL ORDER BY id DESC`,
+			expectedLen:   1,
+		},
+		{
+			name:            "Success get all conflicts",
+			includeResolved: true,
+			expectedQuery:   `SELECT (.+) FROM airport_conflict ORDER BY id DESC`,
+			expectedLen:     1,
+		},
+		{
+			name:          "Error DB",
+			mockError:     sql.ErrConnDone,
+			expectedQuery: `SELECT (.+) FROM airport_conflict WHERE resolved_at IS NULL`,
+			expectedErr:   sql.ErrConnDone,
+		},
+	}
+
+	limit, offset := 10, 0
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAirportConflictRepository(db)
+
+			args := []driver.Value{}
+			if tt.icao != "" {
+				args = append(args, tt.icao)
+			}
+			args = append(args, limit, offset)
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(tt.expectedQuery).WithArgs(args...).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(tt.expectedQuery).WithArgs(args...).WillReturnRows(sqlmock.NewRows(airportConflictColumns).
+					AddRow(1, 1, "KLAX", "manager_phone", "123456", "999999", 7, nil, createdAt, nil))
+			}
+
+			got, err := repo.GetAll(context.Background(), tt.icao, tt.includeResolved, limit, offset)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if len(got) != tt.expectedLen {
+				t.Errorf("Expected len %v, got %v", tt.expectedLen, len(got))
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAirportConflictRepository_GetById(t *testing.T) {
+	createdAt := time.Date(2025, time.October, 16, 12, 0, 0, 0, time.UTC)
+	upstream := "999999"
+	runID := 7
+	tests := []struct {
+		name           string
+		mockRows       *sqlmock.Rows
+		mockError      error
+		expectedResult *dto.AirportConflict
+		expectedErr    error
+	}{
+		{
+			name: "Success get conflict",
+			mockRows: sqlmock.NewRows(airportConflictColumns).
+				AddRow(1, 1, "KLAX", "manager_phone", "123456", "999999", 7, nil, createdAt, nil),
+			expectedResult: &dto.AirportConflict{ID: 1, AirportID: 1, ICAO: "KLAX", Field: "manager_phone",
+				ManualValue: &managerPhone, UpstreamValue: &upstream, SyncRunID: &runID, CreatedAt: createdAt},
+		},
+		{
+			name:      "Not found",
+			mockError: sql.ErrNoRows,
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAirportConflictRepository(db)
+			query := `SELECT (.+) FROM airport_conflict WHERE id = (.+)`
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(tt.mockRows)
+			}
+
+			got, err := repo.GetById(context.Background(), 1)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedResult) {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAirportConflictRepository_Resolve(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockResult  driver.Result
+		mockError   error
+		expectedErr error
+	}{
+		{
+			name:       "Success resolve conflict",
+			mockResult: sqlmock.NewResult(0, 1),
+		},
+		{
+			name:        "No open conflict",
+			mockResult:  sqlmock.NewResult(0, 0),
+			expectedErr: fmt.Errorf("No open conflict found with id 1"),
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAirportConflictRepository(db)
+			query := `UPDATE airport_conflict SET (.+) WHERE id = (.+) AND resolved_at IS NULL`
+
+			if tt.mockError != nil {
+				mock.ExpectExec(query).WithArgs(dto.ConflictResolutionKeepManual, 1).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectExec(query).WithArgs(dto.ConflictResolutionKeepManual, 1).WillReturnResult(tt.mockResult)
+			}
+
+			err := repo.Resolve(context.Background(), 1, dto.ConflictResolutionKeepManual)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
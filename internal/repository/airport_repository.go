@@ -5,24 +5,42 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"aviation-service/internal/dto"
+	"aviation-service/internal/utils"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 //go:generate moq -out ../mock/airport_repository_mock.go -pkg=mock . IAirportRepository
 type IAirportRepository interface {
 	GetAll(ctx context.Context, limit, offset int) ([]dto.Airport, error)
-	GetAllPending(ctx context.Context) ([]dto.Airport, error)
+	ClaimDue(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error)
+	GetStale(ctx context.Context, syncedBefore time.Time) ([]dto.Airport, error)
+	GetByStatus(ctx context.Context, status string) ([]dto.Airport, error)
+	CountByStatus(ctx context.Context) (map[string]int, error)
 	GetById(ctx context.Context, id int) (*dto.Airport, error)
+	GetByICAOs(ctx context.Context, icaos []string) ([]dto.Airport, error)
 	GetByICAOOrFacilityName(ctx context.Context, icao, facilityName string, limit, offset int) ([]dto.Airport, error)
+	StreamByICAOOrFacilityName(ctx context.Context, icao, facilityName string, fn func(airport *dto.Airport) error) error
+	GetNearby(ctx context.Context, lat, lon, radiusNm float64, limit, offset int) ([]dto.NearbyAirport, error)
 	Insert(ctx context.Context, airport *dto.Airport) (*dto.Airport, error)
 	UpdateById(ctx context.Context, airport *dto.Airport) (*dto.Airport, error)
 	UpdateByICAO(ctx context.Context, airports []dto.Airport) error
+	UpdateSyncState(ctx context.Context, airports []dto.Airport) error
+	UpsertByICAO(ctx context.Context, airport *dto.Airport) (bool, error)
 	Delete(ctx context.Context, id int) error
+	GetHistory(ctx context.Context, airportID, limit, offset int) ([]dto.AirportHistory, error)
 }
 
+const airportColumns = `id, type, facility_name, faa, icao, region, state, county, city, ownership, use, 
+			  manager, manager_phone, latitude, longitude, latitude_deg, longitude_deg, status, field_sources, last_synced_at, 
+			  attempt_count, last_error, next_attempt_at`
+
+const airportHistoryColumns = `id, airport_id, icao, action, actor, changes, created_at`
+
 type AirportRepository struct {
 	db *sqlx.DB
 }
@@ -33,44 +51,144 @@ func NewAirportRepository(db *sqlx.DB) *AirportRepository {
 
 func (r *AirportRepository) GetAll(ctx context.Context, limit, offset int) ([]dto.Airport, error) {
 	var airports []dto.Airport
-	query := `SELECT id, type, facility_name, faa, icao, region, state, county, city, ownership, use, 
-			  manager, manager_phone, latitude, longitude, status 
+	query := `SELECT ` + airportColumns + `
 			  FROM airport
 			  LIMIT $1 OFFSET $2`
 	err := r.db.SelectContext(ctx, &airports, query, limit, offset)
 	return airports, err
 }
 
-func (r *AirportRepository) GetAllPending(ctx context.Context) ([]dto.Airport, error) {
+// ClaimDue moves the PENDING airports and the RETRYING ones whose backoff has elapsed to SYNCING and returns them.
+// Claimed airports are due again at leaseUntil, so airports held by a run that died are picked up by a later one.
+// Rows locked by a concurrent claim are skipped, and the status changes of the claim are not recorded in the history
+func (r *AirportRepository) ClaimDue(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
+	var airports []dto.Airport
+	query := `UPDATE airport SET status = $1, next_attempt_at = $2
+			  WHERE id IN (
+				SELECT id FROM airport
+				WHERE status = $3 OR (status IN ($4, $1) AND next_attempt_at <= NOW())
+				FOR UPDATE SKIP LOCKED
+			  )
+			  RETURNING ` + airportColumns
+
+	err := r.db.SelectContext(ctx, &airports, query, dto.AirportStatusSyncing, leaseUntil, dto.AirportStatusPending, dto.AirportStatusRetrying)
+	return airports, err
+}
+
+// GetStale returns the DONE airports last synced before syncedBefore, airports never synced come first
+func (r *AirportRepository) GetStale(ctx context.Context, syncedBefore time.Time) ([]dto.Airport, error) {
+	var airports []dto.Airport
+	query := `SELECT ` + airportColumns + `
+			  FROM airport
+			  WHERE status = $1 AND (last_synced_at IS NULL OR last_synced_at < $2)
+			  ORDER BY last_synced_at NULLS FIRST, id`
+
+	err := r.db.SelectContext(ctx, &airports, query, dto.AirportStatusDone, syncedBefore)
+	return airports, err
+}
+
+func (r *AirportRepository) GetByStatus(ctx context.Context, status string) ([]dto.Airport, error) {
+	var airports []dto.Airport
+	query := `SELECT ` + airportColumns + `
+			  FROM airport
+			  WHERE status = $1
+			  ORDER BY id`
+
+	err := r.db.SelectContext(ctx, &airports, query, status)
+	return airports, err
+}
+
+// CountByStatus returns the number of airports of every sync status present in the table
+func (r *AirportRepository) CountByStatus(ctx context.Context) (map[string]int, error) {
+	var rows []struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+	query := `SELECT status, COUNT(*) AS count
+			  FROM airport
+			  GROUP BY status`
+
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *AirportRepository) GetByICAOs(ctx context.Context, icaos []string) ([]dto.Airport, error) {
 	var airports []dto.Airport
-	query := `SELECT id, type, facility_name, faa, icao, region, state, county, city, ownership, use, 
-			  manager, manager_phone, latitude, longitude, status 
+	query := `SELECT ` + airportColumns + `
 			  FROM airport
-			  WHERE status = $1`
+			  WHERE icao = ANY($1)
+			  ORDER BY id`
 
-	err := r.db.SelectContext(ctx, &airports, query, "PENDING")
+	err := r.db.SelectContext(ctx, &airports, query, pq.Array(icaos))
 	return airports, err
 }
 
 func (r *AirportRepository) Insert(ctx context.Context, airport *dto.Airport) (*dto.Airport, error) {
 	query := `INSERT INTO airport (
 				type, facility_name, faa, icao, region, state, county, city, ownership, use, 
-				manager, manager_phone, latitude, longitude, status 
-			  ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
-				RETURNING id, type, facility_name, faa, icao, region, state, county, city, ownership, use, 
-			  	manager, manager_phone, latitude, longitude, status`
+				manager, manager_phone, latitude, longitude, latitude_deg, longitude_deg, status, field_sources 
+			  ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+				RETURNING ` + airportColumns
 	var created dto.Airport
-	err := r.db.GetContext(ctx, &created, query, airport.Type, airport.FacilityName, airport.FAA,
-		airport.ICAO, airport.Region, airport.State, airport.County, airport.City, airport.Ownership,
-		airport.Use, airport.Manager, airport.ManagerPhone, airport.Latitude, airport.Longitude, airport.Status)
-	return &created, err
+	latDeg, lonDeg := parseCoordinates(airport)
+	sources := fieldSources(ctx, nil, airport)
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := tx.GetContext(ctx, &created, query, airport.Type, airport.FacilityName, airport.FAA,
+			airport.ICAO, airport.Region, airport.State, airport.County, airport.City, airport.Ownership,
+			airport.Use, airport.Manager, airport.ManagerPhone, airport.Latitude, airport.Longitude, latDeg, lonDeg, airport.Status, sources); err != nil {
+			return err
+		}
+		return insertHistory(ctx, tx, historyEntry(ctx, nil, &created))
+	})
+	if err != nil {
+		return &dto.Airport{}, err
+	}
+	return &created, nil
 }
 
 func (r *AirportRepository) GetByICAOOrFacilityName(ctx context.Context, icao string, facilityName string, limit, offset int) ([]dto.Airport, error) {
 	var airports []dto.Airport
-	query := `SELECT id, type, facility_name, faa, icao, region, state, county, city, ownership, use, 
-			  manager, manager_phone, latitude, longitude, status 
-			  FROM airport`
+	where, args := searchConditions(icao, facilityName)
+	query := `SELECT ` + airportColumns + `
+			  FROM airport` + where
+	args = append(args, limit, offset)
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	err := r.db.SelectContext(ctx, &airports, query, args...)
+	return airports, err
+}
+
+// StreamByICAOOrFacilityName calls fn for every matching airport while iterating the result cursor, so the rows are never held in memory together.
+// An error returned by fn stops the iteration and is returned as is
+func (r *AirportRepository) StreamByICAOOrFacilityName(ctx context.Context, icao, facilityName string, fn func(airport *dto.Airport) error) error {
+	where, args := searchConditions(icao, facilityName)
+	query := `SELECT ` + airportColumns + `
+			  FROM airport` + where + `
+			  ORDER BY id`
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var airport dto.Airport
+		if err := rows.StructScan(&airport); err != nil {
+			return err
+		}
+		if err := fn(&airport); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func searchConditions(icao, facilityName string) (string, []interface{}) {
 	var conditions []string
 	args := []interface{}{}
 
@@ -83,19 +201,36 @@ func (r *AirportRepository) GetByICAOOrFacilityName(ctx context.Context, icao st
 		conditions = append(conditions, fmt.Sprintf("facility_name ILIKE $%d", len(args)))
 	}
 
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+	if len(conditions) == 0 {
+		return "", args
 	}
-	args = append(args, limit, offset)
-	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
-	err := r.db.SelectContext(ctx, &airports, query, args...)
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *AirportRepository) GetNearby(ctx context.Context, lat, lon, radiusNm float64, limit, offset int) ([]dto.NearbyAirport, error) {
+	var airports []dto.NearbyAirport
+	// One nautical mile is one minute of latitude, so the latitude band narrows the scan before the haversine filter
+	query := `SELECT * FROM (
+				SELECT ` + airportColumns + `,
+				$4 * 2 * ASIN(LEAST(1, SQRT(
+					POWER(SIN(RADIANS(latitude_deg - $1) / 2), 2) +
+					COS(RADIANS($1)) * COS(RADIANS(latitude_deg)) * POWER(SIN(RADIANS(longitude_deg - $2) / 2), 2)
+				))) AS distance_nm
+				FROM airport
+				WHERE latitude_deg BETWEEN $1 - $3 / 60.0 AND $1 + $3 / 60.0
+				AND longitude_deg IS NOT NULL
+			  ) AS nearby
+			  WHERE distance_nm <= $3
+			  ORDER BY distance_nm
+			  LIMIT $5 OFFSET $6`
+
+	err := r.db.SelectContext(ctx, &airports, query, lat, lon, radiusNm, utils.EarthRadiusNm, limit, offset)
 	return airports, err
 }
 
 func (r *AirportRepository) GetById(ctx context.Context, id int) (*dto.Airport, error) {
 	var airport dto.Airport
-	query := `SELECT id, type, facility_name, faa, icao, region, state, county, city, ownership, use, 
-			  manager, manager_phone, latitude, longitude, status 
+	query := `SELECT ` + airportColumns + `
 			  FROM airport 
 			  WHERE id = $1`
 	err := r.db.GetContext(ctx, &airport, query, id)
@@ -122,84 +257,335 @@ func (r *AirportRepository) UpdateById(ctx context.Context, airport *dto.Airport
 			manager_phone = $12,
 			latitude = $13,
 			longitude = $14,
-			status = $15
-			WHERE id = $16
-			RETURNING id, type, facility_name, faa, icao, region, state, county, city, ownership, use, 
-			manager, manager_phone, latitude, longitude, status`
-	var updated dto.Airport
-	err := r.db.GetContext(ctx, &updated, query, airport.Type, airport.FacilityName, airport.FAA,
-		airport.ICAO, airport.Region, airport.State, airport.County, airport.City, airport.Ownership,
-		airport.Use, airport.Manager, airport.ManagerPhone, airport.Latitude, airport.Longitude, airport.Status, airport.ID)
-	return &updated, err
+			latitude_deg = $15,
+			longitude_deg = $16,
+			status = $17,
+			field_sources = $18,
+			attempt_count = 0,
+			last_error = NULL,
+			next_attempt_at = NULL
+			WHERE id = $19
+			RETURNING ` + airportColumns
+	var before, updated dto.Airport
+	latDeg, lonDeg := parseCoordinates(airport)
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := tx.GetContext(ctx, &before, `SELECT `+airportColumns+` FROM airport WHERE id = $1 FOR UPDATE`, airport.ID); err != nil {
+			return err
+		}
+		if err := tx.GetContext(ctx, &updated, query, airport.Type, airport.FacilityName, airport.FAA,
+			airport.ICAO, airport.Region, airport.State, airport.County, airport.City, airport.Ownership,
+			airport.Use, airport.Manager, airport.ManagerPhone, airport.Latitude, airport.Longitude, latDeg, lonDeg, airport.Status,
+			fieldSources(ctx, &before, airport), airport.ID); err != nil {
+			return err
+		}
+		return insertHistory(ctx, tx, historyEntry(ctx, &before, &updated))
+	})
+	if err != nil {
+		return &dto.Airport{}, err
+	}
+	return &updated, nil
 }
 
+// UpdateByICAO writes the synced airports and stamps them with last_synced_at, it is only used by the sync.
+// Like every other successful write it resets the retry state
 func (r *AirportRepository) UpdateByICAO(ctx context.Context, airports []dto.Airport) error {
+	icaos := make([]string, 0, len(airports))
+	for _, apt := range airports {
+		icaos = append(icaos, apt.ICAO)
+	}
+
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var before []dto.Airport
+		if err := tx.SelectContext(ctx, &before, `SELECT `+airportColumns+` FROM airport WHERE icao = ANY($1) FOR UPDATE`, pq.Array(icaos)); err != nil {
+			return err
+		}
+		byICAO := make(map[string]*dto.Airport, len(before))
+		byID := make(map[int]*dto.Airport, len(before))
+		for i := range before {
+			byICAO[before[i].ICAO] = &before[i]
+			byID[before[i].ID] = &before[i]
+		}
+
+		values := []interface{}{}
+		placeholders := []string{}
+		for i, apt := range airports {
+			base := i*18 + 1
+			placeholders = append(placeholders, fmt.Sprintf(
+				"($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d::double precision, $%d::double precision, $%d, $%d::jsonb)",
+				base, base+1, base+2, base+3, base+4, base+5, base+6, base+7, base+8,
+				base+9, base+10, base+11, base+12, base+13, base+14, base+15, base+16, base+17,
+			))
+
+			latDeg, lonDeg := parseCoordinates(&apt)
+
+			values = append(values,
+				apt.ICAO,
+				apt.Type,
+				apt.FacilityName,
+				apt.FAA,
+				apt.Region,
+				apt.State,
+				apt.County,
+				apt.City,
+				apt.Ownership,
+				apt.Use,
+				apt.Manager,
+				apt.ManagerPhone,
+				apt.Latitude,
+				apt.Longitude,
+				latDeg,
+				lonDeg,
+				apt.Status,
+				fieldSources(ctx, byICAO[apt.ICAO], &apt),
+			)
+		}
+
+		query := `
+			UPDATE airport AS a SET
+				type = v.type,
+				facility_name = v.facility_name,
+				faa = v.faa,
+				region = v.region,
+				state = v.state,
+				county = v.county,
+				city = v.city,
+				ownership = v.ownership,
+				use = v.use,
+				manager = v.manager,
+				manager_phone = v.manager_phone,
+				latitude = v.latitude,
+				longitude = v.longitude,
+				latitude_deg = v.latitude_deg,
+				longitude_deg = v.longitude_deg,
+				status = v.status,
+				field_sources = v.field_sources,
+				last_synced_at = NOW(),
+				attempt_count = 0,
+				last_error = NULL,
+				next_attempt_at = NULL
+			FROM (VALUES
+		` + strings.Join(placeholders, ",") + `
+			) AS v(icao, type, facility_name, faa, region, state, county, city, ownership, use,
+					manager, manager_phone, latitude, longitude, latitude_deg, longitude_deg, status, field_sources)
+			WHERE a.icao = v.icao
+			RETURNING ` + prefixedColumns("a")
+
+		var updated []dto.Airport
+		if err := tx.SelectContext(ctx, &updated, query, values...); err != nil {
+			return err
+		}
+
+		var entries []dto.AirportHistory
+		for i := range updated {
+			entries = append(entries, historyEntry(ctx, byID[updated[i].ID], &updated[i])...)
+		}
+		return insertHistory(ctx, tx, entries)
+	})
+}
+
+// UpdateSyncState writes the status, attempt count, last error and next attempt of airports the sync could not complete.
+// This is sync bookkeeping, the airport data is left alone and nothing is recorded in the history
+func (r *AirportRepository) UpdateSyncState(ctx context.Context, airports []dto.Airport) error {
+	if len(airports) == 0 {
+		return nil
+	}
+
 	values := []interface{}{}
-    placeholders := []string{}
-
-    for i, apt := range airports {
-        base := i*15 + 1
-        placeholders = append(placeholders, fmt.Sprintf(
-            "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
-            base, base+1, base+2, base+3, base+4, base+5, base+6,
-            base+7, base+8, base+9, base+10, base+11, base+12, base+13, base+14,
-        ))
-
-        values = append(values,
-            apt.ICAO, 
-            apt.Type,
-            apt.FacilityName,
-            apt.FAA,
-            apt.Region,
-            apt.State,
-            apt.County,
-            apt.City,
-            apt.Ownership,
-            apt.Use,
-            apt.Manager,
-            apt.ManagerPhone,
-            apt.Latitude,
-            apt.Longitude,
-            apt.Status,
-        )
-    }
-
-    query := `
-        UPDATE airport AS a SET
-            type = v.type,
-            facility_name = v.facility_name,
-            faa = v.faa,
-            region = v.region,
-            state = v.state,
-            county = v.county,
-            city = v.city,
-            ownership = v.ownership,
-            use = v.use,
-            manager = v.manager,
-            manager_phone = v.manager_phone,
-            latitude = v.latitude,
-            longitude = v.longitude,
-            status = v.status
-        FROM (VALUES
-    ` + strings.Join(placeholders, ",") + `
-        ) AS v(icao, type, facility_name, faa, region, state, county, city, ownership, use,
-                manager, manager_phone, latitude, longitude, status)
-        WHERE a.icao = v.icao
-    `
-
-    _, err := r.db.ExecContext(ctx, query, values...)
-    return err
+	placeholders := []string{}
+	for i, apt := range airports {
+		base := i*5 + 1
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d::int, $%d, $%d::timestamptz)",
+			base, base+1, base+2, base+3, base+4))
+		values = append(values, apt.ICAO, apt.Status, apt.AttemptCount, apt.LastError, apt.NextAttemptAt)
+	}
+
+	query := `
+		UPDATE airport AS a SET
+			status = v.status,
+			attempt_count = v.attempt_count,
+			last_error = v.last_error,
+			next_attempt_at = v.next_attempt_at
+		FROM (VALUES
+	` + strings.Join(placeholders, ",") + `
+		) AS v(icao, status, attempt_count, last_error, next_attempt_at)
+		WHERE a.icao = v.icao`
+	_, err := r.db.ExecContext(ctx, query, values...)
+	return err
+}
+
+// UpsertByICAO inserts the airport or updates the row with the same ICAO, it reports whether a new row was created.
+// Fields the airport does not carry keep their stored value and the status follows from the merged row
+func (r *AirportRepository) UpsertByICAO(ctx context.Context, airport *dto.Airport) (bool, error) {
+	query := `INSERT INTO airport (
+				type, facility_name, faa, icao, region, state, county, city, ownership, use,
+				manager, manager_phone, latitude, longitude, latitude_deg, longitude_deg, status, field_sources
+			  ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+			  ON CONFLICT (icao) DO UPDATE SET
+				type = COALESCE(EXCLUDED.type, airport.type),
+				facility_name = COALESCE(EXCLUDED.facility_name, airport.facility_name),
+				faa = COALESCE(EXCLUDED.faa, airport.faa),
+				region = COALESCE(EXCLUDED.region, airport.region),
+				state = COALESCE(EXCLUDED.state, airport.state),
+				county = COALESCE(EXCLUDED.county, airport.county),
+				city = COALESCE(EXCLUDED.city, airport.city),
+				ownership = COALESCE(EXCLUDED.ownership, airport.ownership),
+				use = COALESCE(EXCLUDED.use, airport.use),
+				manager = COALESCE(EXCLUDED.manager, airport.manager),
+				manager_phone = COALESCE(EXCLUDED.manager_phone, airport.manager_phone),
+				latitude = COALESCE(EXCLUDED.latitude, airport.latitude),
+				longitude = COALESCE(EXCLUDED.longitude, airport.longitude),
+				latitude_deg = COALESCE(EXCLUDED.latitude_deg, airport.latitude_deg),
+				longitude_deg = COALESCE(EXCLUDED.longitude_deg, airport.longitude_deg),
+				status = EXCLUDED.status,
+				field_sources = EXCLUDED.field_sources,
+				attempt_count = 0,
+				last_error = NULL,
+				next_attempt_at = NULL
+			  RETURNING ` + airportColumns + `, (xmax = 0) AS created`
+	var upserted struct {
+		dto.Airport
+		Created bool `db:"created"`
+	}
+	latDeg, lonDeg := parseCoordinates(airport)
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var before *dto.Airport
+		var existing dto.Airport
+		err := tx.GetContext(ctx, &existing, `SELECT `+airportColumns+` FROM airport WHERE icao = $1 FOR UPDATE`, airport.ICAO)
+		if err == nil {
+			before = &existing
+		} else if err != sql.ErrNoRows {
+			return err
+		}
+
+		merged := *airport
+		if before != nil {
+			merged = utils.FillAirport(before, airport)
+			merged.Status = dto.AirportStatusPending
+			if utils.NewAirportValidator().IsComplete(&merged) {
+				merged.Status = dto.AirportStatusDone
+			}
+		}
+
+		if err := tx.GetContext(ctx, &upserted, query, airport.Type, airport.FacilityName, airport.FAA,
+			airport.ICAO, airport.Region, airport.State, airport.County, airport.City, airport.Ownership,
+			airport.Use, airport.Manager, airport.ManagerPhone, airport.Latitude, airport.Longitude, latDeg, lonDeg, merged.Status,
+			fieldSources(ctx, before, &merged)); err != nil {
+			return err
+		}
+		return insertHistory(ctx, tx, historyEntry(ctx, before, &upserted.Airport))
+	})
+	if err != nil {
+		return false, err
+	}
+	return upserted.Created, nil
 }
 
 func (r *AirportRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM airport WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	query := `DELETE FROM airport WHERE id = $1 RETURNING ` + airportColumns
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var deleted dto.Airport
+		err := tx.GetContext(ctx, &deleted, query, id)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("No airport found with id %d", id)
+		}
+		if err != nil {
+			return err
+		}
+		return insertHistory(ctx, tx, historyEntry(ctx, &deleted, nil))
+	})
+}
+
+func (r *AirportRepository) GetHistory(ctx context.Context, airportID, limit, offset int) ([]dto.AirportHistory, error) {
+	var history []dto.AirportHistory
+	query := `SELECT ` + airportHistoryColumns + `
+			  FROM airport_history
+			  WHERE airport_id = $1
+			  ORDER BY created_at DESC, id DESC
+			  LIMIT $2 OFFSET $3`
+	err := r.db.SelectContext(ctx, &history, query, airportID, limit, offset)
+	return history, err
+}
+
+func (r *AirportRepository) withTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	rowsAffected, err := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("No airport found with id %d", id)
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// historyEntry describes one write, a nil before is an insert and a nil after a delete. Updates that change nothing are not recorded
+func historyEntry(ctx context.Context, before, after *dto.Airport) []dto.AirportHistory {
+	changes := utils.AirportChanges(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	entry := dto.AirportHistory{Action: dto.AirportHistoryActionUpdate, Actor: utils.ActorFromContext(ctx), Changes: changes}
+	switch {
+	case before == nil:
+		entry.Action = dto.AirportHistoryActionInsert
+		entry.AirportID, entry.ICAO = after.ID, after.ICAO
+	case after == nil:
+		entry.Action = dto.AirportHistoryActionDelete
+		entry.AirportID, entry.ICAO = before.ID, before.ICAO
+	default:
+		entry.AirportID, entry.ICAO = after.ID, after.ICAO
+	}
+	return []dto.AirportHistory{entry}
+}
+
+// fieldSources returns the provenance after writing airport over before, the changed fields take the source of ctx
+func fieldSources(ctx context.Context, before, airport *dto.Airport) dto.FieldSources {
+	var previous dto.FieldSources
+	if before != nil {
+		previous = before.FieldSources
+	}
+	return utils.MergeFieldSources(previous, utils.AirportChanges(before, airport), utils.FieldSourceFromContext(ctx))
+}
+
+func insertHistory(ctx context.Context, tx *sqlx.Tx, entries []dto.AirportHistory) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	values := []interface{}{}
+	placeholders := []string{}
+	for i, entry := range entries {
+		base := i*5 + 1
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)", base, base+1, base+2, base+3, base+4))
+		values = append(values, entry.AirportID, entry.ICAO, entry.Action, entry.Actor, entry.Changes)
 	}
+
+	query := `INSERT INTO airport_history (airport_id, icao, action, actor, changes)
+			  VALUES ` + strings.Join(placeholders, ",")
+	_, err := tx.ExecContext(ctx, query, values...)
 	return err
 }
+
+func prefixedColumns(alias string) string {
+	columns := strings.Split(airportColumns, ",")
+	for i, column := range columns {
+		columns[i] = alias + "." + strings.TrimSpace(column)
+	}
+	return strings.Join(columns, ", ")
+}
+
+func parseCoordinates(airport *dto.Airport) (*float64, *float64) {
+	var latDeg, lonDeg *float64
+	if airport.Latitude != nil {
+		if lat, err := utils.ParseLatitude(*airport.Latitude); err == nil {
+			latDeg = &lat
+		}
+	}
+	if airport.Longitude != nil {
+		if lon, err := utils.ParseLongitude(*airport.Longitude); err == nil {
+			lonDeg = &lon
+		}
+	}
+	return latDeg, lonDeg
+}
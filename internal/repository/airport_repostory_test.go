@@ -7,12 +7,15 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"aviation-service/internal/dto"
 	. "aviation-service/internal/repository"
+	"aviation-service/internal/utils"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 var (
@@ -96,7 +99,8 @@ func TestAirportRepository_GetAll(t *testing.T) {
 	}
 }
 
-func TestAirportRepository_GetAllByPending(t *testing.T) {
+func TestAirportRepository_ClaimDue(t *testing.T) {
+	leaseUntil := time.Date(2025, time.October, 16, 18, 30, 0, 0, time.UTC)
 	tests := []struct {
 		name        string
 		mockRows    *sqlmock.Rows
@@ -105,16 +109,16 @@ func TestAirportRepository_GetAllByPending(t *testing.T) {
 		expectedErr error
 	}{
 		{
-			name: "Success get all pending airports",
+			name: "Success claim due airports",
 			mockRows: sqlmock.NewRows([]string{
 				"id", "type", "facility_name", "faa", "icao", "region", "state",
-				"county", "city", "ownership", "use", "manager", "manager_phone", "latitude", "longitude", "status",
+				"county", "city", "ownership", "use", "manager", "manager_phone", "latitude", "longitude", "status", "attempt_count",
 			}).AddRow(1, "Airport", "Lorem ipsum", "LAX", "KLAX", "LA", "CALIFORNIA",
-				"LOS ANGELES", "LOS ANGELES", "PU", "PU", "Manager1", "123456", 12.34, 56.78, "PENDING"),
+				"LOS ANGELES", "LOS ANGELES", "PU", "PU", "Manager1", "123456", 12.34, 56.78, "SYNCING", 2),
 			expectedLen: 1,
 		},
 		{
-			name: "No pending airports",
+			name: "No due airports",
 			mockRows: sqlmock.NewRows([]string{
 				"id", "type", "facility_name", "faa", "icao", "region", "state",
 				"county", "city", "ownership", "use", "manager", "manager_phone", "latitude", "longitude", "status",
@@ -136,7 +140,228 @@ func TestAirportRepository_GetAllByPending(t *testing.T) {
 			defer db.Close()
 
 			repo := NewAirportRepository(db)
-			query := `SELECT (.+) FROM airport WHERE status = (.+)`
+			query := `UPDATE airport SET status = (.+), next_attempt_at = (.+) WHERE id IN \( SELECT id FROM airport WHERE (.+) FOR UPDATE SKIP LOCKED \) RETURNING (.+)`
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(query).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(query).
+					WithArgs(dto.AirportStatusSyncing, leaseUntil, dto.AirportStatusPending, dto.AirportStatusRetrying).
+					WillReturnRows(tt.mockRows)
+			}
+
+			got, err := repo.ClaimDue(context.Background(), leaseUntil)
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if len(got) != tt.expectedLen {
+				t.Errorf("Expected length %v, got %v", tt.expectedLen, len(got))
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAirportRepository_UpdateSyncState(t *testing.T) {
+	message := "No airport data returned by AviationAPI"
+	nextAttemptAt := time.Date(2025, time.October, 16, 19, 0, 0, 0, time.UTC)
+	airports := []dto.Airport{
+		{ICAO: "KAVL", Status: dto.AirportStatusRetrying, AttemptCount: 1, LastError: &message, NextAttemptAt: &nextAttemptAt},
+		{ICAO: "KLAX", Status: dto.AirportStatusFailed, AttemptCount: 5, LastError: &message},
+	}
+	tests := []struct {
+		name        string
+		airports    []dto.Airport
+		mockError   error
+		expectExec  bool
+		expectedErr error
+	}{
+		{
+			name:       "Success update sync state",
+			airports:   airports,
+			expectExec: true,
+		},
+		{
+			name: "Success nothing to update",
+		},
+		{
+			name:        "Error DB",
+			airports:    airports,
+			mockError:   sql.ErrConnDone,
+			expectExec:  true,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAirportRepository(db)
+			query := `UPDATE airport AS a SET status = v.status, (.+) FROM \(VALUES (.+)\) AS v(.+) WHERE a.icao = v.icao`
+
+			if tt.expectExec {
+				expect := mock.ExpectExec(query).WithArgs(
+					"KAVL", dto.AirportStatusRetrying, 1, message, nextAttemptAt,
+					"KLAX", dto.AirportStatusFailed, 5, message, nil)
+				if tt.mockError != nil {
+					expect.WillReturnError(tt.mockError)
+				} else {
+					expect.WillReturnResult(sqlmock.NewResult(0, 2))
+				}
+			}
+
+			err := repo.UpdateSyncState(context.Background(), tt.airports)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAirportRepository_GetStale(t *testing.T) {
+	syncedBefore := time.Date(2025, time.September, 16, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		mockRows    *sqlmock.Rows
+		mockError   error
+		expectedLen int
+		expectedErr error
+	}{
+		{
+			name: "Success get stale airports",
+			mockRows: sqlmock.NewRows([]string{"id", "icao", "status", "last_synced_at"}).
+				AddRow(2, "KLAX", "DONE", nil).
+				AddRow(1, "KAVL", "DONE", syncedBefore.Add(-time.Hour)),
+			expectedLen: 2,
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAirportRepository(db)
+			query := `SELECT (.+) FROM airport WHERE status = (.+) AND \(last_synced_at IS NULL OR last_synced_at < (.+)\) ORDER BY last_synced_at NULLS FIRST`
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(query).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(query).WithArgs("DONE", syncedBefore).WillReturnRows(tt.mockRows)
+			}
+
+			got, err := repo.GetStale(context.Background(), syncedBefore)
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if len(got) != tt.expectedLen {
+				t.Errorf("Expected length %v, got %v", tt.expectedLen, len(got))
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAirportRepository_GetByStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockRows    *sqlmock.Rows
+		mockError   error
+		expectedLen int
+		expectedErr error
+	}{
+		{
+			name: "Success get airports by status",
+			mockRows: sqlmock.NewRows([]string{"id", "icao", "status", "attempt_count"}).
+				AddRow(1, "KAVL", "FAILED", 5).
+				AddRow(2, "KLAX", "FAILED", 5),
+			expectedLen: 2,
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAirportRepository(db)
+			query := `SELECT (.+) FROM airport WHERE status = \$1 ORDER BY id`
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(query).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(query).WithArgs("FAILED").WillReturnRows(tt.mockRows)
+			}
+
+			got, err := repo.GetByStatus(context.Background(), "FAILED")
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if len(got) != tt.expectedLen {
+				t.Errorf("Expected length %v, got %v", tt.expectedLen, len(got))
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAirportRepository_CountByStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockRows    *sqlmock.Rows
+		mockError   error
+		expected    map[string]int
+		expectedErr error
+	}{
+		{
+			name: "Success count airports by status",
+			mockRows: sqlmock.NewRows([]string{"status", "count"}).
+				AddRow(dto.AirportStatusPending, 12).
+				AddRow(dto.AirportStatusDone, 30),
+			expected: map[string]int{dto.AirportStatusPending: 12, dto.AirportStatusDone: 30},
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAirportRepository(db)
+			query := `SELECT status, COUNT\(\*\) AS count FROM airport GROUP BY status`
 
 			if tt.mockError != nil {
 				mock.ExpectQuery(query).WillReturnError(tt.mockError)
@@ -144,7 +369,58 @@ func TestAirportRepository_GetAllByPending(t *testing.T) {
 				mock.ExpectQuery(query).WillReturnRows(tt.mockRows)
 			}
 
-			got, err := repo.GetAllPending(context.Background())
+			got, err := repo.CountByStatus(context.Background())
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr == nil && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected counts %v, got %v", tt.expected, got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAirportRepository_GetByICAOs(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockRows    *sqlmock.Rows
+		mockError   error
+		expectedLen int
+		expectedErr error
+	}{
+		{
+			name: "Success get airports by ICAO",
+			mockRows: sqlmock.NewRows([]string{"id", "icao", "status"}).
+				AddRow(1, "KAVL", "DONE").
+				AddRow(2, "KLAX", "FAILED"),
+			expectedLen: 2,
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAirportRepository(db)
+			query := `SELECT (.+) FROM airport WHERE icao = ANY\(\$1\) ORDER BY id`
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(query).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(query).WithArgs(pq.Array([]string{"KAVL", "KLAX"})).WillReturnRows(tt.mockRows)
+			}
+
+			got, err := repo.GetByICAOs(context.Background(), []string{"KAVL", "KLAX"})
 			if err != nil && err.Error() != tt.expectedErr.Error() {
 				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
 			}
@@ -195,13 +471,19 @@ func TestAirportRepository_Insert(t *testing.T) {
 			repo := NewAirportRepository(db)
 			query := `INSERT INTO airport (.+) VALUES (.+)`
 
+			mock.ExpectBegin()
 			if tt.mockError != nil {
 				mock.ExpectQuery(query).WillReturnError(tt.mockError)
+				mock.ExpectRollback()
 			} else {
 				mock.ExpectQuery(query).WillReturnRows(tt.mockRows)
+				mock.ExpectExec(`INSERT INTO airport_history`).
+					WithArgs(0, "KLAX", dto.AirportHistoryActionInsert, "import", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			}
 
-			got, err := repo.Insert(context.Background(), &dto.Airport{})
+			got, err := repo.Insert(utils.WithActor(context.Background(), "import"), &dto.Airport{})
 			if err != nil && err.Error() != tt.expectedErr.Error() {
 				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
 			}
@@ -313,6 +595,64 @@ func TestAirportRepository_GetByICAOOrFacilityName(t *testing.T) {
 	}
 }
 
+func TestAirportRepository_GetNearby(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockRows    *sqlmock.Rows
+		mockError   error
+		expectedLen int
+		expectedErr error
+	}{
+		{
+			name: "Success get nearby airports",
+			mockRows: sqlmock.NewRows([]string{
+				"id", "icao", "latitude", "longitude", "latitude_deg", "longitude_deg", "status", "distance_nm",
+			}).AddRow(1, "KADT", "33-06-24.2800N", "088-11-49.8300W", 33.106744, -88.197175, "DONE", 0.0).
+				AddRow(2, "KAIV", "33-06-22.0000N", "088-11-00.0000W", 33.106111, -88.183333, "DONE", 0.7),
+			expectedLen: 2,
+		},
+		{
+			name:        "Error DB",
+			mockRows:    nil,
+			mockError:   sql.ErrConnDone,
+			expectedLen: 0,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	lat, lon, radiusNm := 33.1, -88.2, 25.0
+	limit, offset := 20, 0
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAirportRepository(db)
+			query := `SELECT (.+) FROM airport WHERE latitude_deg BETWEEN (.+) ORDER BY distance_nm`
+			args := []driver.Value{lat, lon, radiusNm, sqlmock.AnyArg(), limit, offset}
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(query).WithArgs(args...).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(query).WithArgs(args...).WillReturnRows(tt.mockRows)
+			}
+
+			got, err := repo.GetNearby(context.Background(), lat, lon, radiusNm, limit, offset)
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if len(got) != tt.expectedLen {
+				t.Errorf("Expected len %v, got %v", tt.expectedLen, len(got))
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
 func TestAirportRepository_GetById(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -418,10 +758,18 @@ func TestAirportRepository_UpdateById(t *testing.T) {
 
 			query := `UPDATE airport SET (.+) WHERE id = (.+)`
 
+			mock.ExpectBegin()
 			if tt.mockError != nil {
-				mock.ExpectQuery(query).WillReturnError(tt.mockError)
+				mock.ExpectQuery(`SELECT (.+) FROM airport WHERE id = (.+) FOR UPDATE`).WithArgs(tt.id).WillReturnError(tt.mockError)
+				mock.ExpectRollback()
 			} else {
+				mock.ExpectQuery(`SELECT (.+) FROM airport WHERE id = (.+) FOR UPDATE`).WithArgs(tt.id).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "icao", "manager_phone", "status"}).AddRow(0, "KLAX", "654321", "PENDING"))
 				mock.ExpectQuery(query).WillReturnRows(tt.mockRows)
+				mock.ExpectExec(`INSERT INTO airport_history`).
+					WithArgs(0, "KLAX", dto.AirportHistoryActionUpdate, dto.ActorAPI, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			}
 
 			got, err := repo.UpdateById(context.Background(), airport)
@@ -469,15 +817,23 @@ func TestAirportRepository_UpdateByICAO(t *testing.T) {
 
 			repo := NewAirportRepository(db)
 
-			query := `UPDATE airport AS a SET`
+			query := `UPDATE airport AS a SET (.+) last_synced_at = NOW\(\), attempt_count = 0, (.+) RETURNING a.id`
 
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT (.+) FROM airport WHERE icao = ANY(.+) FOR UPDATE`).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "icao", "status"}).AddRow(1, "KLAX", "PENDING"))
 			if tt.mockError != nil {
-				mock.ExpectExec(query).WillReturnError(tt.mockError)
+				mock.ExpectQuery(query).WillReturnError(tt.mockError)
+				mock.ExpectRollback()
 			} else {
-				mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(1, int64(len(airports))))
+				mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"id", "icao", "manager", "status"}).AddRow(1, "KLAX", "Manager1", "DONE"))
+				mock.ExpectExec(`INSERT INTO airport_history`).
+					WithArgs(1, "KLAX", dto.AirportHistoryActionUpdate, "sync_run:7", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			}
 
-			err := repo.UpdateByICAO(context.Background(), airports)
+			err := repo.UpdateByICAO(utils.WithActor(context.Background(), utils.SyncRunActor(7)), airports)
 			if err != nil && tt.expectedErr != nil && err.Error() != tt.expectedErr.Error() {
 				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
 			}
@@ -494,17 +850,17 @@ func TestAirportRepository_DELETE(t *testing.T) {
 		name        string
 		id          int
 		mockError   error
-		mockResult  driver.Result
+		mockRows    *sqlmock.Rows
 		expectedErr error
 	}{
 		{
-			name:       "Success delete airport by id",
-			id:         0,
-			mockResult: sqlmock.NewResult(1, 1),
+			name:     "Success delete airport by id",
+			id:       0,
+			mockRows: sqlmock.NewRows([]string{"id", "icao", "status"}).AddRow(0, "KLAX", "DONE"),
 		},
 		{
 			name:        "No airport found to be deleted",
-			mockResult:  sqlmock.NewResult(0, 0),
+			mockRows:    sqlmock.NewRows([]string{"id", "icao", "status"}),
 			expectedErr: fmt.Errorf("No airport found with id 0"),
 		},
 		{
@@ -521,12 +877,21 @@ func TestAirportRepository_DELETE(t *testing.T) {
 
 			repo := NewAirportRepository(db)
 
-			query := `DELETE FROM airport WHERE id = (.+)`
+			query := `DELETE FROM airport WHERE id = (.+) RETURNING`
 
+			mock.ExpectBegin()
 			if tt.mockError != nil {
-				mock.ExpectExec(query).WithArgs(tt.id).WillReturnError(tt.mockError)
+				mock.ExpectQuery(query).WithArgs(tt.id).WillReturnError(tt.mockError)
+				mock.ExpectRollback()
+			} else if tt.expectedErr != nil {
+				mock.ExpectQuery(query).WithArgs(tt.id).WillReturnRows(tt.mockRows)
+				mock.ExpectRollback()
 			} else {
-				mock.ExpectExec(query).WithArgs(tt.id).WillReturnResult(tt.mockResult)
+				mock.ExpectQuery(query).WithArgs(tt.id).WillReturnRows(tt.mockRows)
+				mock.ExpectExec(`INSERT INTO airport_history`).
+					WithArgs(0, "KLAX", dto.AirportHistoryActionDelete, dto.ActorAPI, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			}
 
 			err := repo.Delete(context.Background(), tt.id)
@@ -540,3 +905,249 @@ func TestAirportRepository_DELETE(t *testing.T) {
 		})
 	}
 }
+
+func TestAirportRepository_UpsertByICAO(t *testing.T) {
+	tests := []struct {
+		name            string
+		existingRows    *sqlmock.Rows
+		mockRows        *sqlmock.Rows
+		mockError       error
+		expectedCreated bool
+		expectedAction  string
+		expectedErr     error
+	}{
+		{
+			name:            "Success insert new airport",
+			existingRows:    sqlmock.NewRows([]string{"id", "icao", "status"}),
+			mockRows:        sqlmock.NewRows([]string{"id", "icao", "status", "created"}).AddRow(3, "KLAX", "PENDING", true),
+			expectedCreated: true,
+			expectedAction:  dto.AirportHistoryActionInsert,
+		},
+		{
+			name:           "Success update existing airport",
+			existingRows:   sqlmock.NewRows([]string{"id", "icao", "status"}).AddRow(3, "KLAX", "DONE"),
+			mockRows:       sqlmock.NewRows([]string{"id", "icao", "status", "created"}).AddRow(3, "KLAX", "PENDING", false),
+			expectedAction: dto.AirportHistoryActionUpdate,
+		},
+		{
+			name:         "Success unchanged airport not recorded",
+			existingRows: sqlmock.NewRows([]string{"id", "icao", "status"}).AddRow(3, "KLAX", "PENDING"),
+			mockRows:     sqlmock.NewRows([]string{"id", "icao", "status", "created"}).AddRow(3, "KLAX", "PENDING", false),
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAirportRepository(db)
+			query := `INSERT INTO airport (.+) VALUES (.+) ON CONFLICT \(icao\) DO UPDATE SET (.+)`
+
+			mock.ExpectBegin()
+			if tt.mockError != nil {
+				mock.ExpectQuery(`SELECT (.+) FROM airport WHERE icao = (.+) FOR UPDATE`).WithArgs("KLAX").WillReturnError(tt.mockError)
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectQuery(`SELECT (.+) FROM airport WHERE icao = (.+) FOR UPDATE`).WithArgs("KLAX").WillReturnRows(tt.existingRows)
+				mock.ExpectQuery(query).WillReturnRows(tt.mockRows)
+				if tt.expectedAction != "" {
+					mock.ExpectExec(`INSERT INTO airport_history`).
+						WithArgs(3, "KLAX", tt.expectedAction, dto.ActorAPI, sqlmock.AnyArg()).
+						WillReturnResult(sqlmock.NewResult(1, 1))
+				}
+				mock.ExpectCommit()
+			}
+
+			got, err := repo.UpsertByICAO(context.Background(), &dto.Airport{ICAO: "KLAX", Latitude: &latitude, Longitude: &longitude, Status: "PENDING"})
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if got != tt.expectedCreated {
+				t.Errorf("Expected created %v, got %v", tt.expectedCreated, got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAirportRepository_UpsertByICAO_Reimport(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewAirportRepository(db)
+	columns := []string{"id", "type", "facility_name", "faa", "icao", "region", "state", "county", "city", "ownership", "use",
+		"manager", "manager_phone", "latitude", "longitude", "status"}
+	renamed := "Los Angeles Intl"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM airport WHERE icao = (.+) FOR UPDATE`).WithArgs("KLAX").WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(3, atype, facilityName, faa, "KLAX", region, state, county, city, ownership, use, manager, managerPhone, latitude, longitude, "DONE"))
+	// A NASR row carries no manager contacts, the stored ones are kept and the merged row is still complete
+	mock.ExpectQuery(`INSERT INTO airport (.+) ON CONFLICT \(icao\) DO UPDATE SET (.+)manager = COALESCE\(EXCLUDED.manager, airport.manager\),\s+manager_phone = COALESCE\(EXCLUDED.manager_phone, airport.manager_phone\)`).
+		WithArgs(atype, renamed, faa, "KLAX", region, state, county, city, ownership, use, nil, nil, latitude, longitude,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), "DONE", []byte(`{"facility_name":"MANUAL"}`)).
+		WillReturnRows(sqlmock.NewRows(append(columns, "created")).
+			AddRow(3, atype, renamed, faa, "KLAX", region, state, county, city, ownership, use, manager, managerPhone, latitude, longitude, "DONE", false))
+	mock.ExpectExec(`INSERT INTO airport_history`).
+		WithArgs(3, "KLAX", dto.AirportHistoryActionUpdate, dto.ActorAPI, []byte(`[{"field":"facility_name","before":"Lorem ipsum","after":"Los Angeles Intl"}]`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	created, err := repo.UpsertByICAO(context.Background(), &dto.Airport{
+		Type: &atype, FacilityName: &renamed, FAA: &faa, ICAO: "KLAX", Region: &region, State: &state, County: &county,
+		City: &city, Ownership: &ownership, Use: &use, Latitude: &latitude, Longitude: &longitude, Status: "PENDING",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created {
+		t.Errorf("Expected the existing airport to be updated")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+func TestAirportRepository_StreamByICAOOrFacilityName(t *testing.T) {
+	columns := []string{"id", "icao", "facility_name", "status"}
+	tests := []struct {
+		name          string
+		mockRows      *sqlmock.Rows
+		mockError     error
+		fnErr         error
+		icao          string
+		facilityName  string
+		expectedICAOs []string
+		expectedErr   error
+	}{
+		{
+			name:          "Success stream all airports",
+			mockRows:      sqlmock.NewRows(columns).AddRow(1, "KLAX", "Lorem ipsum", "DONE").AddRow(2, "KJFK", "Dolor", "PENDING"),
+			expectedICAOs: []string{"KLAX", "KJFK"},
+		},
+		{
+			name:          "Success stream by icao and facility name",
+			mockRows:      sqlmock.NewRows(columns).AddRow(1, "KLAX", "Lorem ipsum", "DONE"),
+			icao:          "KLAX",
+			facilityName:  "Lorem",
+			expectedICAOs: []string{"KLAX"},
+		},
+		{
+			name:          "Error from callback stops iteration",
+			mockRows:      sqlmock.NewRows(columns).AddRow(1, "KLAX", "Lorem ipsum", "DONE").AddRow(2, "KJFK", "Dolor", "PENDING"),
+			fnErr:         fmt.Errorf("write failed"),
+			expectedICAOs: []string{"KLAX"},
+			expectedErr:   fmt.Errorf("write failed"),
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAirportRepository(db)
+			query := `SELECT (.+) FROM airport(.+)ORDER BY id`
+
+			args := []driver.Value{}
+			if tt.icao != "" {
+				args = append(args, tt.icao)
+			}
+			if tt.facilityName != "" {
+				args = append(args, "%"+tt.facilityName+"%")
+			}
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(query).WithArgs(args...).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(query).WithArgs(args...).WillReturnRows(tt.mockRows)
+			}
+
+			var got []string
+			err := repo.StreamByICAOOrFacilityName(context.Background(), tt.icao, tt.facilityName, func(airport *dto.Airport) error {
+				got = append(got, airport.ICAO)
+				return tt.fnErr
+			})
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedICAOs) {
+				t.Errorf("Expected icaos %v, got %v", tt.expectedICAOs, got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAirportRepository_GetHistory(t *testing.T) {
+	createdAt := time.Date(2025, time.October, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		mockRows       *sqlmock.Rows
+		mockError      error
+		expectedResult []dto.AirportHistory
+		expectedErr    error
+	}{
+		{
+			name: "Success get airport history",
+			mockRows: sqlmock.NewRows([]string{"id", "airport_id", "icao", "action", "actor", "changes", "created_at"}).
+				AddRow(2, 1, "KLAX", "UPDATE", "sync_run:7", []byte(`[{"field":"manager_phone","before":"123456","after":null}]`), createdAt),
+			expectedResult: []dto.AirportHistory{{ID: 2, AirportID: 1, ICAO: "KLAX", Action: "UPDATE", Actor: "sync_run:7",
+				Changes: dto.FieldChanges{{Field: "manager_phone", Before: &managerPhone}}, CreatedAt: createdAt}},
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAirportRepository(db)
+			query := `SELECT (.+) FROM airport_history WHERE airport_id = (.+) ORDER BY created_at DESC`
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(query).WithArgs(1, 10, 0).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(query).WithArgs(1, 10, 0).WillReturnRows(tt.mockRows)
+			}
+
+			got, err := repo.GetHistory(context.Background(), 1, 10, 0)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedResult) {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
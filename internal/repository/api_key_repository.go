@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"aviation-service/internal/dto"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:generate moq -out ../mock/api_key_repository_mock.go -pkg=mock . IAPIKeyRepository
+type IAPIKeyRepository interface {
+	Create(ctx context.Context, key *dto.APIKey) (*dto.APIKey, error)
+	GetActiveByHash(ctx context.Context, keyHash string) (*dto.APIKey, error)
+	GetAll(ctx context.Context) ([]dto.APIKey, error)
+	Revoke(ctx context.Context, id int) error
+}
+
+const apiKeyColumns = `id, name, prefix, key_hash, role, created_at, revoked_at`
+
+type APIKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *dto.APIKey) (*dto.APIKey, error) {
+	query := `INSERT INTO api_key (name, prefix, key_hash, role)
+			  VALUES ($1, $2, $3, $4)
+			  RETURNING ` + apiKeyColumns
+
+	var created dto.APIKey
+	err := r.db.GetContext(ctx, &created, query, key.Name, key.Prefix, key.KeyHash, key.Role)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetActiveByHash returns the key with this hash unless it was revoked, nil when there is none
+func (r *APIKeyRepository) GetActiveByHash(ctx context.Context, keyHash string) (*dto.APIKey, error) {
+	var key dto.APIKey
+	query := `SELECT ` + apiKeyColumns + `
+			  FROM api_key
+			  WHERE key_hash = $1 AND revoked_at IS NULL`
+	err := r.db.GetContext(ctx, &key, query, keyHash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) GetAll(ctx context.Context) ([]dto.APIKey, error) {
+	var keys []dto.APIKey
+	query := `SELECT ` + apiKeyColumns + `
+			  FROM api_key
+			  ORDER BY id`
+	err := r.db.SelectContext(ctx, &keys, query)
+	return keys, err
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, id int) error {
+	query := `UPDATE api_key SET revoked_at = NOW()
+			  WHERE id = $1 AND revoked_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("No active API key found with id %d", id)
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var apiKeyColumns = []string{"id", "name", "prefix", "key_hash", "role", "created_at", "revoked_at"}
+
+const apiKeyHash = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+func TestAPIKeyRepository_Create(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockRows    *sqlmock.Rows
+		mockError   error
+		expectedID  int
+		expectedErr error
+	}{
+		{
+			name: "Success create API key",
+			mockRows: sqlmock.NewRows(apiKeyColumns).
+				AddRow(1, "ci", "avk_1a2b3c4d", apiKeyHash, "editor", time.Now(), nil),
+			expectedID: 1,
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAPIKeyRepository(db)
+			query := `INSERT INTO api_key \(name, prefix, key_hash, role\) VALUES (.+) RETURNING (.+)`
+			args := []driver.Value{"ci", "avk_1a2b3c4d", apiKeyHash, "editor"}
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(query).WithArgs(args...).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(query).WithArgs(args...).WillReturnRows(tt.mockRows)
+			}
+
+			got, err := repo.Create(context.Background(), &dto.APIKey{Name: "ci", Prefix: "avk_1a2b3c4d", KeyHash: apiKeyHash, Role: "editor"})
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedID != 0 && (got == nil || got.ID != tt.expectedID) {
+				t.Errorf("Expected API key %d, got %+v", tt.expectedID, got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRepository_GetActiveByHash(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockRows    *sqlmock.Rows
+		mockError   error
+		expectedID  int
+		expectedErr error
+	}{
+		{
+			name: "Success get active API key",
+			mockRows: sqlmock.NewRows(apiKeyColumns).
+				AddRow(2, "dashboard", "avk_5e6f7a8b", apiKeyHash, "reader", time.Now(), nil),
+			expectedID: 2,
+		},
+		{
+			name:      "Unknown or revoked API key",
+			mockError: sql.ErrNoRows,
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAPIKeyRepository(db)
+			query := `SELECT (.+) FROM api_key WHERE key_hash = \$1 AND revoked_at IS NULL`
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(query).WithArgs(apiKeyHash).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(query).WithArgs(apiKeyHash).WillReturnRows(tt.mockRows)
+			}
+
+			got, err := repo.GetActiveByHash(context.Background(), apiKeyHash)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedID == 0 && got != nil {
+				t.Errorf("Expected no API key, got %+v", got)
+			}
+			if tt.expectedID != 0 && (got == nil || got.ID != tt.expectedID) {
+				t.Errorf("Expected API key %d, got %+v", tt.expectedID, got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAPIKeyRepository_GetAll(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	revokedAt := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM api_key ORDER BY id`).WillReturnRows(sqlmock.NewRows(apiKeyColumns).
+		AddRow(1, "ci", "avk_1a2b3c4d", apiKeyHash, "editor", time.Now(), revokedAt).
+		AddRow(2, "dashboard", "avk_5e6f7a8b", apiKeyHash, "reader", time.Now(), nil))
+
+	got, err := NewAPIKeyRepository(db).GetAll(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(got) != 2 || got[0].RevokedAt == nil || got[1].RevokedAt != nil {
+		t.Errorf("Expected the revoked and the active key, got %+v", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet SQL expectations: %v", err)
+	}
+}
+
+func TestAPIKeyRepository_Revoke(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockResult  driver.Result
+		mockError   error
+		expectedErr error
+	}{
+		{
+			name:       "Success revoke API key",
+			mockResult: sqlmock.NewResult(0, 1),
+		},
+		{
+			name:        "No active API key",
+			mockResult:  sqlmock.NewResult(0, 0),
+			expectedErr: fmt.Errorf("No active API key found with id 1"),
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewAPIKeyRepository(db)
+			query := `UPDATE api_key SET revoked_at = NOW\(\) WHERE id = \$1 AND revoked_at IS NULL`
+
+			if tt.mockError != nil {
+				mock.ExpectExec(query).WithArgs(1).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectExec(query).WithArgs(1).WillReturnResult(tt.mockResult)
+			}
+
+			err := repo.Revoke(context.Background(), 1)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
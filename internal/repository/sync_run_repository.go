@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"aviation-service/internal/dto"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:generate moq -out ../mock/sync_run_repository_mock.go -pkg=mock . ISyncRunRepository
+type ISyncRunRepository interface {
+	Create(ctx context.Context, run *dto.SyncRun) (*dto.SyncRun, error)
+	Finish(ctx context.Context, run *dto.SyncRun) error
+	InsertItems(ctx context.Context, items []dto.SyncRunItem) error
+	GetAll(ctx context.Context, limit, offset int) ([]dto.SyncRun, error)
+	GetById(ctx context.Context, id int) (*dto.SyncRun, error)
+	GetRunning(ctx context.Context) (*dto.SyncRun, error)
+	GetLastSuccess(ctx context.Context) (map[string]time.Time, error)
+}
+
+const syncRunColumns = `id, trigger, mode, status, started_at, finished_at, total, success, failed, error, error_message`
+
+type SyncRunRepository struct {
+	db *sqlx.DB
+}
+
+func NewSyncRunRepository(db *sqlx.DB) *SyncRunRepository {
+	return &SyncRunRepository{db: db}
+}
+
+func (r *SyncRunRepository) Create(ctx context.Context, run *dto.SyncRun) (*dto.SyncRun, error) {
+	query := `INSERT INTO sync_run (trigger, mode, status, total)
+			  VALUES ($1, $2, $3, $4)
+			  RETURNING ` + syncRunColumns
+	var created dto.SyncRun
+	err := r.db.GetContext(ctx, &created, query, run.Trigger, run.Mode, run.Status, run.Total)
+	return &created, err
+}
+
+func (r *SyncRunRepository) Finish(ctx context.Context, run *dto.SyncRun) error {
+	query := `UPDATE sync_run SET
+			status = $1,
+			finished_at = NOW(),
+			total = $2,
+			success = $3,
+			failed = $4,
+			error = $5,
+			error_message = $6
+			WHERE id = $7`
+	result, err := r.db.ExecContext(ctx, query, run.Status, run.Total, run.Success, run.Failed,
+		run.Error, run.ErrorMessage, run.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("No sync run found with id %d", run.ID)
+	}
+	return err
+}
+
+func (r *SyncRunRepository) InsertItems(ctx context.Context, items []dto.SyncRunItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	values := []interface{}{}
+	placeholders := []string{}
+	for i, item := range items {
+		base := i*8 + 1
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			base, base+1, base+2, base+3, base+4, base+5, base+6, base+7))
+		values = append(values, item.SyncRunID, item.Batch, item.ICAO, item.Outcome, item.Status, item.Error, item.HTTPStatus, changesValue(item.Changes))
+	}
+
+	query := `INSERT INTO sync_run_item (sync_run_id, batch, icao, outcome, status, error, http_status, changes)
+			  VALUES ` + strings.Join(placeholders, ",")
+	_, err := r.db.ExecContext(ctx, query, values...)
+	return err
+}
+
+func (r *SyncRunRepository) GetAll(ctx context.Context, limit, offset int) ([]dto.SyncRun, error) {
+	var runs []dto.SyncRun
+	query := `SELECT ` + syncRunColumns + `
+			  FROM sync_run
+			  ORDER BY id DESC
+			  LIMIT $1 OFFSET $2`
+	err := r.db.SelectContext(ctx, &runs, query, limit, offset)
+	return runs, err
+}
+
+func (r *SyncRunRepository) GetById(ctx context.Context, id int) (*dto.SyncRun, error) {
+	var run dto.SyncRun
+	query := `SELECT ` + syncRunColumns + `
+			  FROM sync_run
+			  WHERE id = $1`
+	err := r.db.GetContext(ctx, &run, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	itemQuery := `SELECT id, sync_run_id, batch, icao, outcome, status, error, http_status, changes, created_at
+				  FROM sync_run_item
+				  WHERE sync_run_id = $1
+				  ORDER BY batch, icao`
+	err = r.db.SelectContext(ctx, &run.Items, itemQuery, id)
+	return &run, err
+}
+
+// GetLastSuccess returns when the latest DONE run of every sync mode finished
+func (r *SyncRunRepository) GetLastSuccess(ctx context.Context) (map[string]time.Time, error) {
+	var rows []struct {
+		Mode       string    `db:"mode"`
+		FinishedAt time.Time `db:"finished_at"`
+	}
+	query := `SELECT mode, MAX(finished_at) AS finished_at
+			  FROM sync_run
+			  WHERE status = $1 AND finished_at IS NOT NULL
+			  GROUP BY mode`
+
+	if err := r.db.SelectContext(ctx, &rows, query, dto.SyncRunStatusDone); err != nil {
+		return nil, err
+	}
+	lastSuccess := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		lastSuccess[row.Mode] = row.FinishedAt
+	}
+	return lastSuccess, nil
+}
+
+// GetRunning returns the most recent run still RUNNING, nil when there is none
+func (r *SyncRunRepository) GetRunning(ctx context.Context) (*dto.SyncRun, error) {
+	var run dto.SyncRun
+	query := `SELECT ` + syncRunColumns + `
+			  FROM sync_run
+			  WHERE status = $1
+			  ORDER BY id DESC
+			  LIMIT 1`
+	err := r.db.GetContext(ctx, &run, query, dto.SyncRunStatusRunning)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// changesValue stores items without changes as NULL rather than an empty list
+func changesValue(changes dto.FieldChanges) interface{} {
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
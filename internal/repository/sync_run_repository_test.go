@@ -0,0 +1,413 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var syncRunColumns = []string{
+	"id", "trigger", "mode", "status", "started_at", "finished_at", "total", "success", "failed", "error", "error_message",
+}
+
+func TestSyncRunRepository_Create(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockRows    *sqlmock.Rows
+		mockError   error
+		expectedID  int
+		expectedErr error
+	}{
+		{
+			name: "Success create sync run",
+			mockRows: sqlmock.NewRows(syncRunColumns).
+				AddRow(1, dto.SyncTriggerCron, dto.SyncModePending, dto.SyncRunStatusRunning, time.Now(), nil, 0, 0, 0, 0, nil),
+			expectedID: 1,
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewSyncRunRepository(db)
+			query := `INSERT INTO sync_run (.+) VALUES (.+)`
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(query).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(query).WithArgs(dto.SyncTriggerCron, dto.SyncModeStale, dto.SyncRunStatusRunning, 0).WillReturnRows(tt.mockRows)
+			}
+
+			got, err := repo.Create(context.Background(), &dto.SyncRun{Trigger: dto.SyncTriggerCron, Mode: dto.SyncModeStale, Status: dto.SyncRunStatusRunning})
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if got.ID != tt.expectedID {
+				t.Errorf("Expected id %v, got %v", tt.expectedID, got.ID)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestSyncRunRepository_Finish(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockResult  sql.Result
+		mockError   error
+		expectedErr error
+	}{
+		{
+			name:       "Success finish sync run",
+			mockResult: sqlmock.NewResult(0, 1),
+		},
+		{
+			name:        "No sync run found",
+			mockResult:  sqlmock.NewResult(0, 0),
+			expectedErr: fmt.Errorf("No sync run found with id 1"),
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewSyncRunRepository(db)
+			query := `UPDATE sync_run SET (.+) WHERE id = (.+)`
+
+			if tt.mockError != nil {
+				mock.ExpectExec(query).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectExec(query).WillReturnResult(tt.mockResult)
+			}
+
+			err := repo.Finish(context.Background(), &dto.SyncRun{ID: 1, Status: dto.SyncRunStatusDone, Total: 3, Success: 3})
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestSyncRunRepository_InsertItems(t *testing.T) {
+	message, httpStatus := "Upstream responded with status 503", 503
+	before, after := "828-000-0000", "828-684-2226"
+	tests := []struct {
+		name        string
+		items       []dto.SyncRunItem
+		mockError   error
+		expectArgs  []driver.Value
+		expectExec  bool
+		expectedErr error
+	}{
+		{
+			name: "Success insert items",
+			items: []dto.SyncRunItem{
+				{SyncRunID: 1, Batch: 0, ICAO: "KAVL", Outcome: dto.SyncOutcomeOK, Status: "DONE",
+					Changes: dto.FieldChanges{{Field: "manager_phone", Before: &before, After: &after}}},
+				{SyncRunID: 1, Batch: 0, ICAO: "KLAX", Outcome: dto.SyncOutcomeFetchError, Status: "PENDING", Error: &message, HTTPStatus: &httpStatus},
+			},
+			expectArgs: []driver.Value{1, 0, "KAVL", dto.SyncOutcomeOK, "DONE", nil, nil,
+				[]byte(`[{"field":"manager_phone","before":"828-000-0000","after":"828-684-2226"}]`),
+				1, 0, "KLAX", dto.SyncOutcomeFetchError, "PENDING", message, httpStatus, nil},
+			expectExec: true,
+		},
+		{
+			name: "Success no items",
+		},
+		{
+			name:        "Error DB",
+			items:       []dto.SyncRunItem{{SyncRunID: 1, ICAO: "KAVL", Outcome: dto.SyncOutcomeOK, Status: "DONE"}},
+			mockError:   sql.ErrConnDone,
+			expectExec:  true,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewSyncRunRepository(db)
+			query := `INSERT INTO sync_run_item (.+) VALUES (.+)`
+
+			if tt.expectExec {
+				if tt.mockError != nil {
+					mock.ExpectExec(query).WillReturnError(tt.mockError)
+				} else {
+					mock.ExpectExec(query).WithArgs(tt.expectArgs...).WillReturnResult(sqlmock.NewResult(0, int64(len(tt.items))))
+				}
+			}
+
+			err := repo.InsertItems(context.Background(), tt.items)
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestSyncRunRepository_GetAll(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockRows    *sqlmock.Rows
+		mockError   error
+		expectedLen int
+		expectedErr error
+	}{
+		{
+			name: "Success get all sync runs",
+			mockRows: sqlmock.NewRows(syncRunColumns).
+				AddRow(2, dto.SyncTriggerAPI, dto.SyncModePending, dto.SyncRunStatusRunning, time.Now(), nil, 0, 0, 0, 0, nil).
+				AddRow(1, dto.SyncTriggerCron, dto.SyncModePending, dto.SyncRunStatusDone, time.Now(), time.Now(), 30, 28, 1, 1, nil),
+			expectedLen: 2,
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewSyncRunRepository(db)
+			query := `SELECT (.+) FROM sync_run ORDER BY id DESC`
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(query).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(query).WithArgs(10, 0).WillReturnRows(tt.mockRows)
+			}
+
+			got, err := repo.GetAll(context.Background(), 10, 0)
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if len(got) != tt.expectedLen {
+				t.Errorf("Expected length %v, got %v", tt.expectedLen, len(got))
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestSyncRunRepository_GetById(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockRows      *sqlmock.Rows
+		mockItemRows  *sqlmock.Rows
+		mockError     error
+		expectedNil   bool
+		expectedItems int
+		expectedErr   error
+	}{
+		{
+			name: "Success get sync run with items",
+			mockRows: sqlmock.NewRows(syncRunColumns).
+				AddRow(1, dto.SyncTriggerCron, dto.SyncModePending, dto.SyncRunStatusDone, time.Now(), time.Now(), 2, 1, 0, 1, nil),
+			mockItemRows: sqlmock.NewRows([]string{"id", "sync_run_id", "batch", "icao", "outcome", "status", "error", "http_status", "changes", "created_at"}).
+				AddRow(1, 1, 0, "KAVL", dto.SyncOutcomeOK, "DONE", nil, nil, `[{"field":"city","after":"ASHEVILLE"}]`, time.Now()).
+				AddRow(2, 1, 1, "KLAX", dto.SyncOutcomeFetchError, "PENDING", "Upstream responded with status 503", 503, nil, time.Now()),
+			expectedItems: 2,
+		},
+		{
+			name:        "No sync run found",
+			mockError:   sql.ErrNoRows,
+			expectedNil: true,
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedNil: true,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewSyncRunRepository(db)
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(`SELECT (.+) FROM sync_run WHERE id = (.+)`).WithArgs(1).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(`SELECT (.+) FROM sync_run WHERE id = (.+)`).WithArgs(1).WillReturnRows(tt.mockRows)
+				mock.ExpectQuery(`SELECT (.+) FROM sync_run_item WHERE sync_run_id = (.+)`).WithArgs(1).WillReturnRows(tt.mockItemRows)
+			}
+
+			got, err := repo.GetById(context.Background(), 1)
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if (got == nil) != tt.expectedNil {
+				t.Errorf("Expected nil result %v, got %+v", tt.expectedNil, got)
+			}
+			if got != nil && len(got.Items) != tt.expectedItems {
+				t.Errorf("Expected %d items, got %d", tt.expectedItems, len(got.Items))
+			}
+			if got != nil && len(got.Items) == 2 && (got.Items[0].HTTPStatus != nil || got.Items[1].HTTPStatus == nil || *got.Items[1].HTTPStatus != 503) {
+				t.Errorf("Expected HTTP status only on the failed item, got %v and %v", got.Items[0].HTTPStatus, got.Items[1].HTTPStatus)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestSyncRunRepository_GetRunning(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockRows    *sqlmock.Rows
+		mockError   error
+		expectedID  int
+		expectedErr error
+	}{
+		{
+			name: "Success get running sync run",
+			mockRows: sqlmock.NewRows(syncRunColumns).
+				AddRow(3, dto.SyncTriggerAPI, dto.SyncModePending, dto.SyncRunStatusRunning, time.Now(), nil, 0, 0, 0, 0, nil),
+			expectedID: 3,
+		},
+		{
+			name:      "No running sync run",
+			mockError: sql.ErrNoRows,
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewSyncRunRepository(db)
+			query := `SELECT (.+) FROM sync_run WHERE status = (.+) ORDER BY id DESC LIMIT 1`
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(query).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(query).WithArgs(dto.SyncRunStatusRunning).WillReturnRows(tt.mockRows)
+			}
+
+			got, err := repo.GetRunning(context.Background())
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if tt.expectedID == 0 && got != nil {
+				t.Errorf("Expected no sync run, got %+v", got)
+			}
+			if tt.expectedID != 0 && (got == nil || got.ID != tt.expectedID) {
+				t.Errorf("Expected sync run %d, got %+v", tt.expectedID, got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestSyncRunRepository_GetLastSuccess(t *testing.T) {
+	finishedAt := time.Date(2026, 3, 1, 5, 10, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		mockRows    *sqlmock.Rows
+		mockError   error
+		expected    map[string]time.Time
+		expectedErr error
+	}{
+		{
+			name: "Success get last successful runs",
+			mockRows: sqlmock.NewRows([]string{"mode", "finished_at"}).
+				AddRow(dto.SyncModePending, finishedAt),
+			expected: map[string]time.Time{dto.SyncModePending: finishedAt},
+		},
+		{
+			name:        "Error DB",
+			mockError:   sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			defer db.Close()
+
+			repo := NewSyncRunRepository(db)
+			query := `SELECT mode, MAX\(finished_at\) AS finished_at FROM sync_run WHERE status = \$1 (.+) GROUP BY mode`
+
+			if tt.mockError != nil {
+				mock.ExpectQuery(query).WillReturnError(tt.mockError)
+			} else {
+				mock.ExpectQuery(query).WithArgs(dto.SyncRunStatusDone).WillReturnRows(tt.mockRows)
+			}
+
+			got, err := repo.GetLastSuccess(context.Background())
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr == nil && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected last success %v, got %v", tt.expected, got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet SQL expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"aviation-service/internal/dto"
+	"aviation-service/internal/repository"
+	"aviation-service/internal/utils"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+type AirportConflictService struct {
+	logger         *zap.SugaredLogger
+	conflictRepo   repository.IAirportConflictRepository
+	airportRepo    repository.IAirportRepository
+	airportService IAirportService
+}
+
+func NewAirportConflictService(logger *zap.SugaredLogger, conflictRepo repository.IAirportConflictRepository, airportRepo repository.IAirportRepository, airportService IAirportService) *AirportConflictService {
+	return &AirportConflictService{
+		logger:         logger,
+		conflictRepo:   conflictRepo,
+		airportRepo:    airportRepo,
+		airportService: airportService,
+	}
+}
+
+func (s *AirportConflictService) GetAllConflict(ctx context.Context, icao string, includeResolved bool, limit, offset int) ([]dto.AirportConflict, error) {
+	ctx, span := tracer.Start(ctx, "AirportConflictService.GetAllConflict")
+	defer span.End()
+
+	conflicts, err := s.conflictRepo.GetAll(ctx, icao, includeResolved, limit, offset)
+	if err != nil {
+		s.logger.Errorw("Failed to get airport conflicts", "error", err, "icao", icao)
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+// ResolveConflict closes an open conflict, accepting the upstream value writes it to the airport and marks the field as upstream again
+func (s *AirportConflictService) ResolveConflict(ctx context.Context, id int, resolution string) (*dto.AirportConflict, error) {
+	ctx, span := tracer.Start(ctx, "AirportConflictService.ResolveConflict")
+	defer span.End()
+
+	conflict, err := s.conflictRepo.GetById(ctx, id)
+	if err != nil {
+		s.logger.Errorw("Failed to get airport conflict", "error", err, "id", id)
+		return nil, err
+	}
+	if conflict == nil || conflict.ResolvedAt != nil {
+		return nil, fmt.Errorf("No open conflict found with id %d", id)
+	}
+
+	if resolution == dto.ConflictResolutionAcceptUpstream {
+		airport, err := s.airportRepo.GetById(ctx, conflict.AirportID)
+		if err != nil {
+			s.logger.Errorw("Failed to get conflicting airport", "error", err, "id", conflict.AirportID)
+			return nil, err
+		}
+		if airport == nil {
+			return nil, fmt.Errorf("No airport found with id %d", conflict.AirportID)
+		}
+		if err := utils.SetAirportField(airport, conflict.Field, conflict.UpstreamValue); err != nil {
+			return nil, err
+		}
+		if _, err := s.airportRepo.UpdateById(utils.WithFieldSource(ctx, dto.FieldSourceUpstream), airport); err != nil {
+			s.logger.Errorw("Failed to apply upstream value", "error", err, "id", conflict.AirportID, "field", conflict.Field)
+			return nil, err
+		}
+		s.airportService.InvalidateCache(ctx)
+	}
+
+	if err := s.conflictRepo.Resolve(ctx, id, resolution); err != nil {
+		s.logger.Errorw("Failed to resolve airport conflict", "error", err, "id", id)
+		return nil, err
+	}
+	return s.conflictRepo.GetById(ctx, id)
+}
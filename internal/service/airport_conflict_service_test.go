@@ -0,0 +1,124 @@
+package service_test
+
+import (
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/mock"
+	. "aviation-service/internal/service"
+	"aviation-service/internal/utils"
+	"aviation-service/pkg/logger"
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAirportConflictService_ResolveConflict(t *testing.T) {
+	manualPhone, upstreamPhone := "828-000-0000", "828-684-2226"
+	resolvedAt := time.Date(2025, time.October, 16, 18, 0, 0, 0, time.UTC)
+	open := &dto.AirportConflict{ID: 3, AirportID: 1, ICAO: "KAVL", Field: "manager_phone", ManualValue: &manualPhone, UpstreamValue: &upstreamPhone}
+
+	tests := []struct {
+		name           string
+		resolution     string
+		conflict       *dto.AirportConflict
+		airport        *dto.Airport
+		expectedPhone  *string
+		expectedSource string
+		expectedErr    error
+	}{
+		{
+			name:       "Success keep manual",
+			resolution: dto.ConflictResolutionKeepManual,
+			conflict:   open,
+		},
+		{
+			name:           "Success accept upstream",
+			resolution:     dto.ConflictResolutionAcceptUpstream,
+			conflict:       open,
+			airport:        &dto.Airport{ID: 1, ICAO: "KAVL", ManagerPhone: &manualPhone},
+			expectedPhone:  &upstreamPhone,
+			expectedSource: dto.FieldSourceUpstream,
+		},
+		{
+			name:        "Error conflict not found",
+			resolution:  dto.ConflictResolutionKeepManual,
+			expectedErr: fmt.Errorf("No open conflict found with id 3"),
+		},
+		{
+			name:        "Error conflict already resolved",
+			resolution:  dto.ConflictResolutionKeepManual,
+			conflict:    &dto.AirportConflict{ID: 3, AirportID: 1, Field: "manager_phone", ResolvedAt: &resolvedAt},
+			expectedErr: fmt.Errorf("No open conflict found with id 3"),
+		},
+		{
+			name:        "Error airport not found",
+			resolution:  dto.ConflictResolutionAcceptUpstream,
+			conflict:    open,
+			expectedErr: fmt.Errorf("No airport found with id 1"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resolution string
+			conflictRepo := &IAirportConflictRepositoryMock{
+				GetByIdFunc: func(ctx context.Context, id int) (*dto.AirportConflict, error) {
+					if tt.conflict == nil {
+						return nil, nil
+					}
+					conflict := *tt.conflict
+					if resolution != "" {
+						conflict.Resolution = &resolution
+						conflict.ResolvedAt = &resolvedAt
+					}
+					return &conflict, nil
+				},
+				ResolveFunc: func(ctx context.Context, id int, r string) error {
+					resolution = r
+					return nil
+				},
+			}
+			var updated *dto.Airport
+			var source string
+			repo := &IAirportRepositoryMock{
+				GetByIdFunc: func(ctx context.Context, id int) (*dto.Airport, error) {
+					return tt.airport, nil
+				},
+				UpdateByIdFunc: func(ctx context.Context, airport *dto.Airport) (*dto.Airport, error) {
+					updated, source = airport, utils.FieldSourceFromContext(ctx)
+					return airport, nil
+				},
+			}
+			airportService := &IAirportServiceMock{
+				InvalidateCacheFunc: func(ctx context.Context) error {
+					return nil
+				},
+			}
+			s := NewAirportConflictService(log, conflictRepo, repo, airportService)
+
+			got, err := s.ResolveConflict(context.Background(), 3, tt.resolution)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Resolution == nil || *got.Resolution != tt.resolution {
+				t.Errorf("Expected resolution %q, got %+v", tt.resolution, got)
+			}
+			if tt.expectedPhone == nil {
+				if updated != nil {
+					t.Errorf("Expected airport untouched, got %+v", updated)
+				}
+				return
+			}
+			if updated == nil || !reflect.DeepEqual(updated.ManagerPhone, tt.expectedPhone) || source != tt.expectedSource {
+				t.Errorf("Expected manager phone %q written as %s, got %+v as %s", *tt.expectedPhone, tt.expectedSource, updated, source)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"aviation-service/internal/dto"
+	"aviation-service/internal/repository"
+	"aviation-service/internal/utils"
+	"context"
+	"io"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type AirportExportService struct {
+	logger      *zap.SugaredLogger
+	airportRepo repository.IAirportRepository
+}
+
+func NewAirportExportService(logger *zap.SugaredLogger, airportRepo repository.IAirportRepository) *AirportExportService {
+	return &AirportExportService{
+		logger:      logger,
+		airportRepo: airportRepo,
+	}
+}
+
+// Export streams every airport matching the search filters into w, rows are written as they are read from the database
+func (s *AirportExportService) Export(ctx context.Context, format, icao, facilityName string, w io.Writer) error {
+	ctx, span := tracer.Start(ctx, "AirportExportService.Export", trace.WithAttributes(attribute.String("export.format", format)))
+	defer span.End()
+
+	exporter, err := utils.NewAirportExporter(format, w)
+	if err != nil {
+		s.logger.Errorw("Failed to create airport exporter", "error", err, "format", format)
+		return err
+	}
+
+	count := 0
+	err = s.airportRepo.StreamByICAOOrFacilityName(ctx, icao, facilityName, func(airport *dto.Airport) error {
+		count++
+		return exporter.Write(airport)
+	})
+	if err != nil {
+		s.logger.Errorw("Failed to export airports", "error", err, "format", format, "exported", count)
+		return err
+	}
+
+	if err := exporter.Close(); err != nil {
+		s.logger.Errorw("Failed to finish airport export", "error", err, "format", format)
+		return err
+	}
+	s.logger.Infow("Airports exported", "format", format, "icao", icao, "facilityName", facilityName, "count", count)
+	return nil
+}
@@ -0,0 +1,75 @@
+package service_test
+
+import (
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/mock"
+	. "aviation-service/internal/service"
+	"aviation-service/pkg/logger"
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestAirportExportService_Export(t *testing.T) {
+	tests := []struct {
+		name           string
+		format         string
+		airports       []dto.Airport
+		streamErr      error
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			name:           "Success export",
+			format:         dto.ExportFormatGeoJSON,
+			airports:       []dto.Airport{{ID: 1, ICAO: "KAVL", Status: "PENDING"}, {ID: 2, ICAO: "KLAX", Status: "PENDING"}},
+			expectedOutput: `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":null,"properties":{"id":1,"icao_ident":"KAVL","status":"PENDING"}},{"type":"Feature","geometry":null,"properties":{"id":2,"icao_ident":"KLAX","status":"PENDING"}}]}` + "\n",
+		},
+		{
+			name:        "Error unsupported format",
+			format:      "xml",
+			expectedErr: fmt.Errorf(`Unsupported export format "xml"`),
+		},
+		{
+			name:        "Error DB",
+			format:      dto.ExportFormatCSV,
+			streamErr:   fmt.Errorf("DB error"),
+			expectedErr: fmt.Errorf("DB error"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &IAirportRepositoryMock{
+				StreamByICAOOrFacilityNameFunc: func(ctx context.Context, icao, facilityName string, fn func(airport *dto.Airport) error) error {
+					if icao != "KAVL" || facilityName != "asheville" {
+						t.Errorf("Expected filters KAVL and asheville, got %q and %q", icao, facilityName)
+					}
+					if tt.streamErr != nil {
+						return tt.streamErr
+					}
+					for i := range tt.airports {
+						if err := fn(&tt.airports[i]); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			}
+			s := NewAirportExportService(log, repo)
+
+			var buf bytes.Buffer
+			err := s.Export(context.Background(), tt.format, "KAVL", "asheville", &buf)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if buf.String() != tt.expectedOutput {
+				t.Errorf("Expected output %q, got %q", tt.expectedOutput, buf.String())
+			}
+		})
+	}
+}
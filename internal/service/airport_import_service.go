@@ -0,0 +1,98 @@
+package service
+
+import (
+	"aviation-service/internal/dto"
+	"aviation-service/internal/repository"
+	"aviation-service/internal/utils"
+	"context"
+	"io"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type AirportImportValidator interface {
+	IsComplete(req *dto.Airport) bool
+	Validate(req *dto.Airport) error
+}
+
+type AirportImportService struct {
+	logger         *zap.SugaredLogger
+	airportRepo    repository.IAirportRepository
+	airportService IAirportService
+	validator      AirportImportValidator
+}
+
+func NewAirportImportService(logger *zap.SugaredLogger, airportRepo repository.IAirportRepository, airportService IAirportService, validator AirportImportValidator) *AirportImportService {
+	return &AirportImportService{
+		logger:         logger,
+		airportRepo:    airportRepo,
+		airportService: airportService,
+		validator:      validator,
+	}
+}
+
+// Import upserts every valid row of the file by ICAO, rows that fail validation or the write are reported and do not stop the import
+func (s *AirportImportService) Import(ctx context.Context, format string, r io.Reader) (*dto.ImportResult, error) {
+	ctx, span := tracer.Start(ctx, "AirportImportService.Import", trace.WithAttributes(attribute.String("import.format", format)))
+	defer span.End()
+
+	format, rows, err := utils.ParseAirportImport(format, r)
+	if err != nil {
+		s.logger.Errorw("Failed to parse import file", "error", err, "format", format)
+		return nil, err
+	}
+
+	result := &dto.ImportResult{Format: format, Total: len(rows)}
+	for _, row := range rows {
+		if err := ctx.Err(); err != nil {
+			s.logger.Errorw("Import cancelled", "error", err, "row", row.Row)
+			s.invalidateCache(ctx, result)
+			return nil, err
+		}
+		if row.Skip {
+			result.Skipped++
+			continue
+		}
+
+		airport := row.Airport
+		if err := s.validator.Validate(&airport); err != nil {
+			s.addRowError(result, row.Row, airport.ICAO, err)
+			continue
+		}
+		if s.validator.IsComplete(&airport) {
+			airport.Status = "DONE"
+		} else {
+			airport.Status = "PENDING"
+		}
+
+		created, err := s.airportRepo.UpsertByICAO(ctx, &airport)
+		if err != nil {
+			s.addRowError(result, row.Row, airport.ICAO, err)
+			continue
+		}
+		if created {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+
+	s.invalidateCache(ctx, result)
+	s.logger.Infow("Airports imported", "format", result.Format, "total", result.Total, "created", result.Created,
+		"updated", result.Updated, "skipped", result.Skipped, "failed", result.Failed)
+	return result, nil
+}
+
+func (s *AirportImportService) addRowError(result *dto.ImportResult, row int, icao string, err error) {
+	s.logger.Infow("Failed to import airport row", "error", err, "row", row, "icao", icao)
+	result.Failed++
+	result.Errors = append(result.Errors, dto.ImportRowError{Row: row, ICAO: icao, Error: err.Error()})
+}
+
+func (s *AirportImportService) invalidateCache(ctx context.Context, result *dto.ImportResult) {
+	if result.Created+result.Updated > 0 {
+		s.airportService.InvalidateCache(context.WithoutCancel(ctx))
+	}
+}
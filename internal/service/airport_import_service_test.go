@@ -0,0 +1,129 @@
+package service_test
+
+import (
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/mock"
+	. "aviation-service/internal/service"
+	"aviation-service/internal/utils"
+	"aviation-service/pkg/logger"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAirportImportService_Import(t *testing.T) {
+	completeRow := `{"icao_ident": "KAVL", "type": "AIRPORT", "facility_name": "ASHEVILLE RGNL", "faa_ident": "AVL", "region": "ASO",
+		"state_full": "NORTH CAROLINA", "county": "BUNCOMBE", "city": "ASHEVILLE", "ownership": "PU", "use": "PU", "manager": "LEW BLEIWEIS",
+		"manager_phone": "828-684-2226", "latitude": "35-26-08.4000N", "longitude": "082-32-31.9000W"}`
+
+	tests := []struct {
+		name             string
+		format           string
+		file             string
+		upsertErr        map[string]error
+		existing         map[string]bool
+		expectedResult   *dto.ImportResult
+		expectedStatus   map[string]string
+		expectInvalidate bool
+		expectedErr      error
+	}{
+		{
+			name:   "Success import with row errors",
+			format: dto.ImportFormatJSON,
+			file: `[` + completeRow + `,
+				{"icao_ident": "KLAX"},
+				{"icao_ident": "KJFK", "ownership": "XX"},
+				{"city": "NOWHERE"},
+				{"icao_ident": "KSFO"}]`,
+			existing:  map[string]bool{"KLAX": true},
+			upsertErr: map[string]error{"KSFO": fmt.Errorf("duplicate key value violates unique constraint")},
+			expectedResult: &dto.ImportResult{
+				Format:  dto.ImportFormatJSON,
+				Total:   5,
+				Created: 1,
+				Updated: 1,
+				Failed:  3,
+				Errors: []dto.ImportRowError{
+					{Row: 3, ICAO: "KJFK", Error: "Ownership must be PU or PR"},
+					{Row: 4, Error: "ICAO is required"},
+					{Row: 5, ICAO: "KSFO", Error: "duplicate key value violates unique constraint"},
+				},
+			},
+			expectedStatus:   map[string]string{"KAVL": "DONE", "KLAX": "PENDING"},
+			expectInvalidate: true,
+		},
+		{
+			name:   "Success NASR rows without ICAO skipped",
+			format: dto.ImportFormatCSV,
+			file:   "SITE_TYPE_CODE,ARPT_ID,ARPT_NAME,ICAO_ID\nA,AVL,ASHEVILLE RGNL,KAVL\nH,1NC,MISSION HOSPITAL,\n",
+			expectedResult: &dto.ImportResult{
+				Format:  dto.ImportFormatNASR,
+				Total:   2,
+				Created: 1,
+				Skipped: 1,
+			},
+			expectedStatus:   map[string]string{"KAVL": "PENDING"},
+			expectInvalidate: true,
+		},
+		{
+			name:   "Success nothing written",
+			format: dto.ImportFormatCSV,
+			file:   "icao,ownership\nKAVL,XX\n",
+			expectedResult: &dto.ImportResult{
+				Format: dto.ImportFormatCSV,
+				Total:  1,
+				Failed: 1,
+				Errors: []dto.ImportRowError{{Row: 2, ICAO: "KAVL", Error: "Ownership must be PU or PR"}},
+			},
+			expectedStatus: map[string]string{},
+		},
+		{
+			name:        "Error invalid file",
+			format:      dto.ImportFormatJSON,
+			file:        `A`,
+			expectedErr: fmt.Errorf("Invalid JSON import file: invalid character 'A' looking for beginning of value"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := map[string]string{}
+			repo := &IAirportRepositoryMock{
+				UpsertByICAOFunc: func(ctx context.Context, airport *dto.Airport) (bool, error) {
+					if err := tt.upsertErr[airport.ICAO]; err != nil {
+						return false, err
+					}
+					status[airport.ICAO] = airport.Status
+					return !tt.existing[airport.ICAO], nil
+				},
+			}
+			airportService := &IAirportServiceMock{
+				InvalidateCacheFunc: func(ctx context.Context) error {
+					return nil
+				},
+			}
+			s := NewAirportImportService(log, repo, airportService, utils.NewAirportValidator())
+
+			got, err := s.Import(context.Background(), tt.format, strings.NewReader(tt.file))
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedResult) {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+
+			if tt.expectedStatus != nil && !reflect.DeepEqual(status, tt.expectedStatus) {
+				t.Errorf("Expected status %v, got %v", tt.expectedStatus, status)
+			}
+
+			if invalidated := len(airportService.InvalidateCacheCalls()) > 0; invalidated != tt.expectInvalidate {
+				t.Errorf("Expected cache invalidated %v, got %v", tt.expectInvalidate, invalidated)
+			}
+		})
+	}
+}
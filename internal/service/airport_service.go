@@ -2,17 +2,21 @@ package service
 
 import (
 	"aviation-service/config"
+	"aviation-service/internal/client"
 	"aviation-service/internal/dto"
 	"aviation-service/internal/repository"
 	"aviation-service/internal/utils"
 	"aviation-service/pkg/redis"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -20,15 +24,26 @@ import (
 type IAirportService interface {
 	GetAllAirport(ctx context.Context, limit, offset int) ([]dto.Airport, error)
 	GetAirport(ctx context.Context, id int) (*dto.Airport, error)
+	GetAirportHistory(ctx context.Context, id int, limit, offset int) ([]dto.AirportHistory, error)
 	CreateAirport(ctx context.Context, request *dto.Airport) (*dto.Airport, error)
 	SearchAirport(ctx context.Context, icao string, name string, limit, offset int) ([]dto.Airport, error)
+	GetNearbyAirport(ctx context.Context, lat, lon, radiusNm float64, limit, offset int) ([]dto.NearbyAirport, error)
 	UpdateAirport(ctx context.Context, request *dto.Airport) (*dto.Airport, error)
 	DeleteAirport(ctx context.Context, id int) error
-	FetchAirportData(icaos string) (*dto.AirportDataResponse, error)
+	FetchAirportData(ctx context.Context, icaos string) (*dto.AirportDataResponse, error)
+	InvalidateCache(ctx context.Context) error
 }
 
+const airportCacheGenerationKey = "airport:generation"
+
+// The warmed searches use the default page size of the search endpoint, so they are the keys searches read
+const (
+	airportCacheWarmPageSize  = 10
+	airportCacheWarmBatchSize = 500
+)
+
 type Client interface {
-	Get(url string) (*http.Response, error)
+	Get(ctx context.Context, url string) (*http.Response, error)
 }
 
 type AirportService struct {
@@ -50,6 +65,9 @@ func NewAirportService(logger *zap.SugaredLogger, airportRepo repository.IAirpor
 }
 
 func (s *AirportService) GetAllAirport(ctx context.Context, limit, offset int) ([]dto.Airport, error) {
+	ctx, span := tracer.Start(ctx, "AirportService.GetAllAirport")
+	defer span.End()
+
 	airports, err := s.airportRepo.GetAll(ctx, limit, offset)
 	if err != nil {
 		s.logger.Errorw("Failed to get all airports", "error", err)
@@ -59,6 +77,9 @@ func (s *AirportService) GetAllAirport(ctx context.Context, limit, offset int) (
 }
 
 func (s *AirportService) GetAirport(ctx context.Context, id int) (*dto.Airport, error) {
+	ctx, span := tracer.Start(ctx, "AirportService.GetAirport")
+	defer span.End()
+
 	airport, err := s.airportRepo.GetById(ctx, id)
 	if err != nil {
 		s.logger.Errorw("Failed to get airport", "error", err)
@@ -67,15 +88,40 @@ func (s *AirportService) GetAirport(ctx context.Context, id int) (*dto.Airport,
 	return airport, nil
 }
 
+func (s *AirportService) GetAirportHistory(ctx context.Context, id int, limit, offset int) ([]dto.AirportHistory, error) {
+	ctx, span := tracer.Start(ctx, "AirportService.GetAirportHistory")
+	defer span.End()
+
+	history, err := s.airportRepo.GetHistory(ctx, id, limit, offset)
+	if err != nil {
+		s.logger.Errorw("Failed to get airport history", "error", err, "id", id)
+		return nil, err
+	}
+	return history, nil
+}
+
 func (s *AirportService) SearchAirport(ctx context.Context, icao string, facilityName string, limit, offset int) ([]dto.Airport, error) {
-	cacheKey := fmt.Sprintf("airport:%s:%s:%s:%s", icao, facilityName, limit, offset)
+	ctx, span := tracer.Start(ctx, "AirportService.SearchAirport", trace.WithAttributes(attribute.String("airport.icao", icao), attribute.String("airport.facility_name", facilityName)))
+	defer span.End()
+
+	// Every write bumps the generation, so keys from an older generation are never read again and expire on their own
+	generation, genErr := utils.GetGeneration(s.redisClient, ctx, airportCacheGenerationKey)
+	useCache := genErr == nil
+	if !useCache {
+		s.logger.Infow("Failed to get airport cache generation, skipping cache", "error", genErr)
+	}
+	cacheKey := fmt.Sprintf("airport:v%d:%s:%s:%d:%d", generation, icao, facilityName, limit, offset)
+
 	var airports []dto.Airport
-	s.logger.Infow("Airport cache hit", "icao", icao, "facilityName", facilityName)
-	cacheErr := utils.GetStruct(s.redisClient, ctx, cacheKey, &airports)
-	if cacheErr == nil {
-		return airports, nil
+	if useCache {
+		cacheErr := utils.GetStruct(s.redisClient, ctx, cacheKey, &airports)
+		cacheLookup(ctx, "airport", cacheErr == nil)
+		if cacheErr == nil {
+			s.logger.Infow("Airport cache hit", "icao", icao, "facilityName", facilityName)
+			return airports, nil
+		}
+		s.logger.Infow("No airport data from cache, fetching from repo", "error", cacheErr)
 	}
-	s.logger.Infow("No airport data from cache, fetching from repo", "error", cacheErr)
 
 	s.logger.Infow("Get airports data from repo", "icao", icao, "facilityName", facilityName)
 	airports, err := s.airportRepo.GetByICAOOrFacilityName(ctx, icao, facilityName, limit, offset)
@@ -85,14 +131,20 @@ func (s *AirportService) SearchAirport(ctx context.Context, icao string, facilit
 	}
 
 	if len(airports) > 0 || icao == "" {
-		if err := utils.SetStruct(s.redisClient, ctx, cacheKey, airports, 24*time.Hour); err != nil {
-			s.logger.Infow("Error set cache", "error", err)
+		if useCache {
+			if err := utils.SetStruct(s.redisClient, ctx, cacheKey, airports, 24*time.Hour); err != nil {
+				s.logger.Infow("Error set cache", "error", err)
+			}
 		}
 		return airports, nil
 	}
-	s.logger.Infow("No airport data from repo, fetching from API", "error", cacheErr)
+	s.logger.Infow("No airport data from repo, fetching from API", "icao", icao)
 
-	airportResponse, err := s.FetchAirportData(icao)
+	airportResponse, err := s.FetchAirportData(ctx, icao)
+	if errors.Is(err, client.ErrCircuitOpen) {
+		s.logger.Infow("AviationAPI circuit is open, returning database result only", "icao", icao)
+		return nil, nil
+	}
 	if err != nil {
 		s.logger.Errorw("Failed to get airports from API", "error", err, "icao", icao)
 		return nil, err
@@ -104,57 +156,138 @@ func (s *AirportService) SearchAirport(ctx context.Context, icao string, facilit
 	}
 
 	airports[0].Status = "DONE"
-	inserted, err := s.airportRepo.Insert(ctx, &airports[0])
+	inserted, err := s.airportRepo.Insert(utils.WithActor(ctx, dto.ActorAviationAPI), &airports[0])
 	if err != nil {
 		s.logger.Errorw("Failed to insert airport from API", "error", err, "icao", icao)
 		return nil, err
 	}
 	airports = []dto.Airport{*inserted}
 
-	if err := utils.SetStruct(s.redisClient, ctx, cacheKey, airports, 24*time.Hour); err != nil {
-		s.logger.Infow("Error set cache", "error", err)
+	// The new airport may match other cached searches, the next search repopulates the cache under the new generation
+	s.InvalidateCache(ctx)
+	return airports, nil
+}
+
+func (s *AirportService) GetNearbyAirport(ctx context.Context, lat, lon, radiusNm float64, limit, offset int) ([]dto.NearbyAirport, error) {
+	ctx, span := tracer.Start(ctx, "AirportService.GetNearbyAirport")
+	defer span.End()
+
+	airports, err := s.airportRepo.GetNearby(ctx, lat, lon, radiusNm, limit, offset)
+	if err != nil {
+		s.logger.Errorw("Failed to get nearby airports", "error", err, "lat", lat, "lon", lon, "radiusNm", radiusNm)
+		return nil, err
 	}
 	return airports, nil
 }
 
 func (s *AirportService) CreateAirport(ctx context.Context, request *dto.Airport) (*dto.Airport, error) {
+	ctx, span := tracer.Start(ctx, "AirportService.CreateAirport")
+	defer span.End()
+
 	airport, err := s.airportRepo.Insert(ctx, request)
 	if err != nil {
 		s.logger.Errorw("Failed to create airport", "error", err)
 		return nil, err
 	}
+	s.InvalidateCache(ctx)
 	return airport, nil
 }
 
 func (s *AirportService) UpdateAirport(ctx context.Context, request *dto.Airport) (*dto.Airport, error) {
+	ctx, span := tracer.Start(ctx, "AirportService.UpdateAirport")
+	defer span.End()
+
 	airport, err := s.airportRepo.UpdateById(ctx, request)
 	if err != nil {
 		s.logger.Errorw("Failed to update airport", "error", err)
 		return nil, err
 	}
+	s.InvalidateCache(ctx)
 	return airport, nil
 }
 
 func (s *AirportService) DeleteAirport(ctx context.Context, id int) error {
+	ctx, span := tracer.Start(ctx, "AirportService.DeleteAirport")
+	defer span.End()
+
 	err := s.airportRepo.Delete(ctx, id)
 	if err != nil {
 		s.logger.Errorw("Failed to delete airport", "error", err)
 		return err
 	}
+	s.InvalidateCache(ctx)
+	return nil
+}
+
+// InvalidateCache evicts every cached airport search result by moving to a new cache generation
+func (s *AirportService) InvalidateCache(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "AirportService.InvalidateCache")
+	defer span.End()
+
+	if err := utils.BumpGeneration(s.redisClient, ctx, airportCacheGenerationKey); err != nil {
+		s.logger.Errorw("Failed to invalidate airport cache", "error", err)
+		return err
+	}
 	return nil
 }
 
-func (s *AirportService) FetchAirportData(icaos string) (*dto.AirportDataResponse, error) {
+// WarmCache fills the search cache of the current generation with the first page of the unfiltered search and of every
+// stored ICAO, it returns the number of searches warmed. A failed search is logged and skipped
+func (s *AirportService) WarmCache(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "AirportService.WarmCache")
+	defer span.End()
+
+	warmed := 0
+	if _, err := s.SearchAirport(ctx, "", "", airportCacheWarmPageSize, 0); err != nil {
+		s.logger.Errorw("Failed to warm airport cache", "error", err)
+		return warmed, err
+	}
+	warmed++
+
+	for offset := 0; ; offset += airportCacheWarmBatchSize {
+		airports, err := s.airportRepo.GetAll(ctx, airportCacheWarmBatchSize, offset)
+		if err != nil {
+			s.logger.Errorw("Failed to get airports to warm cache", "error", err, "offset", offset)
+			return warmed, err
+		}
+		for _, airport := range airports {
+			if err := ctx.Err(); err != nil {
+				return warmed, err
+			}
+			if _, err := s.SearchAirport(ctx, airport.ICAO, "", airportCacheWarmPageSize, 0); err != nil {
+				s.logger.Infow("Failed to warm airport search", "error", err, "icao", airport.ICAO)
+				continue
+			}
+			warmed++
+		}
+		if len(airports) < airportCacheWarmBatchSize {
+			break
+		}
+	}
+
+	s.logger.Infow("Airport cache warmed", "searches", warmed)
+	return warmed, nil
+}
+
+func (s *AirportService) FetchAirportData(ctx context.Context, icaos string) (*dto.AirportDataResponse, error) {
+	ctx, span := tracer.Start(ctx, "AirportService.FetchAirportData", trace.WithAttributes(attribute.String("airport.icaos", icaos)))
+	defer span.End()
+
 	s.logger.Infow("Fetching airports data", "icaos", icaos)
 	params := url.Values{}
 	params.Add("apt", icaos)
-	resp, err := s.client.Get(s.cfg.AIRPORT_API_URL + "/airports?" + params.Encode())
+	resp, err := s.client.Get(ctx, s.cfg.AIRPORT_API_URL+"/airports?"+params.Encode())
 	if err != nil {
 		s.logger.Errorw("Error fetching airports data", "error", err)
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		s.logger.Errorw("Unexpected status code from AviationAPI", "statusCode", resp.StatusCode)
+		return nil, fmt.Errorf("Unexpected status code %d from AviationAPI", resp.StatusCode)
+	}
+
 	var airports dto.AirportDataResponse
 	jsonErr := json.NewDecoder(resp.Body).Decode(&airports)
 	if jsonErr != nil {
@@ -2,6 +2,7 @@ package service_test
 
 import (
 	"aviation-service/config"
+	"aviation-service/internal/client"
 	"aviation-service/internal/dto"
 	. "aviation-service/internal/mock"
 	"aviation-service/internal/repository"
@@ -50,7 +51,7 @@ func TestAirportService_GetAllAirport(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := config.Config{AIRPORT_API_URL: "http://123"}
-			client := http.DefaultClient
+			client := &mockHTTPClient{}
 			redisClient := &MockRedis{Store: make(map[string]string)}
 			s := NewAirportService(log, tt.repo, cfg, client, redisClient)
 			got, err := s.GetAllAirport(context.Background(), 20, 0)
@@ -99,7 +100,7 @@ func TestAirportService_GetAirport(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := config.Config{AIRPORT_API_URL: "http://123"}
-			client := http.DefaultClient
+			client := &mockHTTPClient{}
 			redisClient := &MockRedis{Store: make(map[string]string)}
 			s := NewAirportService(log, tt.repo, cfg, client, redisClient)
 			got, err := s.GetAirport(context.Background(), 1)
@@ -115,6 +116,54 @@ func TestAirportService_GetAirport(t *testing.T) {
 	}
 }
 
+func TestAirportService_GetAirportHistory(t *testing.T) {
+	history := []dto.AirportHistory{{ID: 2, AirportID: 1, ICAO: "KLAX", Action: dto.AirportHistoryActionUpdate, Actor: "sync_run:7"}}
+	tests := []struct {
+		name           string
+		repo           repository.IAirportRepository
+		expectedResult []dto.AirportHistory
+		expectedErr    error
+	}{
+		{
+			name: "Success get airport history",
+			repo: &IAirportRepositoryMock{
+				GetHistoryFunc: func(ctx context.Context, airportID, limit, offset int) ([]dto.AirportHistory, error) {
+					return history, nil
+				},
+			},
+			expectedResult: history,
+		},
+		{
+			name: "Error get airport history",
+			repo: &IAirportRepositoryMock{
+				GetHistoryFunc: func(ctx context.Context, airportID, limit, offset int) ([]dto.AirportHistory, error) {
+					return nil, fmt.Errorf("DB error")
+				},
+			},
+			expectedErr: fmt.Errorf("DB error"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{AIRPORT_API_URL: "http://123"}
+			redisClient := &MockRedis{Store: make(map[string]string)}
+			s := NewAirportService(log, tt.repo, cfg, &mockHTTPClient{}, redisClient)
+			got, err := s.GetAirportHistory(context.Background(), 1, 10, 0)
+
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedResult) {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+		})
+	}
+}
+
 func TestAirportService_SearchAirport(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -127,10 +176,23 @@ func TestAirportService_SearchAirport(t *testing.T) {
 		{
 			name: "Success search airport from cache",
 			redisClient: &MockRedis{Store: map[string]string{
-				"airport:KLAX:Lorem Ipsum": `[{"id": 1, "icao_ident": "KLAX"}]`,
+				"airport:v0:KLAX:Lorem Ipsum:20:0": `[{"id": 1, "icao_ident": "KLAX"}]`,
 			}},
 			expectedResult: []dto.Airport{{ID: 1, ICAO: "KLAX"}},
 		},
+		{
+			name: "Success ignore cache from previous generation",
+			repo: &IAirportRepositoryMock{
+				GetByICAOOrFacilityNameFunc: func(ctx context.Context, icao, facilityName string, limit, offset int) ([]dto.Airport, error) {
+					return []dto.Airport{{ID: 2, ICAO: "KLAX"}}, nil
+				},
+			},
+			redisClient: &MockRedis{Store: map[string]string{
+				"airport:generation":              "1",
+				"airport:v0:KLAX:Lorem Ipsum:20:0": `[{"id": 1, "icao_ident": "KLAX"}]`,
+			}},
+			expectedResult: []dto.Airport{{ID: 2, ICAO: "KLAX"}},
+		},
 		{
 			name: "Success search airport from repo",
 			repo: &IAirportRepositoryMock{
@@ -187,6 +249,34 @@ func TestAirportService_SearchAirport(t *testing.T) {
 			expectedResult: ([]dto.Airport)(nil),
 			expectedErr:    fmt.Errorf("Error fetching airports data"),
 		},
+		{
+			name: "Success circuit open returns database result",
+			repo: &IAirportRepositoryMock{
+				GetByICAOOrFacilityNameFunc: func(ctx context.Context, icao, facilityName string, limit, offset int) ([]dto.Airport, error) {
+					return []dto.Airport{}, nil
+				},
+			},
+			httpClient: &mockHTTPClient{
+				err: client.ErrCircuitOpen,
+			},
+			redisClient: &MockRedis{Store: make(map[string]string)},
+			expectedResult: ([]dto.Airport)(nil),
+		},
+		{
+			name: "Error unexpected status code",
+			repo: &IAirportRepositoryMock{
+				GetByICAOOrFacilityNameFunc: func(ctx context.Context, icao, facilityName string, limit, offset int) ([]dto.Airport, error) {
+					return []dto.Airport{}, nil
+				},
+			},
+			httpClient: &mockHTTPClient{
+				response:   `<html>Service Unavailable</html>`,
+				statusCode: 503,
+			},
+			redisClient: &MockRedis{Store: make(map[string]string)},
+			expectedResult: ([]dto.Airport)(nil),
+			expectedErr:    fmt.Errorf("Unexpected status code 503 from AviationAPI"),
+		},
 		{
 			name: "Error decoding body",
 			repo: &IAirportRepositoryMock{
@@ -276,6 +366,55 @@ func TestAirportService_SearchAirport(t *testing.T) {
 	}
 }
 
+func TestAirportService_GetNearbyAirport(t *testing.T) {
+	tests := []struct {
+		name           string
+		repo           *IAirportRepositoryMock
+		expectedResult interface{}
+		expectedErr    error
+	}{
+		{
+			name: "Success get nearby airports",
+			repo: &IAirportRepositoryMock{
+				GetNearbyFunc: func(ctx context.Context, lat, lon, radiusNm float64, limit, offset int) ([]dto.NearbyAirport, error) {
+					return []dto.NearbyAirport{{Airport: dto.Airport{ID: 1, ICAO: "KADT"}, DistanceNm: 1.5}}, nil
+				},
+			},
+			expectedResult: []dto.NearbyAirport{{Airport: dto.Airport{ID: 1, ICAO: "KADT"}, DistanceNm: 1.5}},
+		},
+		{
+			name: "Error get nearby airports",
+			repo: &IAirportRepositoryMock{
+				GetNearbyFunc: func(ctx context.Context, lat, lon, radiusNm float64, limit, offset int) ([]dto.NearbyAirport, error) {
+					return nil, fmt.Errorf("Failed to get nearby airports")
+				},
+			},
+			expectedResult: ([]dto.NearbyAirport)(nil),
+			expectedErr:    fmt.Errorf("Failed to get nearby airports"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{AIRPORT_API_URL: "http://123"}
+			client := &mockHTTPClient{}
+			redisClient := &MockRedis{Store: make(map[string]string)}
+			s := NewAirportService(log, tt.repo, cfg, client, redisClient)
+			got, err := s.GetNearbyAirport(context.Background(), 33.1, -88.2, 50, 20, 0)
+
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedResult) {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+		})
+	}
+}
+
 func TestAirportService_CreateAirport(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -309,7 +448,7 @@ func TestAirportService_CreateAirport(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := config.Config{AIRPORT_API_URL: "http://123"}
-			client := http.DefaultClient
+			client := &mockHTTPClient{}
 			redisClient := &MockRedis{Store: make(map[string]string)}
 			s := NewAirportService(log, tt.repo, cfg, client, redisClient)
 			got, err := s.CreateAirport(context.Background(), &dto.Airport{})
@@ -318,6 +457,10 @@ func TestAirportService_CreateAirport(t *testing.T) {
 				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
 			}
 
+			if generation := redisClient.Store["airport:generation"]; err == nil && generation != "1" {
+				t.Errorf("Expected cache generation to be bumped, got %q", generation)
+			}
+
 			if !reflect.DeepEqual(got, tt.expectedResult) {
 				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
 			}
@@ -358,7 +501,7 @@ func TestAirportService_UpdateAirport(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := config.Config{AIRPORT_API_URL: "http://123"}
-			client := http.DefaultClient
+			client := &mockHTTPClient{}
 			redisClient := &MockRedis{Store: make(map[string]string)}
 			s := NewAirportService(log, tt.repo, cfg, client, redisClient)
 			got, err := s.UpdateAirport(context.Background(), &dto.Airport{})
@@ -367,6 +510,10 @@ func TestAirportService_UpdateAirport(t *testing.T) {
 				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
 			}
 
+			if generation := redisClient.Store["airport:generation"]; err == nil && generation != "1" {
+				t.Errorf("Expected cache generation to be bumped, got %q", generation)
+			}
+
 			if !reflect.DeepEqual(got, tt.expectedResult) {
 				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
 			}
@@ -406,7 +553,7 @@ func TestAirportService_DeleteAirport(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := config.Config{AIRPORT_API_URL: "http://123"}
-			client := http.DefaultClient
+			client := &mockHTTPClient{}
 			redisClient := &MockRedis{Store: make(map[string]string)}
 			s := NewAirportService(log, tt.repo, cfg, client, redisClient)
 			err := s.DeleteAirport(context.Background(), 1)
@@ -414,28 +561,135 @@ func TestAirportService_DeleteAirport(t *testing.T) {
 			if err != nil && err.Error() != tt.expectedErr.Error() {
 				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
 			}
+
+			if generation := redisClient.Store["airport:generation"]; err == nil && generation != "1" {
+				t.Errorf("Expected cache generation to be bumped, got %q", generation)
+			}
+		})
+	}
+}
+
+func TestAirportService_InvalidateCache(t *testing.T) {
+	tests := []struct {
+		name               string
+		redisClient        r.RedisClient
+		expectedGeneration string
+		expectedErr        error
+	}{
+		{
+			name:               "Success invalidate cache",
+			redisClient:        &MockRedis{Store: map[string]string{"airport:generation": "4"}},
+			expectedGeneration: "5",
+		},
+		{
+			name:        "Error invalidate cache",
+			redisClient: &MockRedisSetError{},
+			expectedErr: fmt.Errorf("Cache incr failed"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{AIRPORT_API_URL: "http://123"}
+			s := NewAirportService(log, &IAirportRepositoryMock{}, cfg, &mockHTTPClient{}, tt.redisClient)
+			err := s.InvalidateCache(context.Background())
+
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if tt.expectedGeneration != "" {
+				got, _ := tt.redisClient.Get(context.Background(), "airport:generation").Result()
+				if got != tt.expectedGeneration {
+					t.Errorf("Expected generation %v, got %v", tt.expectedGeneration, got)
+				}
+			}
+		})
+	}
+}
+
+func TestAirportService_WarmCache(t *testing.T) {
+	tests := []struct {
+		name           string
+		getAllErr      error
+		expectedWarmed int
+		expectedKeys   []string
+		expectedErr    error
+	}{
+		{
+			name:           "Success warm cache",
+			expectedWarmed: 3,
+			expectedKeys:   []string{"airport:v2:::10:0", "airport:v2:KAVL::10:0", "airport:v2:KLAX::10:0"},
+		},
+		{
+			name:           "Error get airports",
+			getAllErr:      fmt.Errorf("DB error"),
+			expectedWarmed: 1,
+			expectedKeys:   []string{"airport:v2:::10:0"},
+			expectedErr:    fmt.Errorf("DB error"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisClient := &MockRedis{Store: map[string]string{"airport:generation": "2"}}
+			repo := &IAirportRepositoryMock{
+				GetAllFunc: func(ctx context.Context, limit, offset int) ([]dto.Airport, error) {
+					return []dto.Airport{{ID: 1, ICAO: "KAVL"}, {ID: 2, ICAO: "KLAX"}}, tt.getAllErr
+				},
+				GetByICAOOrFacilityNameFunc: func(ctx context.Context, icao, facilityName string, limit, offset int) ([]dto.Airport, error) {
+					return []dto.Airport{{ICAO: icao}}, nil
+				},
+			}
+			s := NewAirportService(log, repo, config.Config{AIRPORT_API_URL: "http://123"}, &mockHTTPClient{}, redisClient)
+
+			warmed, err := s.WarmCache(context.Background())
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if warmed != tt.expectedWarmed {
+				t.Errorf("Expected %d searches warmed, got %d", tt.expectedWarmed, warmed)
+			}
+			for _, key := range tt.expectedKeys {
+				if _, ok := redisClient.Store[key]; !ok {
+					t.Errorf("Expected cache key %s, got %v", key, redisClient.Store)
+				}
+			}
+			if len(redisClient.Store) != len(tt.expectedKeys)+1 {
+				t.Errorf("Expected %d cache keys, got %v", len(tt.expectedKeys), redisClient.Store)
+			}
 		})
 	}
 }
 
 type mockHTTPClient struct {
-	response string
-	body     io.ReadCloser
-	err      error
+	response   string
+	statusCode int
+	body       io.ReadCloser
+	err        error
 }
 
-func (m *mockHTTPClient) Get(url string) (*http.Response, error) {
+func (m *mockHTTPClient) Get(ctx context.Context, url string) (*http.Response, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	statusCode := m.statusCode
+	if statusCode == 0 {
+		statusCode = 200
+	}
 	if m.body != nil {
 		return &http.Response{
-			StatusCode: 200,
+			StatusCode: statusCode,
 			Body:       m.body,
 		}, nil
 	}
 	return &http.Response{
-		StatusCode: 200,
+		StatusCode: statusCode,
 		Body:       io.NopCloser(bytes.NewBufferString(m.response)),
 	}, nil
 }
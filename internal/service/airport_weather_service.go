@@ -2,70 +2,177 @@ package service
 
 import (
 	"aviation-service/internal/dto"
+	"aviation-service/internal/repository"
+	"aviation-service/internal/utils"
 	"context"
+	"fmt"
+	"slices"
 	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// categoryScanLimit bounds the airports a flight category search looks at, each one costs a weather and a METAR lookup
+const categoryScanLimit = 500
+
+// CategoryScanLimitError is returned when a flight category search read categoryScanLimit airports without filling its page
+type CategoryScanLimitError struct {
+	Scanned int
+}
+
+func (e *CategoryScanLimitError) Error() string {
+	return fmt.Sprintf("Flight category search stopped after %d airports", e.Scanned)
+}
+
 type AirportWeatherService struct {
-	logger         *zap.SugaredLogger
-	airportService IAirportService
-	weatherService IWeatherService
+	logger                 *zap.SugaredLogger
+	airportRepo            repository.IAirportRepository
+	airportService         IAirportService
+	weatherService         IWeatherService
+	aviationWeatherService IAviationWeatherService
 }
 
-func NewAirportWeatherService(logger *zap.SugaredLogger, airportService IAirportService, weatherService IWeatherService) *AirportWeatherService {
+func NewAirportWeatherService(logger *zap.SugaredLogger, airportRepo repository.IAirportRepository, airportService IAirportService, weatherService IWeatherService, aviationWeatherService IAviationWeatherService) *AirportWeatherService {
 	return &AirportWeatherService{
-		logger:         logger,
-		airportService: airportService,
-		weatherService: weatherService,
+		logger:                 logger,
+		airportRepo:            airportRepo,
+		airportService:         airportService,
+		weatherService:         weatherService,
+		aviationWeatherService: aviationWeatherService,
+	}
+}
+
+// SearchAirportWeather returns the searched airports with weather and flight category. When categories is not empty
+// only airports whose current flight category is one of them are kept, limit and offset then count those airports
+func (s *AirportWeatherService) SearchAirportWeather(ctx context.Context, icao, facilityName string, categories []string, limit, offset int) ([]dto.AirportWeather, error) {
+	ctx, span := tracer.Start(ctx, "AirportWeatherService.SearchAirportWeather", trace.WithAttributes(attribute.String("airport.icao", icao), attribute.String("airport.facility_name", facilityName)))
+	defer span.End()
+
+	if len(categories) == 0 {
+		airports, err := s.airportService.SearchAirport(ctx, icao, facilityName, limit, offset)
+		if err != nil {
+			s.logger.Errorw("Failed to search airports", "error", err)
+			return nil, err
+		}
+		return s.loadAirportWeathers(ctx, airports), nil
+	}
+
+	// The flight category is only known once the weather is loaded, so search pages are read until limit airports
+	// matched, the search runs out or categoryScanLimit airports were looked at
+	var airportWeathers []dto.AirportWeather
+	skipped := 0
+	for searchOffset := 0; len(airportWeathers) < limit; searchOffset += limit {
+		if searchOffset >= categoryScanLimit {
+			s.logger.Infow("Flight category search reached the scan limit", "icao", icao, "facilityName", facilityName, "scanned", searchOffset)
+			return nil, &CategoryScanLimitError{Scanned: searchOffset}
+		}
+
+		var airports []dto.Airport
+		var err error
+		if searchOffset == 0 {
+			airports, err = s.airportService.SearchAirport(ctx, icao, facilityName, limit, searchOffset)
+		} else {
+			// Later pages come from the database only, SearchAirport would fetch and insert an ICAO it misses again
+			airports, err = s.airportRepo.GetByICAOOrFacilityName(ctx, icao, facilityName, limit, searchOffset)
+		}
+		if err != nil {
+			s.logger.Errorw("Failed to search airports", "error", err)
+			return nil, err
+		}
+
+		for _, airportWeather := range s.loadAirportWeathers(ctx, airports) {
+			if !slices.Contains(categories, airportWeather.FlightCategory) {
+				continue
+			}
+			if skipped < offset {
+				skipped++
+				continue
+			}
+			if len(airportWeathers) < limit {
+				airportWeathers = append(airportWeathers, airportWeather)
+			}
+		}
+		if len(airports) < limit {
+			break
+		}
 	}
+	return airportWeathers, nil
 }
 
-func (s *AirportWeatherService) SearchAirportWeather(ctx context.Context, icao, facilityName string, limit, offset int) ([]dto.AirportWeather, error) {
-	airports, err := s.airportService.SearchAirport(ctx, icao, facilityName, limit, offset)
-	if err != nil {
-		s.logger.Errorw("Failed to search airports", "error", err)
-		return nil, err
+// loadAirportWeathers looks the weather and flight category of the airports up concurrently, keeping their order
+func (s *AirportWeatherService) loadAirportWeathers(ctx context.Context, airports []dto.Airport) []dto.AirportWeather {
+	if len(airports) == 0 {
+		return nil
 	}
 
 	numWorkers := 10
 	if len(airports) < numWorkers {
 		numWorkers = len(airports)
 	}
-	airportChannel := make(chan dto.Airport, len(airports))
+	indexChannel := make(chan int, len(airports))
 	wg := sync.WaitGroup{}
-	mutex := sync.Mutex{}
-	var airportWeathers []dto.AirportWeather
+	airportWeathers := make([]dto.AirportWeather, len(airports))
 
 	for i := 0; i < numWorkers; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for airport := range airportChannel {
-				airportWeather := dto.AirportWeather{Airport: airport}
-				if airport.City != nil {
-					weather, weatherErr := s.weatherService.GetWeather(ctx, *airport.City)
-					if weatherErr != nil {
-						s.logger.Errorw("Weather not available for airport", "error", weatherErr, "icao", airport.ICAO)
-					} else {
-						airportWeather.Weather = weather
-					}
-				}
-				mutex.Lock()
-				airportWeathers = append(airportWeathers, airportWeather)
-				mutex.Unlock()
+			for index := range indexChannel {
+				airportWeather := dto.AirportWeather{Airport: airports[index], Weather: s.getAirportWeather(ctx, airports[index])}
+				s.setFlightCategory(ctx, &airportWeather)
+				airportWeathers[index] = airportWeather
 			}
 		}()
 	}
 
-	go func() {
-		defer close(airportChannel)
-		for _, airport := range airports {
-			airportChannel <- airport
-		}
-	}()
+	for index := range airports {
+		indexChannel <- index
+	}
+	close(indexChannel)
 
 	wg.Wait()
-	return airportWeathers, nil
+	return airportWeathers
+}
+
+// getAirportWeather looks the weather up by the airport coordinates and falls back to the city name
+func (s *AirportWeatherService) getAirportWeather(ctx context.Context, airport dto.Airport) *dto.Weather {
+	if airport.LatitudeDeg != nil && airport.LongitudeDeg != nil {
+		weather, weatherErr := s.weatherService.GetWeatherByCoordinates(ctx, *airport.LatitudeDeg, *airport.LongitudeDeg)
+		if weatherErr == nil {
+			return weather
+		}
+		s.logger.Errorw("Weather not available for airport coordinates", "error", weatherErr, "icao", airport.ICAO)
+	}
+
+	if airport.City == nil {
+		return nil
+	}
+	weather, weatherErr := s.weatherService.GetWeather(ctx, *airport.City)
+	if weatherErr != nil {
+		s.logger.Errorw("Weather not available for airport", "error", weatherErr, "icao", airport.ICAO)
+		return nil
+	}
+	return weather
+}
+
+// setFlightCategory prefers the airport METAR and falls back to the WeatherAPI visibility
+func (s *AirportWeatherService) setFlightCategory(ctx context.Context, airportWeather *dto.AirportWeather) {
+	if airportWeather.Airport.ICAO != "" {
+		metar, metarErr := s.aviationWeatherService.GetMetar(ctx, airportWeather.Airport.ICAO)
+		if metarErr != nil {
+			s.logger.Errorw("METAR not available for airport", "error", metarErr, "icao", airportWeather.Airport.ICAO)
+		}
+		if category := utils.MetarFlightCategory(metar); category != "" {
+			airportWeather.FlightCategory = category
+			airportWeather.FlightCategorySource = dto.FlightCategorySourceMetar
+			return
+		}
+	}
+
+	if category := utils.WeatherFlightCategory(airportWeather.Weather); category != "" {
+		airportWeather.FlightCategory = category
+		airportWeather.FlightCategorySource = dto.FlightCategorySourceWeatherAPI
+	}
 }
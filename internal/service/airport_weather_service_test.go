@@ -13,10 +13,13 @@ import (
 
 func TestAirportWeatherService_SearchAirportWeather(t *testing.T) {
 	city := "ASHEVILLE"
+	lat, lon := 33.9425, -118.4081
 	tests := []struct {
 		name           string
-		airportService IAirportService
-		weatherService IWeatherService
+		airportService         IAirportService
+		weatherService         IWeatherService
+		aviationWeatherService IAviationWeatherService
+		categories             []string
 		expectedResult []dto.AirportWeather
 		expectedErr    error
 	}{
@@ -33,16 +36,111 @@ func TestAirportWeatherService_SearchAirportWeather(t *testing.T) {
 						LastUpdated: "2025-09-29 02:45",
 						TempC:       17.2,
 						IsDay:       0,
+						VisKm:       16,
 					}, nil
 				},
 			},
+			aviationWeatherService: noMetarService(),
 			expectedResult: []dto.AirportWeather{{
 				Airport: dto.Airport{ID: 1, ICAO: "KLAX", City: &city},
 				Weather: &dto.Weather{
 					LastUpdated: "2025-09-29 02:45",
 					TempC:       17.2,
 					IsDay:       0,
+					VisKm:       16,
 				},
+				FlightCategory:       dto.FlightCategoryVFR,
+				FlightCategorySource: dto.FlightCategorySourceWeatherAPI,
+			}},
+		},
+		{
+			name: "Success with flight category from METAR",
+			airportService: &IAirportServiceMock{
+				SearchAirportFunc: func(ctx context.Context, icao, name string, limit, offset int) ([]dto.Airport, error) {
+					return []dto.Airport{{ID: 1, ICAO: "KLAX", City: &city}}, nil
+				},
+			},
+			weatherService: &IWeatherServiceMock{
+				GetWeatherFunc: func(ctx context.Context, city string) (*dto.Weather, error) {
+					return &dto.Weather{VisKm: 16}, nil
+				},
+			},
+			aviationWeatherService: &IAviationWeatherServiceMock{
+				GetMetarFunc: func(ctx context.Context, icao string) (*dto.Metar, error) {
+					return ifrMetar(), nil
+				},
+			},
+			expectedResult: []dto.AirportWeather{{
+				Airport:              dto.Airport{ID: 1, ICAO: "KLAX", City: &city},
+				Weather:              &dto.Weather{VisKm: 16},
+				FlightCategory:       dto.FlightCategoryIFR,
+				FlightCategorySource: dto.FlightCategorySourceMetar,
+			}},
+		},
+		{
+			name: "Success filter by flight category",
+			airportService: &IAirportServiceMock{
+				SearchAirportFunc: func(ctx context.Context, icao, name string, limit, offset int) ([]dto.Airport, error) {
+					return []dto.Airport{{ID: 1, ICAO: "KLAX"}, {ID: 2, ICAO: "KAVL"}}, nil
+				},
+			},
+			weatherService: &IWeatherServiceMock{},
+			aviationWeatherService: &IAviationWeatherServiceMock{
+				GetMetarFunc: func(ctx context.Context, icao string) (*dto.Metar, error) {
+					if icao == "KAVL" {
+						return ifrMetar(), nil
+					}
+					return &dto.Metar{Conditions: dto.Conditions{Visibility: &dto.Visibility{StatuteMiles: 10}}}, nil
+				},
+			},
+			categories: []string{dto.FlightCategoryIFR, dto.FlightCategoryLIFR},
+			expectedResult: []dto.AirportWeather{{
+				Airport:              dto.Airport{ID: 2, ICAO: "KAVL"},
+				FlightCategory:       dto.FlightCategoryIFR,
+				FlightCategorySource: dto.FlightCategorySourceMetar,
+			}},
+		},
+		{
+			name: "Success with weather by coordinates",
+			airportService: &IAirportServiceMock{
+				SearchAirportFunc: func(ctx context.Context, icao, name string, limit, offset int) ([]dto.Airport, error) {
+					return []dto.Airport{{ID: 1, ICAO: "KLAX", City: &city, LatitudeDeg: &lat, LongitudeDeg: &lon}}, nil
+				},
+			},
+			weatherService: &IWeatherServiceMock{
+				GetWeatherByCoordinatesFunc: func(ctx context.Context, lat, lon float64) (*dto.Weather, error) {
+					return &dto.Weather{TempC: 21.5, VisKm: 16}, nil
+				},
+			},
+			aviationWeatherService: noMetarService(),
+			expectedResult: []dto.AirportWeather{{
+				Airport:              dto.Airport{ID: 1, ICAO: "KLAX", City: &city, LatitudeDeg: &lat, LongitudeDeg: &lon},
+				Weather:              &dto.Weather{TempC: 21.5, VisKm: 16},
+				FlightCategory:       dto.FlightCategoryVFR,
+				FlightCategorySource: dto.FlightCategorySourceWeatherAPI,
+			}},
+		},
+		{
+			name: "Success with weather by city when coordinates lookup fails",
+			airportService: &IAirportServiceMock{
+				SearchAirportFunc: func(ctx context.Context, icao, name string, limit, offset int) ([]dto.Airport, error) {
+					return []dto.Airport{{ID: 1, ICAO: "KLAX", City: &city, LatitudeDeg: &lat, LongitudeDeg: &lon}}, nil
+				},
+			},
+			weatherService: &IWeatherServiceMock{
+				GetWeatherByCoordinatesFunc: func(ctx context.Context, lat, lon float64) (*dto.Weather, error) {
+					return nil, fmt.Errorf("Weather not available")
+				},
+				GetWeatherFunc: func(ctx context.Context, city string) (*dto.Weather, error) {
+					return &dto.Weather{TempC: 17.2, VisKm: 16}, nil
+				},
+			},
+			aviationWeatherService: noMetarService(),
+			expectedResult: []dto.AirportWeather{{
+				Airport:              dto.Airport{ID: 1, ICAO: "KLAX", City: &city, LatitudeDeg: &lat, LongitudeDeg: &lon},
+				Weather:              &dto.Weather{TempC: 17.2, VisKm: 16},
+				FlightCategory:       dto.FlightCategoryVFR,
+				FlightCategorySource: dto.FlightCategorySourceWeatherAPI,
 			}},
 		},
 		{
@@ -57,6 +155,7 @@ func TestAirportWeatherService_SearchAirportWeather(t *testing.T) {
 					return &dto.Weather{}, nil
 				},
 			},
+			aviationWeatherService: noMetarService(),
 			expectedResult: []dto.AirportWeather{{
 				Airport: dto.Airport{ID: 1, ICAO: "KLAX"},
 			}},
@@ -73,6 +172,11 @@ func TestAirportWeatherService_SearchAirportWeather(t *testing.T) {
 					return nil, fmt.Errorf("Weather not available")
 				},
 			},
+			aviationWeatherService: &IAviationWeatherServiceMock{
+				GetMetarFunc: func(ctx context.Context, icao string) (*dto.Metar, error) {
+					return nil, fmt.Errorf("METAR not available")
+				},
+			},
 			expectedResult: []dto.AirportWeather{{
 				Airport: dto.Airport{ID: 1, ICAO: "KLAX", City: &city},
 			}},
@@ -97,11 +201,11 @@ func TestAirportWeatherService_SearchAirportWeather(t *testing.T) {
 	defer log.Sync()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := NewAirportWeatherService(log, tt.airportService, tt.weatherService)
+			s := NewAirportWeatherService(log, &IAirportRepositoryMock{}, tt.airportService, tt.weatherService, tt.aviationWeatherService)
 
 			ctx := context.Background()
 
-			got, err := s.SearchAirportWeather(ctx, "KLAX", "Lorem Ipsum", 20, 0)
+			got, err := s.SearchAirportWeather(ctx, "KLAX", "Lorem Ipsum", tt.categories, 20, 0)
 			if err != nil && err.Error() != tt.expectedErr.Error() {
 				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
 			}
@@ -112,3 +216,185 @@ func TestAirportWeatherService_SearchAirportWeather(t *testing.T) {
 		})
 	}
 }
+
+func TestAirportWeatherService_SearchAirportWeather_CategoryPages(t *testing.T) {
+	// KIF2, KIF3, KIF5 and KIF7 are IFR, their matches spread over the search pages
+	var airports []dto.Airport
+	for _, icao := range []string{"KVF1", "KIF2", "KIF3", "KVF4", "KIF5", "KVF6", "KIF7"} {
+		airports = append(airports, dto.Airport{ID: len(airports) + 1, ICAO: icao})
+	}
+	// No airport of the second store is IFR, a search for them stops at the scan limit
+	var vfrAirports []dto.Airport
+	for i := 0; i < 600; i++ {
+		vfrAirports = append(vfrAirports, dto.Airport{ID: i + 1, ICAO: fmt.Sprintf("KV%02d", i%100)})
+	}
+	aviationWeatherService := &IAviationWeatherServiceMock{
+		GetMetarFunc: func(ctx context.Context, icao string) (*dto.Metar, error) {
+			if icao[1:3] == "IF" {
+				return ifrMetar(), nil
+			}
+			return &dto.Metar{Conditions: dto.Conditions{Visibility: &dto.Visibility{StatuteMiles: 10}}}, nil
+		},
+	}
+	page := func(store []dto.Airport, limit, offset int) []dto.Airport {
+		if offset > len(store) {
+			return nil
+		}
+		end := offset + limit
+		if end > len(store) {
+			end = len(store)
+		}
+		return store[offset:end]
+	}
+
+	tests := []struct {
+		name              string
+		store             []dto.Airport
+		limit             int
+		offset            int
+		failAt            int
+		expectedICAOs     []string
+		expectedRepoPages []int
+		expectedErr       error
+	}{
+		{
+			name:              "First page of matches spans two search pages",
+			store:             airports,
+			limit:             2,
+			offset:            0,
+			expectedICAOs:     []string{"KIF2", "KIF3"},
+			expectedRepoPages: []int{2},
+		},
+		{
+			name:              "Offset counts matches",
+			store:             airports,
+			limit:             2,
+			offset:            1,
+			expectedICAOs:     []string{"KIF3", "KIF5"},
+			expectedRepoPages: []int{2, 4},
+		},
+		{
+			name:              "Search runs out before limit",
+			store:             airports,
+			limit:             3,
+			offset:            2,
+			expectedICAOs:     []string{"KIF5", "KIF7"},
+			expectedRepoPages: []int{3, 6},
+		},
+		{
+			name:              "Offset past every match",
+			store:             airports,
+			limit:             2,
+			offset:            4,
+			expectedRepoPages: []int{2, 4, 6},
+		},
+		{
+			name:              "Failed to search a later page",
+			store:             airports,
+			limit:             2,
+			offset:            0,
+			failAt:            2,
+			expectedRepoPages: []int{2},
+			expectedErr:       fmt.Errorf("Failed to get airports"),
+		},
+		{
+			name:              "Scan stops at the limit",
+			store:             vfrAirports,
+			limit:             100,
+			offset:            0,
+			expectedRepoPages: []int{100, 200, 300, 400},
+			expectedErr:       &CategoryScanLimitError{Scanned: 500},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			airportService := &IAirportServiceMock{
+				SearchAirportFunc: func(ctx context.Context, icao, name string, limit, offset int) ([]dto.Airport, error) {
+					return page(tt.store, limit, offset), nil
+				},
+			}
+			airportRepo := &IAirportRepositoryMock{
+				GetByICAOOrFacilityNameFunc: func(ctx context.Context, icao, facilityName string, limit, offset int) ([]dto.Airport, error) {
+					if tt.failAt > 0 && offset == tt.failAt {
+						return nil, fmt.Errorf("Failed to get airports")
+					}
+					return page(tt.store, limit, offset), nil
+				},
+			}
+			s := NewAirportWeatherService(log, airportRepo, airportService, &IWeatherServiceMock{}, aviationWeatherService)
+
+			got, err := s.SearchAirportWeather(context.Background(), "", "", []string{dto.FlightCategoryIFR}, tt.limit, tt.offset)
+			if (err != nil || tt.expectedErr != nil) && (err == nil || tt.expectedErr == nil || err.Error() != tt.expectedErr.Error()) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			var icaos []string
+			for _, airportWeather := range got {
+				icaos = append(icaos, airportWeather.Airport.ICAO)
+			}
+			if !reflect.DeepEqual(icaos, tt.expectedICAOs) {
+				t.Errorf("Expected airports %v, got %v", tt.expectedICAOs, icaos)
+			}
+
+			// Only the first page goes through SearchAirport and its AviationAPI fallback
+			if calls := airportService.SearchAirportCalls(); len(calls) != 1 || calls[0].Offset != 0 {
+				t.Errorf("Expected one SearchAirport call for the first page, got %+v", calls)
+			}
+			var repoPages []int
+			for _, call := range airportRepo.GetByICAOOrFacilityNameCalls() {
+				repoPages = append(repoPages, call.Offset)
+			}
+			if !reflect.DeepEqual(repoPages, tt.expectedRepoPages) {
+				t.Errorf("Expected repository offsets %v, got %v", tt.expectedRepoPages, repoPages)
+			}
+		})
+	}
+}
+
+func TestAirportWeatherService_SearchAirportWeather_KnownICAO(t *testing.T) {
+	// An ICAO search finds one airport, the second page must not look the ICAO up at AviationAPI again
+	airportService := &IAirportServiceMock{
+		SearchAirportFunc: func(ctx context.Context, icao, name string, limit, offset int) ([]dto.Airport, error) {
+			return []dto.Airport{{ID: 1, ICAO: icao}}, nil
+		},
+	}
+	airportRepo := &IAirportRepositoryMock{
+		GetByICAOOrFacilityNameFunc: func(ctx context.Context, icao, facilityName string, limit, offset int) ([]dto.Airport, error) {
+			return nil, nil
+		},
+	}
+	log := logger.GetLogger()
+	defer log.Sync()
+	s := NewAirportWeatherService(log, airportRepo, airportService, &IWeatherServiceMock{}, noMetarService())
+
+	got, err := s.SearchAirportWeather(context.Background(), "KLAX", "", []string{dto.FlightCategoryIFR}, 1, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got != nil {
+		t.Errorf("Expected no airport, got %+v", got)
+	}
+	if len(airportService.SearchAirportCalls()) != 1 || len(airportRepo.GetByICAOOrFacilityNameCalls()) != 1 {
+		t.Errorf("Expected one SearchAirport and one repository call, got %d and %d",
+			len(airportService.SearchAirportCalls()), len(airportRepo.GetByICAOOrFacilityNameCalls()))
+	}
+}
+
+func noMetarService() *IAviationWeatherServiceMock {
+	return &IAviationWeatherServiceMock{
+		GetMetarFunc: func(ctx context.Context, icao string) (*dto.Metar, error) {
+			return nil, nil
+		},
+	}
+}
+
+func ifrMetar() *dto.Metar {
+	base := 800
+	return &dto.Metar{Conditions: dto.Conditions{
+		Visibility: &dto.Visibility{StatuteMiles: 10},
+		Clouds:     []dto.CloudLayer{{Cover: "OVC", BaseFt: &base}},
+	}}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"aviation-service/internal/dto"
+	"aviation-service/internal/repository"
+	"aviation-service/pkg/middleware"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	APIKeyHeader = "X-API-Key"
+
+	apiKeyPrefix = "avk_"
+	// The stored prefix is enough to tell keys apart in listings without being usable to authenticate
+	apiKeyDisplayLength = len(apiKeyPrefix) + 8
+	apiKeySecretBytes   = 32
+)
+
+type APIKeyService struct {
+	logger     *zap.SugaredLogger
+	apiKeyRepo repository.IAPIKeyRepository
+}
+
+func NewAPIKeyService(logger *zap.SugaredLogger, apiKeyRepo repository.IAPIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		logger:     logger,
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Issue creates a key for role. Only its SHA-256 hash is stored, so the returned plaintext key can not be shown again
+func (s *APIKeyService) Issue(ctx context.Context, name, role string) (string, *dto.APIKey, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyService.Issue")
+	defer span.End()
+
+	if name == "" {
+		return "", nil, fmt.Errorf("API key name is required")
+	}
+	if !middleware.ValidRole(role) {
+		return "", nil, fmt.Errorf("Invalid role %q (expected reader, editor or operator)", role)
+	}
+
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+	key := apiKeyPrefix + hex.EncodeToString(secret)
+
+	created, err := s.apiKeyRepo.Create(ctx, &dto.APIKey{
+		Name:    name,
+		Prefix:  key[:apiKeyDisplayLength],
+		KeyHash: hashAPIKey(key),
+		Role:    role,
+	})
+	if err != nil {
+		s.logger.Errorw("Failed to create API key", "error", err, "name", name)
+		return "", nil, err
+	}
+	return key, created, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, id int) error {
+	ctx, span := tracer.Start(ctx, "APIKeyService.Revoke")
+	defer span.End()
+
+	return s.apiKeyRepo.Revoke(ctx, id)
+}
+
+func (s *APIKeyService) GetAll(ctx context.Context) ([]dto.APIKey, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyService.GetAll")
+	defer span.End()
+
+	return s.apiKeyRepo.GetAll(ctx)
+}
+
+// Authenticate resolves the caller from the X-API-Key header, an unknown or revoked key is rejected
+func (s *APIKeyService) Authenticate(r *http.Request) (*middleware.Identity, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return nil, nil
+	}
+
+	ctx, span := tracer.Start(r.Context(), "APIKeyService.Authenticate")
+	defer span.End()
+
+	apiKey, err := s.apiKeyRepo.GetActiveByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		s.logger.Errorw("Failed to look up API key", "error", err)
+		return nil, err
+	}
+	if apiKey == nil {
+		return nil, &middleware.CredentialsError{Reason: "Invalid API key"}
+	}
+	return &middleware.Identity{Subject: apiKey.Name, Role: apiKey.Role, Method: middleware.AuthMethodAPIKey}, nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
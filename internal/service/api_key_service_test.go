@@ -0,0 +1,161 @@
+package service_test
+
+import (
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/mock"
+	. "aviation-service/internal/service"
+	"aviation-service/pkg/logger"
+	"aviation-service/pkg/middleware"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAPIKeyService_Issue(t *testing.T) {
+	tests := []struct {
+		name        string
+		keyName     string
+		role        string
+		createErr   error
+		expectedErr error
+	}{
+		{
+			name:    "Success issue key",
+			keyName: "ci",
+			role:    middleware.RoleEditor,
+		},
+		{
+			name:        "Error missing name",
+			role:        middleware.RoleEditor,
+			expectedErr: fmt.Errorf("API key name is required"),
+		},
+		{
+			name:        "Error invalid role",
+			keyName:     "ci",
+			role:        "admin",
+			expectedErr: fmt.Errorf("Invalid role \"admin\" (expected reader, editor or operator)"),
+		},
+		{
+			name:        "Error create key",
+			keyName:     "ci",
+			role:        middleware.RoleEditor,
+			createErr:   fmt.Errorf("db error"),
+			expectedErr: fmt.Errorf("db error"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored *dto.APIKey
+			repo := &IAPIKeyRepositoryMock{
+				CreateFunc: func(ctx context.Context, key *dto.APIKey) (*dto.APIKey, error) {
+					stored = key
+					if tt.createErr != nil {
+						return nil, tt.createErr
+					}
+					created := *key
+					created.ID = 1
+					return &created, nil
+				},
+			}
+
+			key, created, err := NewAPIKeyService(log, repo).Issue(context.Background(), tt.keyName, tt.role)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr != nil {
+				return
+			}
+
+			sum := sha256.Sum256([]byte(key))
+			if !strings.HasPrefix(key, "avk_") || stored.KeyHash != hex.EncodeToString(sum[:]) {
+				t.Errorf("Expected the SHA-256 of %q to be stored, got %q", key, stored.KeyHash)
+			}
+			if !strings.HasPrefix(key, stored.Prefix) || strings.Contains(stored.Prefix, key) {
+				t.Errorf("Expected a short prefix of %q, got %q", key, stored.Prefix)
+			}
+			if created.ID != 1 || created.Role != tt.role {
+				t.Errorf("Expected the created key, got %+v", created)
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	const key = "avk_0123456789abcdef"
+	sum := sha256.Sum256([]byte(key))
+	keyHash := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name             string
+		header           string
+		apiKey           *dto.APIKey
+		lookupErr        error
+		expectedIdentity *middleware.Identity
+		expectedErr      error
+	}{
+		{
+			name:             "Success known key",
+			header:           key,
+			apiKey:           &dto.APIKey{ID: 1, Name: "ci", Role: middleware.RoleEditor},
+			expectedIdentity: &middleware.Identity{Subject: "ci", Role: middleware.RoleEditor, Method: middleware.AuthMethodAPIKey},
+		},
+		{
+			name: "No key sent",
+		},
+		{
+			name:        "Error unknown or revoked key",
+			header:      key,
+			expectedErr: &middleware.CredentialsError{Reason: "Invalid API key"},
+		},
+		{
+			name:        "Error lookup",
+			header:      key,
+			lookupErr:   fmt.Errorf("db error"),
+			expectedErr: fmt.Errorf("db error"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &IAPIKeyRepositoryMock{
+				GetActiveByHashFunc: func(ctx context.Context, hash string) (*dto.APIKey, error) {
+					if hash != keyHash {
+						t.Errorf("Expected lookup by %q, got %q", keyHash, hash)
+					}
+					return tt.apiKey, tt.lookupErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/airport", nil)
+			if tt.header != "" {
+				req.Header.Set(APIKeyHeader, tt.header)
+			}
+			identity, err := NewAPIKeyService(log, repo).Authenticate(req)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			var credentialsErr *middleware.CredentialsError
+			if _, ok := tt.expectedErr.(*middleware.CredentialsError); ok && !errors.As(err, &credentialsErr) {
+				t.Errorf("Expected a credentials error, got %T", err)
+			}
+			if !reflect.DeepEqual(identity, tt.expectedIdentity) {
+				t.Errorf("Expected identity %+v, got %+v", tt.expectedIdentity, identity)
+			}
+			if tt.header == "" && len(repo.GetActiveByHashCalls()) != 0 {
+				t.Error("Expected no lookup without a key")
+			}
+		})
+	}
+}
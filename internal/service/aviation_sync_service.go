@@ -1,19 +1,102 @@
 package service
 
 import (
+	"aviation-service/config"
+	"aviation-service/internal/client"
 	"aviation-service/internal/dto"
 	"aviation-service/internal/repository"
+	"aviation-service/internal/utils"
+	"aviation-service/pkg/redis"
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	goredis "github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 type AviationSyncService struct {
 	logger         *zap.SugaredLogger
 	airportRepo    repository.IAirportRepository
+	syncRunRepo    repository.ISyncRunRepository
+	conflictRepo   repository.IAirportConflictRepository
 	airportService IAirportService
+	redisClient    redis.RedisClient
+	policy         SyncPolicy
+	// jobs holds the *syncJob of every sync running in this process by run id
+	jobs sync.Map
+}
+
+// SyncInProgressError is returned by Sync while another sync holds the lock, Run is the run it executes when it could be found
+type SyncInProgressError struct {
+	Run *dto.SyncRun
+}
+
+func (e *SyncInProgressError) Error() string {
+	if e.Run == nil {
+		return "Sync already in progress"
+	}
+	return fmt.Sprintf("Sync run %d already in progress", e.Run.ID)
+}
+
+// AirportsNotFoundError is returned by a targeted sync when some of the requested ICAOs are not stored, nothing is synced then
+type AirportsNotFoundError struct {
+	ICAOs []string
+}
+
+func (e *AirportsNotFoundError) Error() string {
+	return fmt.Sprintf("Airports not found: %s", strings.Join(e.ICAOs, ", "))
+}
+
+// SyncPolicy decides how synced data is merged, when DONE airports are refreshed and how failed airports are retried
+type SyncPolicy struct {
+	MergePolicy    string
+	StaleMaxAge    time.Duration
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+func SyncPolicyFromConfig(cfg config.Config) SyncPolicy {
+	return SyncPolicy{
+		MergePolicy:    cfg.SYNC_MERGE_POLICY,
+		StaleMaxAge:    cfg.SYNC_STALE_MAX_AGE,
+		MaxAttempts:    cfg.SYNC_MAX_ATTEMPTS,
+		RetryBaseDelay: cfg.SYNC_RETRY_BASE_DELAY,
+		RetryMaxDelay:  cfg.SYNC_RETRY_MAX_DELAY,
+	}
+}
+
+// Claimed airports are handed to another run once this lease expires, long enough for any sync run to finish
+const syncClaimLease = 30 * time.Minute
+
+// The sync lock is shared by the API and every scheduler replica, it expires this long after its holder stops renewing it
+const (
+	syncLockKey = "sync:lock"
+	syncLockTTL = 30 * time.Second
+)
+
+// Airports are fetched from AviationAPI in batches of syncBatchLength ICAOs by syncWorkers concurrent workers
+const (
+	syncBatchLength = 30
+	syncWorkers     = 10
+)
+
+var errSyncCancelled = errors.New("Sync cancelled")
+
+// A job running in another process is cancelled through this key, its workers look for it before starting a batch.
+// It outlives any run, a run never lasts longer than its claim lease
+const syncCancelKeyPrefix = "sync:cancel:"
+
+func syncCancelKey(id int) string {
+	return syncCancelKeyPrefix + strconv.Itoa(id)
 }
 
 type SyncStats struct {
@@ -21,122 +104,662 @@ type SyncStats struct {
 	success int
 	failed  int
 	err     int
+	batches int
+}
+
+// syncJob is a sync run executing in this process, the run is only read once done is closed
+type syncJob struct {
+	run      *dto.SyncRun
+	batches  int
+	stats    SyncStats
+	cancel   context.CancelCauseFunc
+	done     chan struct{}
+	response *dto.SyncResponse
+	err      error
 }
 
-func NewAviationSyncService(logger *zap.SugaredLogger, airportRepo repository.IAirportRepository, airportService IAirportService) *AviationSyncService {
+func (j *syncJob) snapshot() *dto.SyncJob {
+	job := &dto.SyncJob{
+		ID:        j.run.ID,
+		Trigger:   j.run.Trigger,
+		Mode:      j.run.Mode,
+		Status:    dto.SyncRunStatusRunning,
+		StartedAt: j.run.StartedAt,
+		Total:     j.run.Total,
+		Batches:   j.batches,
+	}
+	select {
+	case <-j.done:
+		job.Status = j.run.Status
+		job.FinishedAt = j.run.FinishedAt
+		job.ErrorMessage = j.run.ErrorMessage
+	default:
+	}
+
+	j.stats.mu.Lock()
+	defer j.stats.mu.Unlock()
+	job.BatchesDone = j.stats.batches
+	job.Success = j.stats.success
+	job.Failed = j.stats.failed
+	job.Error = j.stats.err
+	return job
+}
+
+// syncJobFromRun reports a run this process is not executing, the batches done are counted from its recorded items
+func syncJobFromRun(run *dto.SyncRun) *dto.SyncJob {
+	batches := map[int]bool{}
+	for _, item := range run.Items {
+		if item.Outcome != dto.SyncOutcomeCancelled {
+			batches[item.Batch] = true
+		}
+	}
+	return &dto.SyncJob{
+		ID:           run.ID,
+		Trigger:      run.Trigger,
+		Mode:         run.Mode,
+		Status:       run.Status,
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.FinishedAt,
+		Total:        run.Total,
+		Batches:      (run.Total + syncBatchLength - 1) / syncBatchLength,
+		BatchesDone:  len(batches),
+		Success:      run.Success,
+		Failed:       run.Failed,
+		Error:        run.Error,
+		ErrorMessage: run.ErrorMessage,
+	}
+}
+
+type syncBatch struct {
+	index int
+	icaos []string
+}
+
+func NewAviationSyncService(logger *zap.SugaredLogger, airportRepo repository.IAirportRepository, syncRunRepo repository.ISyncRunRepository,
+	conflictRepo repository.IAirportConflictRepository, airportService IAirportService, redisClient redis.RedisClient, policy SyncPolicy) *AviationSyncService {
 	return &AviationSyncService{
 		logger:         logger,
 		airportRepo:    airportRepo,
+		syncRunRepo:    syncRunRepo,
+		conflictRepo:   conflictRepo,
 		airportService: airportService,
+		redisClient:    redisClient,
+		policy:         policy,
 	}
 }
 
-func (s *AviationSyncService) Sync(ctx context.Context) (*dto.SyncResponse, error) {
-	var syncStats SyncStats
-	airports, err := s.airportRepo.GetAllPending(ctx)
+// Sync runs while holding the sync lock, so a sync triggered through the API and the scheduler never overlap.
+// A lost lock cancels the run and a held one returns a SyncInProgressError. A run stopped before its last batch returns
+// its response along with the cause it was stopped for
+func (s *AviationSyncService) Sync(ctx context.Context, opts dto.SyncOptions) (*dto.SyncResponse, error) {
+	ctx, span := tracer.Start(ctx, "AviationSyncService.Sync", trace.WithAttributes(attribute.String("sync.trigger", opts.Trigger), attribute.String("sync.mode", opts.Mode)))
+	defer span.End()
+
+	job, err := s.startJob(ctx, opts)
+	if err != nil || job == nil {
+		return nil, err
+	}
+	<-job.done
+	return job.response, job.err
+}
+
+// StartSync starts a sync as a job that outlives the request and returns it once its airports are claimed,
+// the job is followed with GetSyncJob and stopped with CancelSyncJob
+func (s *AviationSyncService) StartSync(ctx context.Context, opts dto.SyncOptions) (*dto.SyncJob, error) {
+	ctx, span := tracer.Start(ctx, "AviationSyncService.StartSync", trace.WithAttributes(attribute.String("sync.trigger", opts.Trigger), attribute.String("sync.mode", opts.Mode)))
+	defer span.End()
+
+	job, err := s.startJob(context.WithoutCancel(ctx), opts)
 	if err != nil {
-		s.logger.Errorw("Failed get all pending airports", "error", err)
+		return nil, err
+	}
+	if job == nil {
+		return nil, nil
+	}
+	return job.snapshot(), nil
+}
+
+// GetSyncJob reports the live progress of a job running in this process, other runs are reported from their sync run
+func (s *AviationSyncService) GetSyncJob(ctx context.Context, id int) (*dto.SyncJob, error) {
+	ctx, span := tracer.Start(ctx, "AviationSyncService.GetSyncJob")
+	defer span.End()
+
+	if job := s.job(id); job != nil {
+		return job.snapshot(), nil
+	}
+
+	run, err := s.syncRunRepo.GetById(ctx, id)
+	if err != nil {
+		s.logger.Errorw("Failed to get sync job", "error", err, "id", id)
+		return nil, err
+	}
+	if run == nil {
+		return nil, nil
+	}
+	return syncJobFromRun(run), nil
+}
+
+// CancelSyncJob cancels a job running in this process and waits for its workers to stop, a finished job is returned as is.
+// A job running in another process is asked to stop and returned still running, it stops before its next batch
+func (s *AviationSyncService) CancelSyncJob(ctx context.Context, id int) (*dto.SyncJob, error) {
+	ctx, span := tracer.Start(ctx, "AviationSyncService.CancelSyncJob")
+	defer span.End()
+
+	job := s.job(id)
+	if job == nil {
+		syncJob, err := s.GetSyncJob(ctx, id)
+		if err != nil || syncJob == nil {
+			return nil, err
+		}
+		if syncJob.Status == dto.SyncRunStatusRunning {
+			s.logger.Infow("Requesting cancellation of sync job running in another process", "id", id)
+			if err := s.redisClient.Set(ctx, syncCancelKey(id), "1", syncClaimLease).Err(); err != nil {
+				s.logger.Errorw("Failed to request sync job cancellation", "error", err, "id", id)
+				return nil, err
+			}
+		}
+		return syncJob, nil
+	}
+
+	s.logger.Infow("Cancelling sync job", "id", id)
+	job.cancel(errSyncCancelled)
+	select {
+	case <-job.done:
+	case <-ctx.Done():
+	}
+	return job.snapshot(), nil
+}
+
+// Shutdown cancels the sync jobs running in this process and waits until they recorded their runs or ctx is done
+func (s *AviationSyncService) Shutdown(ctx context.Context) {
+	var jobs []*syncJob
+	s.jobs.Range(func(_, value any) bool {
+		job := value.(*syncJob)
+		job.cancel(errSyncCancelled)
+		jobs = append(jobs, job)
+		return true
+	})
+
+	for _, job := range jobs {
+		s.logger.Infow("Waiting for cancelled sync job", "id", job.run.ID)
+		select {
+		case <-job.done:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// startJob takes the sync lock, creates the run and claims its airports, the batches are then synced in the background.
+// It returns nil when there is nothing to sync
+func (s *AviationSyncService) startJob(ctx context.Context, opts dto.SyncOptions) (*syncJob, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	lock, ctx, err := s.acquireLock(ctx)
+	if err != nil {
+		cancel(nil)
+		return nil, err
+	}
+	stop := func() {
+		lock.Release(context.WithoutCancel(ctx))
+		cancel(nil)
+	}
+
+	mode := opts.Mode
+	if mode == "" {
+		mode = dto.SyncModePending
+	}
+	if len(opts.ICAOs) > 0 {
+		mode = dto.SyncModeTargeted
+	}
+	run, err := s.syncRunRepo.Create(ctx, &dto.SyncRun{Trigger: opts.Trigger, Mode: mode, Status: dto.SyncRunStatusRunning})
+	if err != nil {
+		s.logger.Errorw("Failed to create sync run", "error", err)
+		stop()
+		return nil, err
+	}
+	ctx = utils.WithActor(ctx, utils.SyncRunActor(run.ID))
+
+	airports, err := s.airportsToSync(ctx, mode, opts.ICAOs)
+	if err != nil {
+		s.logger.Errorw("Failed to get airports to sync", "error", err, "mode", mode)
+		s.finishRun(ctx, run, err)
+		stop()
 		return nil, err
 	}
 
 	if len(airports) == 0 {
+		s.finishRun(ctx, run, nil)
+		stop()
 		return nil, nil
 	}
-	s.logger.Infow("Syncing pending airports", "count", len(airports))
+	s.logger.Infow("Syncing airports", "count", len(airports), "mode", mode, "runId", run.ID, "mergePolicy", s.policy.MergePolicy)
+
+	run.Total = len(airports)
+	job := &syncJob{
+		run:     run,
+		batches: (len(airports) + syncBatchLength - 1) / syncBatchLength,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	s.jobs.Store(run.ID, job)
 
-	batchLength := 30
-	numWorkers := 10
-	icaoChannel := make(chan string, len(airports)/batchLength)
+	go func() {
+		defer s.jobs.Delete(run.ID)
+		defer close(job.done)
+		defer stop()
+		s.runJob(ctx, job, airports)
+	}()
+	return job, nil
+}
+
+func (s *AviationSyncService) runJob(ctx context.Context, job *syncJob, airports []dto.Airport) {
+	run := job.run
+	syncStats := &job.stats
+
+	// The stored rows carry the field provenance the merge policy needs
+	existing := make(map[string]*dto.Airport, len(airports))
+	for i := range airports {
+		existing[airports[i].ICAO] = &airports[i]
+	}
+
+	batchChannel := make(chan syncBatch, job.batches)
 	wg := sync.WaitGroup{}
 
-	for i := 0; i < numWorkers; i++ {
+	for i := 0; i < syncWorkers; i++ {
 		wg.Add(1)
-		go s.getAirportData(ctx, &wg, &syncStats, icaoChannel)
+		go s.getAirportData(ctx, &wg, run.ID, existing, syncStats, batchChannel)
 	}
 
 	go func() {
+		index := 0
 		batch := []string{}
 		for _, apt := range airports {
 			batch = append(batch, apt.ICAO)
 
-			if len(batch) == batchLength {
-				batchString := strings.Join(batch, ",")
-				icaoChannel <- batchString
+			if len(batch) == syncBatchLength {
+				batchChannel <- syncBatch{index: index, icaos: batch}
 
+				index++
 				batch = []string{}
 			}
 		}
 
 		if len(batch) > 0 {
-			batchString := strings.Join(batch, ",")
-			icaoChannel <- batchString
+			batchChannel <- syncBatch{index: index, icaos: batch}
 		}
-		close(icaoChannel)
+		close(batchChannel)
 	}()
 	wg.Wait()
 
-	syncResponse := dto.SyncResponse{
-		Total:   len(airports),
-		Success: syncStats.success,
-		Failed:  syncStats.failed,
-		Error:   syncStats.err,
+	syncStats.mu.Lock()
+	run.Success = syncStats.success
+	run.Failed = syncStats.failed
+	run.Error = syncStats.err
+	syncStats.mu.Unlock()
+	job.err = context.Cause(ctx)
+	s.finishRun(ctx, run, job.err)
+
+	job.response = &dto.SyncResponse{
+		RunID:   run.ID,
+		Mode:    run.Mode,
+		Total:   run.Total,
+		Success: run.Success,
+		Failed:  run.Failed,
+		Error:   run.Error,
+	}
+}
+
+func (s *AviationSyncService) job(id int) *syncJob {
+	if job, ok := s.jobs.Load(id); ok {
+		return job.(*syncJob)
+	}
+	return nil
+}
+
+func (s *AviationSyncService) acquireLock(ctx context.Context) (*utils.RedisLock, context.Context, error) {
+	lock, lockCtx, err := utils.AcquireLock(s.redisClient, ctx, syncLockKey, syncLockTTL)
+	if errors.Is(err, utils.ErrLockHeld) {
+		run, runErr := s.syncRunRepo.GetRunning(ctx)
+		if runErr != nil {
+			s.logger.Errorw("Failed to get running sync run", "error", runErr)
+		}
+		s.logger.Infow("Sync already in progress", "run", run)
+		return nil, nil, &SyncInProgressError{Run: run}
+	}
+	if err != nil {
+		s.logger.Errorw("Failed to acquire sync lock", "error", err)
+		return nil, nil, err
+	}
+	return lock, lockCtx, nil
+}
+
+// airportsToSync loads the airports of a run. Pending mode claims the PENDING airports and the RETRYING ones that are due,
+// stale mode takes the DONE airports not synced within the stale max age, failed mode the FAILED airports and targeted mode the requested ICAOs
+func (s *AviationSyncService) airportsToSync(ctx context.Context, mode string, icaos []string) ([]dto.Airport, error) {
+	switch mode {
+	case dto.SyncModePending:
+		return s.airportRepo.ClaimDue(ctx, time.Now().Add(syncClaimLease))
+	case dto.SyncModeStale:
+		return s.airportRepo.GetStale(ctx, time.Now().Add(-s.policy.StaleMaxAge))
+	case dto.SyncModeFailed:
+		return s.airportRepo.GetByStatus(ctx, dto.AirportStatusFailed)
+	case dto.SyncModeTargeted:
+		return s.targetedAirports(ctx, icaos)
+	default:
+		return nil, fmt.Errorf("Unsupported sync mode %q", mode)
+	}
+}
+
+// targetedAirports loads the requested airports whatever their status, the sync lock keeps them from being claimed meanwhile
+func (s *AviationSyncService) targetedAirports(ctx context.Context, icaos []string) ([]dto.Airport, error) {
+	requested := map[string]bool{}
+	for _, icao := range icaos {
+		requested[strings.ToUpper(strings.TrimSpace(icao))] = true
+	}
+	delete(requested, "")
+	normalized := make([]string, 0, len(requested))
+	for icao := range requested {
+		normalized = append(normalized, icao)
+	}
+
+	airports, err := s.airportRepo.GetByICAOs(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	for _, airport := range airports {
+		delete(requested, airport.ICAO)
+	}
+	if len(requested) > 0 {
+		missing := make([]string, 0, len(requested))
+		for icao := range requested {
+			missing = append(missing, icao)
+		}
+		sort.Strings(missing)
+		return nil, &AirportsNotFoundError{ICAOs: missing}
+	}
+	return airports, nil
+}
+
+// RefreshAirport syncs a single airport whatever its status and returns it as stored before and after the sync,
+// it takes the sync lock like any other sync
+func (s *AviationSyncService) RefreshAirport(ctx context.Context, id int) (*dto.AirportRefresh, error) {
+	ctx, span := tracer.Start(ctx, "AviationSyncService.RefreshAirport")
+	defer span.End()
+
+	before, err := s.airportRepo.GetById(ctx, id)
+	if err != nil {
+		s.logger.Errorw("Failed to get airport to refresh", "error", err, "id", id)
+		return nil, err
+	}
+	if before == nil {
+		return nil, nil
+	}
+
+	syncResponse, err := s.Sync(ctx, dto.SyncOptions{Trigger: dto.SyncTriggerAPI, ICAOs: []string{before.ICAO}})
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := s.airportRepo.GetById(ctx, id)
+	if err != nil {
+		s.logger.Errorw("Failed to get refreshed airport", "error", err, "id", id)
+		return nil, err
+	}
+	s.logger.Infow("Airport refreshed", "id", id, "icao", before.ICAO)
+	return &dto.AirportRefresh{Before: before, After: after, Sync: syncResponse}, nil
+}
+
+func (s *AviationSyncService) GetAllSyncRun(ctx context.Context, limit, offset int) ([]dto.SyncRun, error) {
+	ctx, span := tracer.Start(ctx, "AviationSyncService.GetAllSyncRun")
+	defer span.End()
+
+	runs, err := s.syncRunRepo.GetAll(ctx, limit, offset)
+	if err != nil {
+		s.logger.Errorw("Failed to get all sync runs", "error", err)
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (s *AviationSyncService) GetSyncRun(ctx context.Context, id int) (*dto.SyncRun, error) {
+	ctx, span := tracer.Start(ctx, "AviationSyncService.GetSyncRun")
+	defer span.End()
+
+	run, err := s.syncRunRepo.GetById(ctx, id)
+	if err != nil {
+		s.logger.Errorw("Failed to get sync run", "error", err, "id", id)
+		return nil, err
 	}
-	return &syncResponse, nil
+	return run, nil
 }
 
-func (s *AviationSyncService) getAirportData(ctx context.Context, wg *sync.WaitGroup, syncStats *SyncStats, icaoChannel chan string) {
+func (s *AviationSyncService) getAirportData(ctx context.Context, wg *sync.WaitGroup, runID int, existing map[string]*dto.Airport, syncStats *SyncStats, batchChannel chan syncBatch) {
 	defer wg.Done()
 
-	for icaos := range icaoChannel {
-		airports, err := s.airportService.FetchAirportData(icaos)
+	for batch := range batchChannel {
+		if ctx.Err() == nil && s.cancelRequested(ctx, runID) {
+			if job := s.job(runID); job != nil {
+				job.cancel(errSyncCancelled)
+			}
+		}
+		if ctx.Err() != nil {
+			s.releaseBatch(ctx, runID, batch, existing)
+			continue
+		}
+
+		// A batch already started is finished even when the job is cancelled, only the batches not started are released
+		batchCtx := context.WithoutCancel(ctx)
+		airports, err := s.airportService.FetchAirportData(batchCtx, strings.Join(batch.icaos, ","))
 		if err != nil {
 			s.logger.Errorw("Failed to fetch airports from API", "error", err)
 			syncStats.mu.Lock()
 			syncStats.err += 1
+			syncStats.batches += 1
 			syncStats.mu.Unlock()
+			s.failBatch(batchCtx, runID, batch, existing, dto.SyncOutcomeFetchError, err)
 			continue
 		}
 
-		err = s.updateAirportData(ctx, syncStats, airports)
+		items, err := s.updateAirportData(batchCtx, runID, batch, existing, syncStats, airports)
 		if err != nil {
 			s.logger.Errorw("Failed to update airports from API", "error", err)
 			syncStats.mu.Lock()
 			syncStats.err += 1
+			syncStats.batches += 1
 			syncStats.mu.Unlock()
+			s.failBatch(batchCtx, runID, batch, existing, dto.SyncOutcomeUpdateError, err)
 			continue
 		}
+		s.recordItems(batchCtx, items)
 	}
 }
 
-func (s *AviationSyncService) updateAirportData(ctx context.Context, syncStats *SyncStats, airports *dto.AirportDataResponse) error {
+// cancelRequested reports whether another process asked to cancel the run, a failed lookup lets the batch start
+func (s *AviationSyncService) cancelRequested(ctx context.Context, runID int) bool {
+	_, err := s.redisClient.Get(ctx, syncCancelKey(runID)).Result()
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		s.logger.Warnw("Failed to check sync job cancellation", "error", err, "runId", runID)
+	}
+	return err == nil
+}
+
+func (s *AviationSyncService) updateAirportData(ctx context.Context, runID int, batch syncBatch, existing map[string]*dto.Airport, syncStats *SyncStats, airports *dto.AirportDataResponse) ([]dto.SyncRunItem, error) {
 	s.logger.Infow("Updating airports data", "count", len(*airports))
 	var success, failed int
 	var toUpdate []dto.Airport
+	var conflicts []dto.AirportConflict
+	var states []dto.Airport
+	var items []dto.SyncRunItem
 
 	for icao, apt := range *airports {
+		item := dto.SyncRunItem{SyncRunID: runID, Batch: batch.index, ICAO: icao, Outcome: dto.SyncOutcomeOK}
+		stored, ok := existing[icao]
 		if len(apt) == 0 {
-			toUpdate = append(toUpdate, dto.Airport{
-				ICAO:   icao,
-				Status: "FAILED",
-			})
 			failed++
+			item.Outcome = dto.SyncOutcomeMissing
+			states, item = s.retryItem(states, item, stored, "No airport data returned by AviationAPI")
+			items = append(items, item)
 			continue
 		}
 
-		apt[0].Status = "DONE"
-		toUpdate = append(toUpdate, apt[0])
+		upstream := apt[0]
+		upstream.Status = dto.AirportStatusDone
+		if ok {
+			merged, fieldConflicts := utils.MergeAirport(stored, &upstream, s.policy.MergePolicy)
+			upstream = merged
+			for _, conflict := range fieldConflicts {
+				conflict.SyncRunID = &runID
+				conflicts = append(conflicts, conflict)
+			}
+			item.Changes = utils.AirportChanges(stored, &upstream)
+		}
+		toUpdate = append(toUpdate, upstream)
+		item.Status = upstream.Status
+		items = append(items, item)
 		success++
 	}
 
+	// ICAOs the API left out of the response are retried like the ones it has no data for
+	for _, icao := range batch.icaos {
+		if _, ok := (*airports)[icao]; !ok {
+			failed++
+			item := dto.SyncRunItem{SyncRunID: runID, Batch: batch.index, ICAO: icao, Outcome: dto.SyncOutcomeMissing}
+			states, item = s.retryItem(states, item, existing[icao], "Airport missing from the AviationAPI response")
+			items = append(items, item)
+		}
+	}
+
 	if len(toUpdate) > 0 {
 		if err := s.airportRepo.UpdateByICAO(ctx, toUpdate); err != nil {
 			s.logger.Errorw("Batch update failed", "error", err)
-			return err
+			return nil, err
+		}
+		s.airportService.InvalidateCache(ctx)
+	}
+	if len(conflicts) > 0 {
+		if err := s.conflictRepo.Upsert(context.WithoutCancel(ctx), conflicts); err != nil {
+			s.logger.Errorw("Failed to record airport conflicts", "error", err, "runId", runID)
 		}
 	}
+	s.updateSyncState(ctx, states)
 	syncStats.mu.Lock()
 	syncStats.success += success
 	syncStats.failed += failed
+	syncStats.batches += 1
 	syncStats.mu.Unlock()
-	return nil
+	return items, nil
+}
+
+func (s *AviationSyncService) recordItems(ctx context.Context, items []dto.SyncRunItem) {
+	if err := s.syncRunRepo.InsertItems(context.WithoutCancel(ctx), items); err != nil {
+		s.logger.Errorw("Failed to record sync run items", "error", err)
+	}
+}
+
+func (s *AviationSyncService) finishRun(ctx context.Context, run *dto.SyncRun, runErr error) {
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = dto.SyncRunStatusDone
+	if runErr != nil {
+		message := runErr.Error()
+		run.Status = dto.SyncRunStatusFailed
+		run.ErrorMessage = &message
+	}
+	if errors.Is(runErr, errSyncCancelled) {
+		run.Status = dto.SyncRunStatusCancelled
+	}
+
+	// The run must be closed even when the sync context has already timed out
+	if err := s.syncRunRepo.Finish(context.WithoutCancel(ctx), run); err != nil {
+		s.logger.Errorw("Failed to finish sync run", "error", err, "runId", run.ID)
+	}
+}
+
+// failBatch records a batch that could not be fetched or written, its claimed airports are scheduled for a retry.
+// A batch the upstream rejected keeps the status code it answered with
+func (s *AviationSyncService) failBatch(ctx context.Context, runID int, batch syncBatch, existing map[string]*dto.Airport, outcome string, err error) {
+	var httpStatus *int
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) {
+		httpStatus = &statusErr.StatusCode
+	}
+
+	var states []dto.Airport
+	items := make([]dto.SyncRunItem, 0, len(batch.icaos))
+	for _, icao := range batch.icaos {
+		item := dto.SyncRunItem{SyncRunID: runID, Batch: batch.index, ICAO: icao, Outcome: outcome, HTTPStatus: httpStatus}
+		states, item = s.retryItem(states, item, existing[icao], err.Error())
+		items = append(items, item)
+	}
+	s.updateSyncState(ctx, states)
+	s.recordItems(ctx, items)
+}
+
+// releaseBatch records a batch skipped by a cancelled run, its claimed airports are handed back as due without using up an attempt
+func (s *AviationSyncService) releaseBatch(ctx context.Context, runID int, batch syncBatch, existing map[string]*dto.Airport) {
+	var states []dto.Airport
+	now := time.Now()
+	items := make([]dto.SyncRunItem, 0, len(batch.icaos))
+	for _, icao := range batch.icaos {
+		item := dto.SyncRunItem{SyncRunID: runID, Batch: batch.index, ICAO: icao, Outcome: dto.SyncOutcomeCancelled, Status: dto.AirportStatusPending}
+		if stored := existing[icao]; stored != nil {
+			item.Status = stored.Status
+			if stored.Status == dto.AirportStatusSyncing {
+				state := dto.Airport{ICAO: icao, Status: dto.AirportStatusPending, AttemptCount: stored.AttemptCount, LastError: stored.LastError}
+				if stored.AttemptCount > 0 {
+					state.Status = dto.AirportStatusRetrying
+					state.NextAttemptAt = &now
+				}
+				item.Status = state.Status
+				states = append(states, state)
+			}
+		}
+		items = append(items, item)
+	}
+	s.updateSyncState(ctx, states)
+	s.recordItems(ctx, items)
+}
+
+// retryItem sets the error and resulting status of an item the sync could not complete. An airport claimed by the run
+// moves to RETRYING with an exponential backoff, or to FAILED once it used up its attempts. Refreshed DONE airports keep their status
+func (s *AviationSyncService) retryItem(states []dto.Airport, item dto.SyncRunItem, stored *dto.Airport, message string) ([]dto.Airport, dto.SyncRunItem) {
+	item.Error = &message
+	item.Status = dto.AirportStatusPending
+	if stored == nil {
+		return states, item
+	}
+	item.Status = stored.Status
+	if stored.Status != dto.AirportStatusSyncing {
+		return states, item
+	}
+
+	state := dto.Airport{ICAO: stored.ICAO, Status: dto.AirportStatusFailed, AttemptCount: stored.AttemptCount + 1, LastError: &message}
+	if state.AttemptCount < s.policy.MaxAttempts {
+		nextAttemptAt := time.Now().Add(s.retryDelay(state.AttemptCount))
+		state.Status = dto.AirportStatusRetrying
+		state.NextAttemptAt = &nextAttemptAt
+	}
+	item.Status = state.Status
+	return append(states, state), item
+}
+
+// retryDelay doubles the base delay with every failed attempt, capped at the max delay
+func (s *AviationSyncService) retryDelay(attempt int) time.Duration {
+	delay := s.policy.RetryBaseDelay << (attempt - 1)
+	if delay <= 0 || delay > s.policy.RetryMaxDelay {
+		return s.policy.RetryMaxDelay
+	}
+	return delay
+}
+
+func (s *AviationSyncService) updateSyncState(ctx context.Context, states []dto.Airport) {
+	if len(states) == 0 {
+		return
+	}
+	// Like the run items, the retry state must be kept when the sync context has timed out
+	if err := s.airportRepo.UpdateSyncState(context.WithoutCancel(ctx), states); err != nil {
+		s.logger.Errorw("Failed to update airport sync state", "error", err)
+		return
+	}
+	s.airportService.InvalidateCache(ctx)
 }
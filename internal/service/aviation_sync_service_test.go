@@ -1,14 +1,23 @@
 package service_test
 
 import (
+	"aviation-service/internal/client"
 	"aviation-service/internal/dto"
 	. "aviation-service/internal/mock"
 	"aviation-service/internal/repository"
 	. "aviation-service/internal/service"
+	"aviation-service/internal/utils"
 	"aviation-service/pkg/logger"
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 type expectedCount struct {
@@ -22,6 +31,7 @@ func TestAviationSyncService_Sync(t *testing.T) {
 	tests := []struct {
 		name           string
 		repo           repository.IAirportRepository
+		syncRunRepo    repository.ISyncRunRepository
 		airportService IAirportService
 		expected       expectedCount
 		expectedErr    error
@@ -29,7 +39,7 @@ func TestAviationSyncService_Sync(t *testing.T) {
 		{
 			name: "Success with data",
 			repo: &IAirportRepositoryMock{
-				GetAllPendingFunc: func(ctx context.Context) ([]dto.Airport, error) {
+				ClaimDueFunc: func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
 					var airports []dto.Airport
 					n := 30
 					for i := 1; i <= n; i++ {
@@ -45,31 +55,44 @@ func TestAviationSyncService_Sync(t *testing.T) {
 				},
 			},
 			airportService: &IAirportServiceMock{
-				FetchAirportDataFunc: func(icao string) (*dto.AirportDataResponse, error) {
+				FetchAirportDataFunc: func(ctx context.Context, icao string) (*dto.AirportDataResponse, error) {
 					return &dto.AirportDataResponse{
 						"KLAX": []dto.Airport{}, 
 						"KAVL": []dto.Airport{{ID: 1, ICAO: "KAVL"}},
 						}, nil
 				},
+				InvalidateCacheFunc: func(ctx context.Context) error {
+					return nil
+				},
 			},
 			expected: expectedCount{
 				total:   30,
 				success: 1,
-				failed:  1,
+				failed:  31,
 			},
 		},
 		{
 			name: "Success no data to sync",
 			repo: &IAirportRepositoryMock{
-				GetAllPendingFunc: func(ctx context.Context) ([]dto.Airport, error) {
+				ClaimDueFunc: func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
 					return []dto.Airport{}, nil
 				},
 			},
 		},
+		{
+			name: "Failed create sync run",
+			repo: &IAirportRepositoryMock{},
+			syncRunRepo: &ISyncRunRepositoryMock{
+				CreateFunc: func(ctx context.Context, run *dto.SyncRun) (*dto.SyncRun, error) {
+					return nil, fmt.Errorf("Failed create sync run")
+				},
+			},
+			expectedErr: fmt.Errorf("Failed create sync run"),
+		},
 		{
 			name: "Failed get all pending airports",
 			repo: &IAirportRepositoryMock{
-				GetAllPendingFunc: func(ctx context.Context) ([]dto.Airport, error) {
+				ClaimDueFunc: func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
 					return nil, fmt.Errorf("Failed get all pending airports")
 				},
 			},
@@ -78,7 +101,7 @@ func TestAviationSyncService_Sync(t *testing.T) {
 		{
 			name: "Error fetching airport data from API",
 			repo: &IAirportRepositoryMock{
-				GetAllPendingFunc: func(ctx context.Context) ([]dto.Airport, error) {
+				ClaimDueFunc: func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
 					return []dto.Airport{{ID: 1, ICAO: "KLAX"}}, nil
 				},
 				UpdateByICAOFunc: func(ctx context.Context, airport []dto.Airport) error {
@@ -86,7 +109,7 @@ func TestAviationSyncService_Sync(t *testing.T) {
 				},
 			},
 			airportService: &IAirportServiceMock{
-				FetchAirportDataFunc: func(icao string) (*dto.AirportDataResponse, error) {
+				FetchAirportDataFunc: func(ctx context.Context, icao string) (*dto.AirportDataResponse, error) {
 					return nil, fmt.Errorf("Error fetching airport data")
 				},
 			},
@@ -98,7 +121,7 @@ func TestAviationSyncService_Sync(t *testing.T) {
 		{
 			name: "Error updating airport data",
 			repo: &IAirportRepositoryMock{
-				GetAllPendingFunc: func(ctx context.Context) ([]dto.Airport, error) {
+				ClaimDueFunc: func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
 					return []dto.Airport{{ID: 1, ICAO: "KLAX"}}, nil
 				},
 				UpdateByICAOFunc: func(ctx context.Context, airport []dto.Airport) error {
@@ -106,11 +129,14 @@ func TestAviationSyncService_Sync(t *testing.T) {
 				},
 			},
 			airportService: &IAirportServiceMock{
-				FetchAirportDataFunc: func(icao string) (*dto.AirportDataResponse, error) {
+				FetchAirportDataFunc: func(ctx context.Context, icao string) (*dto.AirportDataResponse, error) {
 					return &dto.AirportDataResponse{
 						"KLAX": []dto.Airport{{ID: 1, ICAO: "KLAX"}},
 						}, nil
 				},
+				InvalidateCacheFunc: func(ctx context.Context) error {
+					return nil
+				},
 			},
 			expected: expectedCount{
 				total: 1,
@@ -123,9 +149,13 @@ func TestAviationSyncService_Sync(t *testing.T) {
 	defer log.Sync()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := NewAviationSyncService(log, tt.repo, tt.airportService)
+			syncRunRepo := tt.syncRunRepo
+			if syncRunRepo == nil {
+				syncRunRepo = newSyncRunRepositoryMock()
+			}
+			s := NewAviationSyncService(log, tt.repo, syncRunRepo, &IAirportConflictRepositoryMock{}, tt.airportService, newMockRedis(), newSyncPolicy(dto.MergePolicyManualWins))
 
-			resp, err := s.Sync(context.Background())
+			resp, err := s.Sync(context.Background(), dto.SyncOptions{Trigger: dto.SyncTriggerAPI})
 			if err != nil {
 				if err.Error() != tt.expectedErr.Error() {
 					t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
@@ -152,3 +182,1141 @@ func TestAviationSyncService_Sync(t *testing.T) {
 		})
 	}
 }
+
+func TestAviationSyncService_SyncRecordsRun(t *testing.T) {
+	var finished *dto.SyncRun
+	var items []dto.SyncRunItem
+	syncRunRepo := &ISyncRunRepositoryMock{
+		CreateFunc: func(ctx context.Context, run *dto.SyncRun) (*dto.SyncRun, error) {
+			if run.Trigger != dto.SyncTriggerCron {
+				t.Errorf("Expected trigger %v, got %v", dto.SyncTriggerCron, run.Trigger)
+			}
+			return &dto.SyncRun{ID: 7, Trigger: run.Trigger, Status: run.Status}, nil
+		},
+		FinishFunc: func(ctx context.Context, run *dto.SyncRun) error {
+			finished = run
+			return nil
+		},
+		InsertItemsFunc: func(ctx context.Context, runItems []dto.SyncRunItem) error {
+			items = append(items, runItems...)
+			return nil
+		},
+	}
+	repo := &IAirportRepositoryMock{
+		ClaimDueFunc: func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
+			return []dto.Airport{{ICAO: "KAVL", Status: dto.AirportStatusSyncing}, {ICAO: "KLAX", Status: dto.AirportStatusSyncing},
+				{ICAO: "KADT", Status: dto.AirportStatusSyncing}}, nil
+		},
+		UpdateSyncStateFunc: func(ctx context.Context, airports []dto.Airport) error {
+			return nil
+		},
+		UpdateByICAOFunc: func(ctx context.Context, airport []dto.Airport) error {
+			if actor := utils.ActorFromContext(ctx); actor != "sync_run:7" {
+				t.Errorf("Expected actor sync_run:7, got %q", actor)
+			}
+			return nil
+		},
+	}
+	airportService := &IAirportServiceMock{
+		FetchAirportDataFunc: func(ctx context.Context, icao string) (*dto.AirportDataResponse, error) {
+			return &dto.AirportDataResponse{
+				"KLAX": []dto.Airport{},
+				"KAVL": []dto.Airport{{ICAO: "KAVL"}},
+			}, nil
+		},
+		InvalidateCacheFunc: func(ctx context.Context) error {
+			return nil
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	s := NewAviationSyncService(log, repo, syncRunRepo, &IAirportConflictRepositoryMock{}, airportService, newMockRedis(), newSyncPolicy(dto.MergePolicyManualWins))
+	resp, err := s.Sync(context.Background(), dto.SyncOptions{Trigger: dto.SyncTriggerCron})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if resp.RunID != 7 {
+		t.Errorf("Expected run id 7, got %d", resp.RunID)
+	}
+	if finished == nil || finished.Status != dto.SyncRunStatusDone || finished.Success != 1 || finished.Failed != 2 {
+		t.Errorf("Expected finished run with 1 success and 2 failed, got %+v", finished)
+	}
+
+	outcomes := map[string]string{}
+	for _, item := range items {
+		outcomes[item.ICAO] = item.Outcome + "/" + item.Status
+	}
+	expected := map[string]string{
+		"KAVL": dto.SyncOutcomeOK + "/DONE",
+		"KLAX": dto.SyncOutcomeMissing + "/RETRYING",
+		"KADT": dto.SyncOutcomeMissing + "/RETRYING",
+	}
+	if !reflect.DeepEqual(outcomes, expected) {
+		t.Errorf("Expected items %v, got %v", expected, outcomes)
+	}
+}
+
+func TestAviationSyncService_SyncMergePolicy(t *testing.T) {
+	manualPhone, upstreamPhone := "828-000-0000", "828-684-2226"
+	oldCity, newCity := "OLD CITY", "ASHEVILLE"
+	runID := 1
+	tests := []struct {
+		name              string
+		policy            string
+		expectedPhone     string
+		expectedConflicts []dto.AirportConflict
+	}{
+		{
+			name:          "Upstream wins",
+			policy:        dto.MergePolicyUpstreamWins,
+			expectedPhone: upstreamPhone,
+		},
+		{
+			name:          "Manual wins",
+			policy:        dto.MergePolicyManualWins,
+			expectedPhone: manualPhone,
+		},
+		{
+			name:          "Flag conflict",
+			policy:        dto.MergePolicyFlagConflict,
+			expectedPhone: manualPhone,
+			expectedConflicts: []dto.AirportConflict{{AirportID: 1, ICAO: "KAVL", Field: "manager_phone",
+				ManualValue: &manualPhone, UpstreamValue: &upstreamPhone, SyncRunID: &runID}},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated []dto.Airport
+			var conflicts []dto.AirportConflict
+			repo := &IAirportRepositoryMock{
+				ClaimDueFunc: func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
+					return []dto.Airport{{ID: 1, ICAO: "KAVL", ManagerPhone: &manualPhone, City: &oldCity, Status: "PENDING",
+						FieldSources: dto.FieldSources{"manager_phone": dto.FieldSourceManual, "city": dto.FieldSourceUpstream}}}, nil
+				},
+				UpdateByICAOFunc: func(ctx context.Context, airports []dto.Airport) error {
+					updated = airports
+					return nil
+				},
+			}
+			conflictRepo := &IAirportConflictRepositoryMock{
+				UpsertFunc: func(ctx context.Context, c []dto.AirportConflict) error {
+					conflicts = c
+					return nil
+				},
+			}
+			airportService := &IAirportServiceMock{
+				FetchAirportDataFunc: func(ctx context.Context, icao string) (*dto.AirportDataResponse, error) {
+					return &dto.AirportDataResponse{"KAVL": []dto.Airport{{ICAO: "KAVL", ManagerPhone: &upstreamPhone, City: &newCity}}}, nil
+				},
+				InvalidateCacheFunc: func(ctx context.Context) error {
+					return nil
+				},
+			}
+
+			s := NewAviationSyncService(log, repo, newSyncRunRepositoryMock(), conflictRepo, airportService, newMockRedis(), newSyncPolicy(tt.policy))
+			if _, err := s.Sync(context.Background(), dto.SyncOptions{Trigger: dto.SyncTriggerAPI}); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(updated) != 1 || updated[0].ID != 1 || updated[0].Status != "DONE" {
+				t.Fatalf("Expected airport 1 updated to DONE, got %+v", updated)
+			}
+			if *updated[0].ManagerPhone != tt.expectedPhone {
+				t.Errorf("Expected manager phone %q, got %q", tt.expectedPhone, *updated[0].ManagerPhone)
+			}
+			if *updated[0].City != newCity {
+				t.Errorf("Expected upstream city %q, got %q", newCity, *updated[0].City)
+			}
+			if !reflect.DeepEqual(conflicts, tt.expectedConflicts) {
+				t.Errorf("Expected conflicts %+v, got %+v", tt.expectedConflicts, conflicts)
+			}
+		})
+	}
+}
+
+func TestAviationSyncService_SyncStale(t *testing.T) {
+	oldPhone, newPhone := "828-000-0000", "828-684-2226"
+	city := "ASHEVILLE"
+	noData := "No airport data returned by AviationAPI"
+	var syncedBefore time.Time
+	var mode string
+	var updated []dto.Airport
+	var items []dto.SyncRunItem
+	syncRunRepo := newSyncRunRepositoryMock()
+	syncRunRepo.CreateFunc = func(ctx context.Context, run *dto.SyncRun) (*dto.SyncRun, error) {
+		mode = run.Mode
+		return &dto.SyncRun{ID: 1, Trigger: run.Trigger, Mode: run.Mode, Status: run.Status}, nil
+	}
+	syncRunRepo.InsertItemsFunc = func(ctx context.Context, runItems []dto.SyncRunItem) error {
+		items = append(items, runItems...)
+		return nil
+	}
+	repo := &IAirportRepositoryMock{
+		GetStaleFunc: func(ctx context.Context, before time.Time) ([]dto.Airport, error) {
+			syncedBefore = before
+			return []dto.Airport{
+				{ID: 1, ICAO: "KAVL", City: &city, ManagerPhone: &oldPhone, Status: "DONE"},
+				{ID: 2, ICAO: "KLAX", Status: "DONE"},
+			}, nil
+		},
+		UpdateByICAOFunc: func(ctx context.Context, airports []dto.Airport) error {
+			updated = airports
+			return nil
+		},
+	}
+	airportService := &IAirportServiceMock{
+		FetchAirportDataFunc: func(ctx context.Context, icao string) (*dto.AirportDataResponse, error) {
+			return &dto.AirportDataResponse{
+				"KAVL": []dto.Airport{{ICAO: "KAVL", City: &city, ManagerPhone: &newPhone}},
+				"KLAX": []dto.Airport{},
+			}, nil
+		},
+		InvalidateCacheFunc: func(ctx context.Context) error {
+			return nil
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	s := NewAviationSyncService(log, repo, syncRunRepo, &IAirportConflictRepositoryMock{}, airportService, newMockRedis(), SyncPolicy{MergePolicy: dto.MergePolicyManualWins, StaleMaxAge: 24 * time.Hour, MaxAttempts: 3})
+	resp, err := s.Sync(context.Background(), dto.SyncOptions{Trigger: dto.SyncTriggerCron, Mode: dto.SyncModeStale})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mode != dto.SyncModeStale || resp.Mode != dto.SyncModeStale {
+		t.Errorf("Expected stale run, got run mode %q and response mode %q", mode, resp.Mode)
+	}
+	if age := time.Since(syncedBefore); age < 24*time.Hour || age > 24*time.Hour+time.Minute {
+		t.Errorf("Expected airports synced before a day ago, got %v", syncedBefore)
+	}
+	if len(updated) != 1 || updated[0].ICAO != "KAVL" || *updated[0].ManagerPhone != newPhone {
+		t.Errorf("Expected only KAVL updated with the new phone, got %+v", updated)
+	}
+	if resp.Success != 1 || resp.Failed != 1 {
+		t.Errorf("Expected 1 success and 1 failed, got %+v", resp)
+	}
+
+	expected := map[string]dto.SyncRunItem{
+		"KAVL": {SyncRunID: 1, ICAO: "KAVL", Outcome: dto.SyncOutcomeOK, Status: "DONE",
+			Changes: dto.FieldChanges{{Field: "manager_phone", Before: &oldPhone, After: &newPhone}}},
+		"KLAX": {SyncRunID: 1, ICAO: "KLAX", Outcome: dto.SyncOutcomeMissing, Status: "DONE", Error: &noData},
+	}
+	got := map[string]dto.SyncRunItem{}
+	for _, item := range items {
+		got[item.ICAO] = item
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected items %+v, got %+v", expected, got)
+	}
+}
+
+func TestAviationSyncService_SyncFailed(t *testing.T) {
+	lastError := "No airport data returned by AviationAPI"
+	var status string
+	var updated []dto.Airport
+	repo := &IAirportRepositoryMock{
+		GetByStatusFunc: func(ctx context.Context, s string) ([]dto.Airport, error) {
+			status = s
+			return []dto.Airport{
+				{ID: 1, ICAO: "KAVL", Status: dto.AirportStatusFailed, AttemptCount: 5, LastError: &lastError},
+				{ID: 2, ICAO: "KLAX", Status: dto.AirportStatusFailed, AttemptCount: 5, LastError: &lastError},
+			}, nil
+		},
+		UpdateByICAOFunc: func(ctx context.Context, airports []dto.Airport) error {
+			updated = airports
+			return nil
+		},
+	}
+	airportService := &IAirportServiceMock{
+		FetchAirportDataFunc: func(ctx context.Context, icao string) (*dto.AirportDataResponse, error) {
+			return &dto.AirportDataResponse{"KAVL": []dto.Airport{{ICAO: "KAVL"}}, "KLAX": []dto.Airport{}}, nil
+		},
+		InvalidateCacheFunc: func(ctx context.Context) error {
+			return nil
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	s := NewAviationSyncService(log, repo, newSyncRunRepositoryMock(), &IAirportConflictRepositoryMock{}, airportService, newMockRedis(), newSyncPolicy(dto.MergePolicyManualWins))
+	resp, err := s.Sync(context.Background(), dto.SyncOptions{Trigger: dto.SyncTriggerCron, Mode: dto.SyncModeFailed})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if status != dto.AirportStatusFailed {
+		t.Errorf("Expected FAILED airports loaded, got %q", status)
+	}
+	if len(updated) != 1 || updated[0].ICAO != "KAVL" || updated[0].Status != dto.AirportStatusDone {
+		t.Errorf("Expected only KAVL updated to DONE, got %+v", updated)
+	}
+	// KLAX is still without data and keeps its FAILED state untouched
+	if len(repo.UpdateSyncStateCalls()) != 0 {
+		t.Errorf("Expected no sync state update, got %+v", repo.UpdateSyncStateCalls())
+	}
+	if resp.Mode != dto.SyncModeFailed || resp.Success != 1 || resp.Failed != 1 {
+		t.Errorf("Expected failed mode with 1 success and 1 failed, got %+v", resp)
+	}
+}
+
+func TestAviationSyncService_SyncTargeted(t *testing.T) {
+	lastError := "API down"
+	stored := []dto.Airport{
+		{ID: 1, ICAO: "KADT", Status: dto.AirportStatusFailed, AttemptCount: 5, LastError: &lastError},
+		{ID: 2, ICAO: "KAIV", Status: dto.AirportStatusDone},
+	}
+	tests := []struct {
+		name            string
+		icaos           []string
+		expectedLookup  []string
+		expectedUpdated []string
+		expectedErr     error
+		expectRun       bool
+	}{
+		{
+			name:            "Success sync requested airports whatever their status",
+			icaos:           []string{"kadt", " KAIV", "KADT"},
+			expectedLookup:  []string{"KADT", "KAIV"},
+			expectedUpdated: []string{"KADT", "KAIV"},
+			expectRun:       true,
+		},
+		{
+			name:           "Error unknown ICAOs",
+			icaos:          []string{"KADT", "KXXX", "KAAA"},
+			expectedLookup: []string{"KAAA", "KADT", "KXXX"},
+			expectedErr:    fmt.Errorf("Airports not found: KAAA, KXXX"),
+			expectRun:      true,
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lookup, updated []string
+			var finished *dto.SyncRun
+			syncRunRepo := newSyncRunRepositoryMock()
+			syncRunRepo.FinishFunc = func(ctx context.Context, run *dto.SyncRun) error {
+				finished = run
+				return nil
+			}
+			repo := &IAirportRepositoryMock{
+				GetByICAOsFunc: func(ctx context.Context, icaos []string) ([]dto.Airport, error) {
+					lookup = append([]string{}, icaos...)
+					var found []dto.Airport
+					for _, airport := range stored {
+						for _, icao := range icaos {
+							if airport.ICAO == icao {
+								found = append(found, airport)
+							}
+						}
+					}
+					return found, nil
+				},
+				UpdateByICAOFunc: func(ctx context.Context, airports []dto.Airport) error {
+					for _, airport := range airports {
+						if airport.Status != dto.AirportStatusDone {
+							t.Errorf("Expected %s synced to DONE, got %s", airport.ICAO, airport.Status)
+						}
+						updated = append(updated, airport.ICAO)
+					}
+					return nil
+				},
+			}
+			airportService := &IAirportServiceMock{
+				FetchAirportDataFunc: func(ctx context.Context, icaos string) (*dto.AirportDataResponse, error) {
+					response := dto.AirportDataResponse{}
+					for _, icao := range strings.Split(icaos, ",") {
+						response[icao] = []dto.Airport{{ICAO: icao}}
+					}
+					return &response, nil
+				},
+				InvalidateCacheFunc: func(ctx context.Context) error {
+					return nil
+				},
+			}
+
+			s := NewAviationSyncService(log, repo, syncRunRepo, &IAirportConflictRepositoryMock{}, airportService, newMockRedis(), newSyncPolicy(dto.MergePolicyManualWins))
+			resp, err := s.Sync(context.Background(), dto.SyncOptions{Trigger: dto.SyncTriggerAPI, ICAOs: tt.icaos})
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			sort.Strings(lookup)
+			sort.Strings(updated)
+			if !reflect.DeepEqual(lookup, tt.expectedLookup) {
+				t.Errorf("Expected lookup of %v, got %v", tt.expectedLookup, lookup)
+			}
+			if !reflect.DeepEqual(updated, tt.expectedUpdated) {
+				t.Errorf("Expected updated %v, got %v", tt.expectedUpdated, updated)
+			}
+			if tt.expectedErr != nil {
+				if finished == nil || finished.Status != dto.SyncRunStatusFailed {
+					t.Errorf("Expected run finished FAILED, got %+v", finished)
+				}
+				return
+			}
+			if resp.Mode != dto.SyncModeTargeted || resp.Total != 2 || resp.Success != 2 {
+				t.Errorf("Expected targeted sync of 2 airports, got %+v", resp)
+			}
+		})
+	}
+}
+
+func TestAviationSyncService_RefreshAirport(t *testing.T) {
+	city := "ATWOOD"
+	before := &dto.Airport{ID: 1, ICAO: "KADT", Status: dto.AirportStatusFailed, AttemptCount: 5}
+	after := &dto.Airport{ID: 1, ICAO: "KADT", City: &city, Status: dto.AirportStatusDone}
+	tests := []struct {
+		name           string
+		stored         []*dto.Airport
+		held           bool
+		expectedResult *dto.AirportRefresh
+		expectedErr    error
+	}{
+		{
+			name:   "Success refresh airport",
+			stored: []*dto.Airport{before, after},
+			expectedResult: &dto.AirportRefresh{Before: before, After: after,
+				Sync: &dto.SyncResponse{RunID: 1, Mode: dto.SyncModeTargeted, Total: 1, Success: 1}},
+		},
+		{
+			name:   "No airport found",
+			stored: []*dto.Airport{nil},
+		},
+		{
+			name:        "Error sync in progress",
+			stored:      []*dto.Airport{before},
+			held:        true,
+			expectedErr: fmt.Errorf("Sync already in progress"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reads := 0
+			repo := &IAirportRepositoryMock{
+				GetByIdFunc: func(ctx context.Context, id int) (*dto.Airport, error) {
+					airport := tt.stored[reads]
+					reads++
+					return airport, nil
+				},
+				GetByICAOsFunc: func(ctx context.Context, icaos []string) ([]dto.Airport, error) {
+					return []dto.Airport{*before}, nil
+				},
+				UpdateByICAOFunc: func(ctx context.Context, airports []dto.Airport) error {
+					return nil
+				},
+			}
+			airportService := &IAirportServiceMock{
+				FetchAirportDataFunc: func(ctx context.Context, icaos string) (*dto.AirportDataResponse, error) {
+					return &dto.AirportDataResponse{"KADT": []dto.Airport{{ICAO: "KADT", City: &city}}}, nil
+				},
+				InvalidateCacheFunc: func(ctx context.Context) error {
+					return nil
+				},
+			}
+			syncRunRepo := newSyncRunRepositoryMock()
+			syncRunRepo.GetRunningFunc = func(ctx context.Context) (*dto.SyncRun, error) {
+				return nil, nil
+			}
+			redisClient := newMockRedis()
+			if tt.held {
+				redisClient.Store["sync:lock"] = "other-owner"
+			}
+
+			s := NewAviationSyncService(log, repo, syncRunRepo, &IAirportConflictRepositoryMock{}, airportService, redisClient, newSyncPolicy(dto.MergePolicyManualWins))
+			got, err := s.RefreshAirport(context.Background(), 1)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedResult) {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+		})
+	}
+}
+
+func TestAviationSyncService_SyncRetry(t *testing.T) {
+	noData, missing, apiDown := "No airport data returned by AviationAPI", "Airport missing from the AviationAPI response", "API down"
+	unavailable := "Upstream responded with status 503"
+	claimed := []dto.Airport{
+		{ID: 1, ICAO: "KAVL", Status: dto.AirportStatusSyncing},
+		{ID: 2, ICAO: "KLAX", Status: dto.AirportStatusSyncing, AttemptCount: 2},
+		{ID: 3, ICAO: "KJFK", Status: dto.AirportStatusSyncing, AttemptCount: 1},
+	}
+	tests := []struct {
+		name           string
+		response       *dto.AirportDataResponse
+		fetchErr       error
+		expectedStates map[string]dto.Airport
+		expectedDelay  map[string]time.Duration
+		// expectedHTTPStatus is recorded on every item of the run, 0 for none
+		expectedHTTPStatus int
+	}{
+		{
+			name: "Retry with backoff until attempts are used up",
+			response: &dto.AirportDataResponse{
+				"KAVL": []dto.Airport{},
+				"KJFK": []dto.Airport{{ICAO: "KJFK"}},
+			},
+			expectedStates: map[string]dto.Airport{
+				"KAVL": {ICAO: "KAVL", Status: dto.AirportStatusRetrying, AttemptCount: 1, LastError: &noData},
+				"KLAX": {ICAO: "KLAX", Status: dto.AirportStatusFailed, AttemptCount: 3, LastError: &missing},
+			},
+			expectedDelay: map[string]time.Duration{"KAVL": 10 * time.Minute},
+		},
+		{
+			name:     "Retry the whole batch on fetch error",
+			fetchErr: fmt.Errorf("API down"),
+			expectedStates: map[string]dto.Airport{
+				"KAVL": {ICAO: "KAVL", Status: dto.AirportStatusRetrying, AttemptCount: 1, LastError: &apiDown},
+				"KLAX": {ICAO: "KLAX", Status: dto.AirportStatusFailed, AttemptCount: 3, LastError: &apiDown},
+				"KJFK": {ICAO: "KJFK", Status: dto.AirportStatusRetrying, AttemptCount: 2, LastError: &apiDown},
+			},
+			// The second attempt of KJFK would wait 20 minutes, capped at the 15 minutes max delay
+			expectedDelay: map[string]time.Duration{"KAVL": 10 * time.Minute, "KJFK": 15 * time.Minute},
+		},
+		{
+			name:     "Record the status of a batch rejected by the upstream",
+			fetchErr: &client.StatusError{StatusCode: http.StatusServiceUnavailable},
+			expectedStates: map[string]dto.Airport{
+				"KAVL": {ICAO: "KAVL", Status: dto.AirportStatusRetrying, AttemptCount: 1, LastError: &unavailable},
+				"KLAX": {ICAO: "KLAX", Status: dto.AirportStatusFailed, AttemptCount: 3, LastError: &unavailable},
+				"KJFK": {ICAO: "KJFK", Status: dto.AirportStatusRetrying, AttemptCount: 2, LastError: &unavailable},
+			},
+			expectedDelay:      map[string]time.Duration{"KAVL": 10 * time.Minute, "KJFK": 15 * time.Minute},
+			expectedHTTPStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated []dto.Airport
+			states := map[string]dto.Airport{}
+			repo := &IAirportRepositoryMock{
+				ClaimDueFunc: func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
+					return append([]dto.Airport{}, claimed...), nil
+				},
+				UpdateByICAOFunc: func(ctx context.Context, airports []dto.Airport) error {
+					updated = airports
+					return nil
+				},
+				UpdateSyncStateFunc: func(ctx context.Context, airports []dto.Airport) error {
+					for _, apt := range airports {
+						states[apt.ICAO] = apt
+					}
+					return nil
+				},
+			}
+			airportService := &IAirportServiceMock{
+				FetchAirportDataFunc: func(ctx context.Context, icao string) (*dto.AirportDataResponse, error) {
+					return tt.response, tt.fetchErr
+				},
+				InvalidateCacheFunc: func(ctx context.Context) error {
+					return nil
+				},
+			}
+
+			var items []dto.SyncRunItem
+			syncRunRepo := newSyncRunRepositoryMock()
+			syncRunRepo.InsertItemsFunc = func(ctx context.Context, runItems []dto.SyncRunItem) error {
+				items = append(items, runItems...)
+				return nil
+			}
+
+			policy := SyncPolicy{MergePolicy: dto.MergePolicyManualWins, MaxAttempts: 3, RetryBaseDelay: 10 * time.Minute, RetryMaxDelay: 15 * time.Minute}
+			s := NewAviationSyncService(log, repo, syncRunRepo, &IAirportConflictRepositoryMock{}, airportService, newMockRedis(), policy)
+			if _, err := s.Sync(context.Background(), dto.SyncOptions{Trigger: dto.SyncTriggerAPI}); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			for icao, state := range states {
+				if delay, ok := tt.expectedDelay[icao]; ok {
+					if state.NextAttemptAt == nil || time.Until(*state.NextAttemptAt) > delay || time.Until(*state.NextAttemptAt) < delay-time.Minute {
+						t.Errorf("Expected %s next attempt in %v, got %v", icao, delay, state.NextAttemptAt)
+					}
+				} else if state.NextAttemptAt != nil {
+					t.Errorf("Expected no next attempt for %s, got %v", icao, state.NextAttemptAt)
+				}
+				state.NextAttemptAt = nil
+				states[icao] = state
+			}
+			if !reflect.DeepEqual(states, tt.expectedStates) {
+				t.Errorf("Expected states %+v, got %+v", tt.expectedStates, states)
+			}
+			if tt.fetchErr == nil && (len(updated) != 1 || updated[0].ICAO != "KJFK" || updated[0].Status != dto.AirportStatusDone) {
+				t.Errorf("Expected KJFK updated to DONE, got %+v", updated)
+			}
+			for _, item := range items {
+				if (item.HTTPStatus == nil && tt.expectedHTTPStatus != 0) || (item.HTTPStatus != nil && *item.HTTPStatus != tt.expectedHTTPStatus) {
+					t.Errorf("Expected %s HTTP status %d, got %v", item.ICAO, tt.expectedHTTPStatus, item.HTTPStatus)
+				}
+			}
+		})
+	}
+}
+
+func TestAviationSyncService_SyncLocked(t *testing.T) {
+	running := &dto.SyncRun{ID: 4, Trigger: dto.SyncTriggerCron, Status: dto.SyncRunStatusRunning}
+	tests := []struct {
+		name        string
+		store       map[string]string
+		running     *dto.SyncRun
+		expectedErr error
+		expectedRun *dto.SyncRun
+	}{
+		{
+			name:        "Sync in progress",
+			store:       map[string]string{"sync:lock": "other-owner"},
+			running:     running,
+			expectedErr: fmt.Errorf("Sync run 4 already in progress"),
+			expectedRun: running,
+		},
+		{
+			name:        "Sync in progress without running run",
+			store:       map[string]string{"sync:lock": "other-owner"},
+			expectedErr: fmt.Errorf("Sync already in progress"),
+		},
+		{
+			name:  "Lock released after the sync",
+			store: map[string]string{},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisClient := &MockRedis{Store: tt.store}
+			syncRunRepo := newSyncRunRepositoryMock()
+			syncRunRepo.GetRunningFunc = func(ctx context.Context) (*dto.SyncRun, error) {
+				return tt.running, nil
+			}
+			repo := &IAirportRepositoryMock{
+				ClaimDueFunc: func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
+					if _, ok := redisClient.Store["sync:lock"]; !ok {
+						t.Error("Expected the sync lock held while syncing")
+					}
+					return nil, nil
+				},
+			}
+
+			s := NewAviationSyncService(log, repo, syncRunRepo, &IAirportConflictRepositoryMock{}, &IAirportServiceMock{}, redisClient, newSyncPolicy(dto.MergePolicyManualWins))
+			_, err := s.Sync(context.Background(), dto.SyncOptions{Trigger: dto.SyncTriggerAPI})
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			var inProgress *SyncInProgressError
+			if tt.expectedErr != nil {
+				if !errors.As(err, &inProgress) || inProgress.Run != tt.expectedRun {
+					t.Errorf("Expected SyncInProgressError with run %+v, got %#v", tt.expectedRun, err)
+				}
+				if len(syncRunRepo.CreateCalls()) != 0 {
+					t.Error("Expected no sync run created while another sync runs")
+				}
+				return
+			}
+			if _, ok := redisClient.Store["sync:lock"]; ok {
+				t.Error("Expected the sync lock released")
+			}
+		})
+	}
+}
+
+func TestAviationSyncService_SyncJob(t *testing.T) {
+	// One batch more than there are workers, so a batch is still queued when the job is cancelled
+	var airports []dto.Airport
+	for i := 0; i < 11*30; i++ {
+		airports = append(airports, dto.Airport{ICAO: fmt.Sprintf("K%03d", i), Status: dto.AirportStatusSyncing})
+	}
+	airports[330-1].AttemptCount = 2
+
+	started := make(chan struct{}, 11)
+	release := make(chan struct{})
+	finished := make(chan *dto.SyncRun, 1)
+	var mu sync.Mutex
+	states := map[string]dto.Airport{}
+	var items []dto.SyncRunItem
+
+	repo := &IAirportRepositoryMock{
+		ClaimDueFunc: func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
+			return airports, nil
+		},
+		UpdateSyncStateFunc: func(ctx context.Context, airports []dto.Airport) error {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, airport := range airports {
+				states[airport.ICAO] = airport
+			}
+			return nil
+		},
+	}
+	airportService := &IAirportServiceMock{
+		FetchAirportDataFunc: func(ctx context.Context, icaos string) (*dto.AirportDataResponse, error) {
+			started <- struct{}{}
+			<-release
+			return nil, fmt.Errorf("Fetch error")
+		},
+		InvalidateCacheFunc: func(ctx context.Context) error {
+			return nil
+		},
+	}
+	syncRunRepo := newSyncRunRepositoryMock()
+	syncRunRepo.InsertItemsFunc = func(ctx context.Context, batchItems []dto.SyncRunItem) error {
+		mu.Lock()
+		defer mu.Unlock()
+		items = append(items, batchItems...)
+		return nil
+	}
+	syncRunRepo.FinishFunc = func(ctx context.Context, run *dto.SyncRun) error {
+		finished <- run
+		return nil
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	redisClient := newMockRedis()
+	s := NewAviationSyncService(log, repo, syncRunRepo, &IAirportConflictRepositoryMock{}, airportService, redisClient, newSyncPolicy(dto.MergePolicyManualWins))
+
+	requestCtx, cancelRequest := context.WithCancel(context.Background())
+	job, err := s.StartSync(requestCtx, dto.SyncOptions{Trigger: dto.SyncTriggerAPI})
+	if err != nil {
+		t.Fatalf("Expected job started, got %v", err)
+	}
+	// The job must outlive the request that started it
+	cancelRequest()
+
+	expected := &dto.SyncJob{ID: 1, Trigger: dto.SyncTriggerAPI, Mode: dto.SyncModePending, Status: dto.SyncRunStatusRunning, Total: 330, Batches: 11}
+	if !reflect.DeepEqual(job, expected) {
+		t.Errorf("Expected job %+v, got %+v", expected, job)
+	}
+
+	for i := 0; i < 10; i++ {
+		<-started
+	}
+	if got, err := s.GetSyncJob(context.Background(), 1); err != nil || got.Status != dto.SyncRunStatusRunning || got.BatchesDone != 0 {
+		t.Errorf("Expected running job without batches done, got %+v, %v", got, err)
+	}
+
+	cancelled, cancelCtx := context.WithCancel(context.Background())
+	cancelCtx()
+	if _, err := s.CancelSyncJob(cancelled, 1); err != nil {
+		t.Fatalf("Expected job cancelled, got %v", err)
+	}
+	close(release)
+
+	run := <-finished
+	if run.Status != dto.SyncRunStatusCancelled || run.ErrorMessage == nil || *run.ErrorMessage != "Sync cancelled" {
+		t.Errorf("Expected run CANCELLED with Sync cancelled, got %+v", run)
+	}
+	if run.Error != 10 {
+		t.Errorf("Expected 10 failed batches, got %d", run.Error)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	cancelledItems := 0
+	for _, item := range items {
+		if item.Outcome == dto.SyncOutcomeCancelled {
+			cancelledItems++
+		}
+	}
+	if cancelledItems != 30 {
+		t.Errorf("Expected the queued batch recorded as 30 CANCELLED items, got %d", cancelledItems)
+	}
+	if state := states["K329"]; state.Status != dto.AirportStatusRetrying || state.AttemptCount != 2 || state.NextAttemptAt == nil {
+		t.Errorf("Expected a released retried airport due again without an attempt used, got %+v", state)
+	}
+	if state := states["K300"]; state.Status != dto.AirportStatusPending || state.AttemptCount != 0 {
+		t.Errorf("Expected a released airport PENDING again, got %+v", state)
+	}
+	if state := states["K000"]; state.Status != dto.AirportStatusRetrying || state.AttemptCount != 1 {
+		t.Errorf("Expected an airport of a failed batch RETRYING, got %+v", state)
+	}
+}
+
+func TestAviationSyncService_SyncTimeout(t *testing.T) {
+	repo := &IAirportRepositoryMock{
+		ClaimDueFunc: func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
+			return []dto.Airport{{ICAO: "KAVL", Status: dto.AirportStatusSyncing}}, nil
+		},
+		UpdateSyncStateFunc: func(ctx context.Context, airports []dto.Airport) error {
+			return nil
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	// The batch in flight is finished after the deadline, it fails once the job timed out
+	airportService := &IAirportServiceMock{
+		FetchAirportDataFunc: func(_ context.Context, icaos string) (*dto.AirportDataResponse, error) {
+			<-ctx.Done()
+			return nil, fmt.Errorf("Fetch error")
+		},
+		InvalidateCacheFunc: func(ctx context.Context) error {
+			return nil
+		},
+	}
+	syncRunRepo := newSyncRunRepositoryMock()
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	s := NewAviationSyncService(log, repo, syncRunRepo, &IAirportConflictRepositoryMock{}, airportService, newMockRedis(), newSyncPolicy(dto.MergePolicyManualWins))
+
+	syncResponse, err := s.Sync(ctx, dto.SyncOptions{Trigger: dto.SyncTriggerCron})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline as error, got %v", err)
+	}
+	if syncResponse == nil || syncResponse.RunID != 1 || syncResponse.Total != 1 {
+		t.Errorf("Expected the response of the stopped run, got %+v", syncResponse)
+	}
+	finishCalls := syncRunRepo.FinishCalls()
+	if len(finishCalls) != 1 || finishCalls[0].Run.Status != dto.SyncRunStatusFailed {
+		t.Errorf("Expected run FAILED, got %+v", finishCalls)
+	}
+}
+
+func TestAviationSyncService_Shutdown(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan *dto.SyncRun, 1)
+
+	repo := &IAirportRepositoryMock{
+		ClaimDueFunc: func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
+			return []dto.Airport{{ICAO: "KAVL", Status: dto.AirportStatusSyncing}}, nil
+		},
+		UpdateSyncStateFunc: func(ctx context.Context, airports []dto.Airport) error {
+			return nil
+		},
+	}
+	airportService := &IAirportServiceMock{
+		FetchAirportDataFunc: func(ctx context.Context, icaos string) (*dto.AirportDataResponse, error) {
+			close(started)
+			<-release
+			return nil, fmt.Errorf("Fetch error")
+		},
+		InvalidateCacheFunc: func(ctx context.Context) error {
+			return nil
+		},
+	}
+	syncRunRepo := newSyncRunRepositoryMock()
+	syncRunRepo.FinishFunc = func(ctx context.Context, run *dto.SyncRun) error {
+		finished <- run
+		return nil
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	s := NewAviationSyncService(log, repo, syncRunRepo, &IAirportConflictRepositoryMock{}, airportService, newMockRedis(), newSyncPolicy(dto.MergePolicyManualWins))
+
+	if _, err := s.StartSync(context.Background(), dto.SyncOptions{Trigger: dto.SyncTriggerAPI}); err != nil {
+		t.Fatalf("Expected job started, got %v", err)
+	}
+	<-started
+
+	stopped := make(chan struct{})
+	go func() {
+		s.Shutdown(context.Background())
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("Expected Shutdown to wait for the running job")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Shutdown to return once the job finished")
+	}
+	if run := <-finished; run.Status != dto.SyncRunStatusCancelled {
+		t.Errorf("Expected run CANCELLED, got %+v", run)
+	}
+}
+
+func TestAviationSyncService_GetSyncJob(t *testing.T) {
+	started := time.Date(2026, 1, 2, 5, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		run            *dto.SyncRun
+		runErr         error
+		cancel         bool
+		expectedResult *dto.SyncJob
+		expectedStore  map[string]string
+		expectedErr    error
+	}{
+		{
+			name: "Success job of a finished run",
+			run: &dto.SyncRun{ID: 2, Trigger: dto.SyncTriggerCron, Mode: dto.SyncModePending, Status: dto.SyncRunStatusDone, StartedAt: started,
+				Total: 45, Success: 44, Failed: 1, Items: []dto.SyncRunItem{
+					{Batch: 0, ICAO: "KAVL", Outcome: dto.SyncOutcomeOK},
+					{Batch: 0, ICAO: "KLAX", Outcome: dto.SyncOutcomeOK},
+					{Batch: 1, ICAO: "KJFK", Outcome: dto.SyncOutcomeMissing},
+				}},
+			expectedResult: &dto.SyncJob{ID: 2, Trigger: dto.SyncTriggerCron, Mode: dto.SyncModePending, Status: dto.SyncRunStatusDone, StartedAt: started,
+				Total: 45, Batches: 2, BatchesDone: 2, Success: 44, Failed: 1},
+		},
+		{
+			name:           "Cancel finished job",
+			run:            &dto.SyncRun{ID: 2, Status: dto.SyncRunStatusFailed, Items: []dto.SyncRunItem{{Batch: 0, Outcome: dto.SyncOutcomeCancelled}}},
+			cancel:         true,
+			expectedResult: &dto.SyncJob{ID: 2, Status: dto.SyncRunStatusFailed},
+		},
+		{
+			name:           "Cancel job running in another process",
+			run:            &dto.SyncRun{ID: 2, Status: dto.SyncRunStatusRunning},
+			cancel:         true,
+			expectedResult: &dto.SyncJob{ID: 2, Status: dto.SyncRunStatusRunning},
+			expectedStore:  map[string]string{"sync:cancel:2": "1"},
+		},
+		{
+			name: "No job found",
+		},
+		{
+			name:        "Error get sync run",
+			runErr:      fmt.Errorf("Failed to get sync run"),
+			expectedErr: fmt.Errorf("Failed to get sync run"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			syncRunRepo := &ISyncRunRepositoryMock{
+				GetByIdFunc: func(ctx context.Context, id int) (*dto.SyncRun, error) {
+					return tt.run, tt.runErr
+				},
+			}
+			redisClient := newMockRedis()
+			s := NewAviationSyncService(log, &IAirportRepositoryMock{}, syncRunRepo, &IAirportConflictRepositoryMock{}, &IAirportServiceMock{}, redisClient, newSyncPolicy(dto.MergePolicyManualWins))
+
+			var got *dto.SyncJob
+			var err error
+			if tt.cancel {
+				got, err = s.CancelSyncJob(context.Background(), 2)
+			} else {
+				got, err = s.GetSyncJob(context.Background(), 2)
+			}
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedResult) {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+			expectedStore := tt.expectedStore
+			if expectedStore == nil {
+				expectedStore = map[string]string{}
+			}
+			if !reflect.DeepEqual(redisClient.Store, expectedStore) {
+				t.Errorf("Expected redis store %v, got %v", expectedStore, redisClient.Store)
+			}
+		})
+	}
+}
+
+func TestAviationSyncService_CancelSyncJobInAnotherProcess(t *testing.T) {
+	// One batch more than there are workers, the queued batch is started after the cancellation was requested
+	var airports []dto.Airport
+	for i := 0; i < 11*30; i++ {
+		airports = append(airports, dto.Airport{ICAO: fmt.Sprintf("K%03d", i), Status: dto.AirportStatusSyncing})
+	}
+
+	started := make(chan struct{}, 11)
+	release := make(chan struct{})
+	finished := make(chan *dto.SyncRun, 1)
+
+	repo := &IAirportRepositoryMock{
+		ClaimDueFunc: func(ctx context.Context, leaseUntil time.Time) ([]dto.Airport, error) {
+			return airports, nil
+		},
+		UpdateSyncStateFunc: func(ctx context.Context, airports []dto.Airport) error {
+			return nil
+		},
+	}
+	airportService := &IAirportServiceMock{
+		FetchAirportDataFunc: func(ctx context.Context, icaos string) (*dto.AirportDataResponse, error) {
+			started <- struct{}{}
+			<-release
+			return nil, fmt.Errorf("Fetch error")
+		},
+		InvalidateCacheFunc: func(ctx context.Context) error {
+			return nil
+		},
+	}
+	syncRunRepo := newSyncRunRepositoryMock()
+	syncRunRepo.FinishFunc = func(ctx context.Context, run *dto.SyncRun) error {
+		finished <- run
+		return nil
+	}
+	otherSyncRunRepo := &ISyncRunRepositoryMock{
+		GetByIdFunc: func(ctx context.Context, id int) (*dto.SyncRun, error) {
+			return &dto.SyncRun{ID: id, Status: dto.SyncRunStatusRunning}, nil
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	// Both processes share Redis
+	redisClient := newMockRedis()
+	s := NewAviationSyncService(log, repo, syncRunRepo, &IAirportConflictRepositoryMock{}, airportService, redisClient, newSyncPolicy(dto.MergePolicyManualWins))
+	other := NewAviationSyncService(log, &IAirportRepositoryMock{}, otherSyncRunRepo, &IAirportConflictRepositoryMock{}, &IAirportServiceMock{}, redisClient, newSyncPolicy(dto.MergePolicyManualWins))
+
+	if _, err := s.StartSync(context.Background(), dto.SyncOptions{Trigger: dto.SyncTriggerCron}); err != nil {
+		t.Fatalf("Expected job started, got %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		<-started
+	}
+
+	job, err := other.CancelSyncJob(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected cancellation requested, got %v", err)
+	}
+	if job.Status != dto.SyncRunStatusRunning {
+		t.Errorf("Expected the job returned still running, got %+v", job)
+	}
+	close(release)
+
+	run := <-finished
+	if run.Status != dto.SyncRunStatusCancelled {
+		t.Errorf("Expected run CANCELLED, got %+v", run)
+	}
+	if run.Error != 10 || len(started) != 0 {
+		t.Errorf("Expected only the 10 started batches fetched, got %d failed and %d more started", run.Error, len(started))
+	}
+}
+
+func TestAviationSyncService_GetAllSyncRun(t *testing.T) {
+	tests := []struct {
+		name           string
+		syncRunRepo    repository.ISyncRunRepository
+		expectedResult []dto.SyncRun
+		expectedErr    error
+	}{
+		{
+			name: "Success get all sync runs",
+			syncRunRepo: &ISyncRunRepositoryMock{
+				GetAllFunc: func(ctx context.Context, limit, offset int) ([]dto.SyncRun, error) {
+					return []dto.SyncRun{{ID: 2, Trigger: dto.SyncTriggerAPI}, {ID: 1, Trigger: dto.SyncTriggerCron}}, nil
+				},
+			},
+			expectedResult: []dto.SyncRun{{ID: 2, Trigger: dto.SyncTriggerAPI}, {ID: 1, Trigger: dto.SyncTriggerCron}},
+		},
+		{
+			name: "Error get all sync runs",
+			syncRunRepo: &ISyncRunRepositoryMock{
+				GetAllFunc: func(ctx context.Context, limit, offset int) ([]dto.SyncRun, error) {
+					return nil, fmt.Errorf("Failed to get all sync runs")
+				},
+			},
+			expectedErr: fmt.Errorf("Failed to get all sync runs"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewAviationSyncService(log, &IAirportRepositoryMock{}, tt.syncRunRepo, &IAirportConflictRepositoryMock{}, &IAirportServiceMock{}, newMockRedis(), newSyncPolicy(dto.MergePolicyManualWins))
+
+			got, err := s.GetAllSyncRun(context.Background(), 10, 0)
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedResult) {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+		})
+	}
+}
+
+func TestAviationSyncService_GetSyncRun(t *testing.T) {
+	tests := []struct {
+		name           string
+		syncRunRepo    repository.ISyncRunRepository
+		expectedResult *dto.SyncRun
+		expectedErr    error
+	}{
+		{
+			name: "Success get sync run",
+			syncRunRepo: &ISyncRunRepositoryMock{
+				GetByIdFunc: func(ctx context.Context, id int) (*dto.SyncRun, error) {
+					return &dto.SyncRun{ID: id, Items: []dto.SyncRunItem{{ICAO: "KAVL", Outcome: dto.SyncOutcomeOK}}}, nil
+				},
+			},
+			expectedResult: &dto.SyncRun{ID: 1, Items: []dto.SyncRunItem{{ICAO: "KAVL", Outcome: dto.SyncOutcomeOK}}},
+		},
+		{
+			name: "Error get sync run",
+			syncRunRepo: &ISyncRunRepositoryMock{
+				GetByIdFunc: func(ctx context.Context, id int) (*dto.SyncRun, error) {
+					return nil, fmt.Errorf("Failed to get sync run")
+				},
+			},
+			expectedErr: fmt.Errorf("Failed to get sync run"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewAviationSyncService(log, &IAirportRepositoryMock{}, tt.syncRunRepo, &IAirportConflictRepositoryMock{}, &IAirportServiceMock{}, newMockRedis(), newSyncPolicy(dto.MergePolicyManualWins))
+
+			got, err := s.GetSyncRun(context.Background(), 1)
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedResult) {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+		})
+	}
+}
+
+func newSyncRunRepositoryMock() *ISyncRunRepositoryMock {
+	return &ISyncRunRepositoryMock{
+		CreateFunc: func(ctx context.Context, run *dto.SyncRun) (*dto.SyncRun, error) {
+			return &dto.SyncRun{ID: 1, Trigger: run.Trigger, Mode: run.Mode, Status: run.Status}, nil
+		},
+		FinishFunc: func(ctx context.Context, run *dto.SyncRun) error {
+			return nil
+		},
+		InsertItemsFunc: func(ctx context.Context, items []dto.SyncRunItem) error {
+			return nil
+		},
+	}
+}
+
+func newSyncPolicy(mergePolicy string) SyncPolicy {
+	return SyncPolicy{
+		MergePolicy:    mergePolicy,
+		StaleMaxAge:    30 * 24 * time.Hour,
+		MaxAttempts:    5,
+		RetryBaseDelay: 15 * time.Minute,
+		RetryMaxDelay:  24 * time.Hour,
+	}
+}
+
+func newMockRedis() *MockRedis {
+	return &MockRedis{Store: map[string]string{}}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"aviation-service/config"
+	"aviation-service/internal/dto"
+	"aviation-service/internal/utils"
+	"aviation-service/pkg/redis"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+//go:generate moq -out ../mock/aviation_weather_service_mock.go -pkg=mock . IAviationWeatherService
+type IAviationWeatherService interface {
+	GetMetar(ctx context.Context, icao string) (*dto.Metar, error)
+	GetTaf(ctx context.Context, icao string) (*dto.Taf, error)
+}
+
+const (
+	metarIssueInterval = 1 * time.Hour
+	tafIssueInterval   = 6 * time.Hour
+	// Used when the next report is already overdue, so a late issue is picked up quickly
+	overdueReportTTL = 5 * time.Minute
+)
+
+type AviationWeatherService struct {
+	logger      *zap.SugaredLogger
+	cfg         config.Config
+	client      Client
+	redisClient redis.RedisClient
+}
+
+func NewAviationWeatherService(logger *zap.SugaredLogger, cfg config.Config, client Client, redisClient redis.RedisClient) *AviationWeatherService {
+	return &AviationWeatherService{
+		logger:      logger,
+		cfg:         cfg,
+		client:      client,
+		redisClient: redisClient,
+	}
+}
+
+func (s *AviationWeatherService) GetMetar(ctx context.Context, icao string) (*dto.Metar, error) {
+	ctx, span := tracer.Start(ctx, "AviationWeatherService.GetMetar", trace.WithAttributes(attribute.String("airport.icao", icao)))
+	defer span.End()
+
+	cacheKey := fmt.Sprintf("metar:%s", icao)
+	var metar dto.Metar
+	cacheErr := utils.GetStruct(s.redisClient, ctx, cacheKey, &metar)
+	cacheLookup(ctx, "metar", cacheErr == nil)
+	if cacheErr == nil {
+		s.logger.Infow("METAR cache hit", "icao", icao)
+		return &metar, nil
+	}
+	s.logger.Infow("No METAR from cache, fetching from API", "error", cacheErr)
+
+	raw, err := s.fetchReport(ctx, "metar", icao)
+	if err != nil {
+		return nil, err
+	}
+	if raw == "" {
+		return nil, nil
+	}
+
+	parsed, err := utils.ParseMetar(raw, time.Now())
+	if err != nil {
+		s.logger.Errorw("Error parsing METAR", "error", err, "icao", icao, "raw", raw)
+		return nil, err
+	}
+
+	if err := utils.SetStruct(s.redisClient, ctx, cacheKey, parsed, reportTTL(parsed.ObservedAt, metarIssueInterval)); err != nil {
+		s.logger.Errorw("Error to cache METAR", "error", err)
+	}
+	return parsed, nil
+}
+
+func (s *AviationWeatherService) GetTaf(ctx context.Context, icao string) (*dto.Taf, error) {
+	ctx, span := tracer.Start(ctx, "AviationWeatherService.GetTaf", trace.WithAttributes(attribute.String("airport.icao", icao)))
+	defer span.End()
+
+	cacheKey := fmt.Sprintf("taf:%s", icao)
+	var taf dto.Taf
+	cacheErr := utils.GetStruct(s.redisClient, ctx, cacheKey, &taf)
+	cacheLookup(ctx, "taf", cacheErr == nil)
+	if cacheErr == nil {
+		s.logger.Infow("TAF cache hit", "icao", icao)
+		return &taf, nil
+	}
+	s.logger.Infow("No TAF from cache, fetching from API", "error", cacheErr)
+
+	raw, err := s.fetchReport(ctx, "taf", icao)
+	if err != nil {
+		return nil, err
+	}
+	if raw == "" {
+		return nil, nil
+	}
+
+	parsed, err := utils.ParseTaf(raw, time.Now())
+	if err != nil {
+		s.logger.Errorw("Error parsing TAF", "error", err, "icao", icao, "raw", raw)
+		return nil, err
+	}
+
+	if err := utils.SetStruct(s.redisClient, ctx, cacheKey, parsed, reportTTL(parsed.IssuedAt, tafIssueInterval)); err != nil {
+		s.logger.Errorw("Error to cache TAF", "error", err)
+	}
+	return parsed, nil
+}
+
+func (s *AviationWeatherService) fetchReport(ctx context.Context, report, icao string) (string, error) {
+	s.logger.Infow("Fetching aviation weather", "report", report, "icao", icao)
+	params := url.Values{}
+	params.Add("apt", icao)
+	resp, err := s.client.Get(ctx, s.cfg.AIRPORT_API_URL+"/weather/"+report+"?"+params.Encode())
+	if err != nil {
+		s.logger.Errorw("Error fetching aviation weather", "error", err, "report", report)
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		s.logger.Errorw("Unexpected status code from AviationAPI", "statusCode", resp.StatusCode, "report", report)
+		return "", fmt.Errorf("Unexpected status code %d from AviationAPI", resp.StatusCode)
+	}
+
+	var data dto.AviationWeatherDataResponse
+	if jsonErr := json.NewDecoder(resp.Body).Decode(&data); jsonErr != nil {
+		s.logger.Errorw("Error decoding body", "error", jsonErr)
+		return "", jsonErr
+	}
+	return data.Raw, nil
+}
+
+// reportTTL keeps a report cached until its successor is due, reports are issued on a fixed interval from the issue time
+func reportTTL(issuedAt time.Time, interval time.Duration) time.Duration {
+	ttl := time.Until(issuedAt.Add(interval))
+	if ttl < overdueReportTTL {
+		return overdueReportTTL
+	}
+	return ttl
+}
@@ -0,0 +1,201 @@
+package service_test
+
+import (
+	"aviation-service/config"
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/mock"
+	. "aviation-service/internal/service"
+	"aviation-service/internal/utils"
+	"aviation-service/pkg/logger"
+	r "aviation-service/pkg/redis"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAviationWeatherService_GetMetar(t *testing.T) {
+	raw := fmt.Sprintf("KAVL %sZ 36006KT 10SM FEW050 18/06 A3012 RMK AO2", time.Now().UTC().Format("021504"))
+	metar, _ := utils.ParseMetar(raw, time.Now())
+	cached, _ := json.Marshal(metar)
+
+	tests := []struct {
+		name           string
+		httpClient     *mockHTTPClient
+		redisClient    r.RedisClient
+		expectedResult *dto.Metar
+		expectedErr    error
+	}{
+		{
+			name: "Success with data (cache miss)",
+			httpClient: &mockHTTPClient{
+				response: fmt.Sprintf(`{"station_id":"KAVL","raw":%q}`, raw),
+			},
+			redisClient:    &MockRedis{Store: make(map[string]string)},
+			expectedResult: metar,
+		},
+		{
+			name:       "Success with data (cache hit)",
+			httpClient: &mockHTTPClient{},
+			redisClient: &MockRedis{Store: map[string]string{
+				"metar:KAVL": string(cached),
+			}},
+			expectedResult: metar,
+		},
+		{
+			name: "Success no METAR from API",
+			httpClient: &mockHTTPClient{
+				response: `{}`,
+			},
+			redisClient:    &MockRedis{Store: make(map[string]string)},
+			expectedResult: nil,
+		},
+		{
+			name: "Error fetching METAR",
+			httpClient: &mockHTTPClient{
+				err: fmt.Errorf("Error fetching METAR"),
+			},
+			redisClient: &MockRedis{Store: make(map[string]string)},
+			expectedErr: fmt.Errorf("Error fetching METAR"),
+		},
+		{
+			name: "Error unexpected status code",
+			httpClient: &mockHTTPClient{
+				response:   `<html>Service Unavailable</html>`,
+				statusCode: 503,
+			},
+			redisClient: &MockRedis{Store: make(map[string]string)},
+			expectedErr: fmt.Errorf("Unexpected status code 503 from AviationAPI"),
+		},
+		{
+			name: "Error decoding body",
+			httpClient: &mockHTTPClient{
+				response: `A`,
+			},
+			redisClient: &MockRedis{Store: make(map[string]string)},
+			expectedErr: fmt.Errorf("invalid character 'A' looking for beginning of value"),
+		},
+		{
+			name: "Error parsing METAR",
+			httpClient: &mockHTTPClient{
+				response: `{"station_id":"KAVL","raw":"KAVL 36006KT"}`,
+			},
+			redisClient: &MockRedis{Store: make(map[string]string)},
+			expectedErr: fmt.Errorf("Invalid report time format (expected DDHHMMZ)"),
+		},
+		{
+			name: "Error set to cache",
+			httpClient: &mockHTTPClient{
+				response: fmt.Sprintf(`{"station_id":"KAVL","raw":%q}`, raw),
+			},
+			redisClient:    &MockRedisSetError{},
+			expectedResult: metar,
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{AIRPORT_API_URL: "http://123"}
+			s := NewAviationWeatherService(log, cfg, tt.httpClient, tt.redisClient)
+
+			got, err := s.GetMetar(context.Background(), "KAVL")
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if got != nil && tt.expectedResult != nil {
+				if got.Raw != tt.expectedResult.Raw || !got.ObservedAt.Equal(tt.expectedResult.ObservedAt) ||
+					!reflect.DeepEqual(got.Conditions, tt.expectedResult.Conditions) || !reflect.DeepEqual(got.Altimeter, tt.expectedResult.Altimeter) {
+					t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+				}
+			} else if got != tt.expectedResult {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+		})
+	}
+}
+
+func TestAviationWeatherService_GetTaf(t *testing.T) {
+	now := time.Now().UTC()
+	raw := fmt.Sprintf("TAF KAVL %sZ %s/%s 36006KT P6SM SCT250 TEMPO %s/%s 3SM BR",
+		now.Format("021504"), now.Format("0215"), now.Add(24*time.Hour).Format("0215"),
+		now.Add(time.Hour).Format("0215"), now.Add(3*time.Hour).Format("0215"))
+	taf, _ := utils.ParseTaf(raw, now)
+	cached, _ := json.Marshal(taf)
+
+	tests := []struct {
+		name           string
+		httpClient     *mockHTTPClient
+		redisClient    r.RedisClient
+		expectedResult *dto.Taf
+		expectedErr    error
+	}{
+		{
+			name: "Success with data (cache miss)",
+			httpClient: &mockHTTPClient{
+				response: fmt.Sprintf(`{"station_id":"KAVL","raw":%q}`, raw),
+			},
+			redisClient:    &MockRedis{Store: make(map[string]string)},
+			expectedResult: taf,
+		},
+		{
+			name:       "Success with data (cache hit)",
+			httpClient: &mockHTTPClient{},
+			redisClient: &MockRedis{Store: map[string]string{
+				"taf:KAVL": string(cached),
+			}},
+			expectedResult: taf,
+		},
+		{
+			name: "Success no TAF from API",
+			httpClient: &mockHTTPClient{
+				response: `{}`,
+			},
+			redisClient:    &MockRedis{Store: make(map[string]string)},
+			expectedResult: nil,
+		},
+		{
+			name: "Error fetching TAF",
+			httpClient: &mockHTTPClient{
+				err: fmt.Errorf("Error fetching TAF"),
+			},
+			redisClient: &MockRedis{Store: make(map[string]string)},
+			expectedErr: fmt.Errorf("Error fetching TAF"),
+		},
+		{
+			name: "Error parsing TAF",
+			httpClient: &mockHTTPClient{
+				response: `{"station_id":"KAVL","raw":"TAF KAVL 161720Z 36006KT"}`,
+			},
+			redisClient: &MockRedis{Store: make(map[string]string)},
+			expectedErr: fmt.Errorf("Invalid TAF report, missing validity period"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{AIRPORT_API_URL: "http://123"}
+			s := NewAviationWeatherService(log, cfg, tt.httpClient, tt.redisClient)
+
+			got, err := s.GetTaf(context.Background(), "KAVL")
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if got != nil && tt.expectedResult != nil {
+				if got.Raw != tt.expectedResult.Raw || !got.ValidTo.Equal(tt.expectedResult.ValidTo) ||
+					len(got.Forecasts) != len(tt.expectedResult.Forecasts) {
+					t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+				}
+			} else if got != tt.expectedResult {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+		})
+	}
+}
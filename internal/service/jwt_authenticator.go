@@ -0,0 +1,329 @@
+package service
+
+import (
+	"aviation-service/config"
+	"aviation-service/internal/utils"
+	"aviation-service/pkg/middleware"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// A token signed with a key the JWKS does not list refetches it at most this often by default, so rotated keys are
+// picked up without letting forged key IDs or a down identity provider cost a fetch per request
+const jwksMinRefreshInterval = time.Minute
+
+// jwksFetchTimeout bounds a JWKS fetch, a hanging identity provider must not hold bearer token requests
+const jwksFetchTimeout = 10 * time.Second
+
+// Only asymmetric algorithms are accepted, a shared secret would let every service holding it mint tokens
+var jwtSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// JWTOptions select the JWKS that signs the tokens and how the scope claim maps to roles. JWKSFile is read once at
+// startup, JWKSURL is refetched every JWKSRefreshInterval
+type JWTOptions struct {
+	JWKSFile            string
+	JWKSURL             string
+	JWKSRefreshInterval time.Duration
+	// JWKSMinRefreshInterval is the least time between two fetches, one minute when zero
+	JWKSMinRefreshInterval time.Duration
+	Issuer                 string
+	Audience               string
+	ClockSkew              time.Duration
+	ScopeClaim             string
+	// RoleScopes maps every role to the scope that grants it
+	RoleScopes map[string]string
+}
+
+func JWTOptionsFromConfig(cfg config.Config) JWTOptions {
+	return JWTOptions{
+		JWKSFile:            cfg.AUTH_JWT_JWKS_FILE,
+		JWKSURL:             cfg.AUTH_JWT_JWKS_URL,
+		JWKSRefreshInterval: cfg.AUTH_JWT_JWKS_REFRESH_INTERVAL,
+		Issuer:              cfg.AUTH_JWT_ISSUER,
+		Audience:            cfg.AUTH_JWT_AUDIENCE,
+		ClockSkew:           cfg.AUTH_JWT_CLOCK_SKEW,
+		ScopeClaim:          cfg.AUTH_JWT_SCOPE_CLAIM,
+		RoleScopes: map[string]string{
+			middleware.RoleReader:   cfg.AUTH_JWT_READER_SCOPE,
+			middleware.RoleEditor:   cfg.AUTH_JWT_EDITOR_SCOPE,
+			middleware.RoleOperator: cfg.AUTH_JWT_OPERATOR_SCOPE,
+		},
+	}
+}
+
+// Enabled reports whether a JWKS is configured, without one bearer tokens are not accepted
+func (o JWTOptions) Enabled() bool {
+	return o.JWKSFile != "" || o.JWKSURL != ""
+}
+
+var errUnknownSigningKey = errors.New("unknown signing key")
+
+type JWTAuthenticator struct {
+	logger *zap.SugaredLogger
+	client Client
+	opts   JWTOptions
+	parser *jwt.Parser
+
+	mu   sync.RWMutex
+	keys map[string]utils.SigningKey
+	// fetchedAt is the last successful fetch, attemptedAt the last fetch started and fetchErr why it failed
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	fetchErr    error
+}
+
+// NewJWTAuthenticator loads the JWKS right away, so a missing file or an unreachable identity provider fails the startup
+func NewJWTAuthenticator(ctx context.Context, logger *zap.SugaredLogger, client Client, opts JWTOptions) (*JWTAuthenticator, error) {
+	if opts.JWKSMinRefreshInterval <= 0 {
+		opts.JWKSMinRefreshInterval = jwksMinRefreshInterval
+	}
+	a := &JWTAuthenticator{
+		logger: logger,
+		client: client,
+		opts:   opts,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(jwtSigningMethods),
+			jwt.WithIssuer(opts.Issuer),
+			jwt.WithAudience(opts.Audience),
+			jwt.WithLeeway(opts.ClockSkew),
+			jwt.WithExpirationRequired(),
+		),
+	}
+
+	if opts.JWKSFile != "" {
+		data, err := os.ReadFile(opts.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		keys, err := utils.ParseJWKS(data)
+		if err != nil {
+			return nil, err
+		}
+		a.keys = keys
+		a.fetchedAt = time.Now()
+		return a, nil
+	}
+	if err := a.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Authenticate resolves the caller from an Authorization: Bearer header. The role is the highest one granted by the
+// scopes of the token, a valid token without any of them gets no role and is refused by every route
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*middleware.Identity, error) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return nil, nil
+	}
+
+	ctx, span := tracer.Start(r.Context(), "JWTAuthenticator.Authenticate")
+	defer span.End()
+
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(strings.TrimSpace(token), claims, func(t *jwt.Token) (interface{}, error) {
+		return a.verificationKey(ctx, t)
+	})
+	if err != nil {
+		return nil, a.tokenError(err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, &middleware.CredentialsError{Reason: "Token has no subject"}
+	}
+	return &middleware.Identity{Subject: subject, Role: a.role(claims), Method: middleware.AuthMethodJWT}, nil
+}
+
+// tokenError tells the caller why its token was refused, a JWKS that could not be fetched is our failure and not theirs
+func (a *JWTAuthenticator) tokenError(err error) error {
+	var fetchErr *jwksFetchError
+	switch {
+	case errors.As(err, &fetchErr):
+		return fetchErr
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return &middleware.CredentialsError{Reason: "Token expired"}
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return &middleware.CredentialsError{Reason: "Token not valid yet"}
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return &middleware.CredentialsError{Reason: "Token issuer not accepted"}
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return &middleware.CredentialsError{Reason: "Token audience not accepted"}
+	case errors.Is(err, errUnknownSigningKey):
+		return &middleware.CredentialsError{Reason: "Token signing key not known"}
+	}
+	a.logger.Infow("Rejected bearer token", "error", err)
+	return &middleware.CredentialsError{Reason: "Invalid bearer token"}
+}
+
+// role returns the highest role one of the token scopes grants. The scope claim is a space separated string (OAuth)
+// or an array of strings (Azure AD scp, Keycloak roles)
+func (a *JWTAuthenticator) role(claims jwt.MapClaims) string {
+	var scopes []string
+	switch value := claims[a.opts.ScopeClaim].(type) {
+	case string:
+		scopes = strings.Fields(value)
+	case []interface{}:
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				scopes = append(scopes, s)
+			}
+		}
+	}
+
+	role := ""
+	for _, candidate := range []string{middleware.RoleReader, middleware.RoleEditor, middleware.RoleOperator} {
+		scope := a.opts.RoleScopes[candidate]
+		for _, s := range scopes {
+			if scope != "" && s == scope {
+				role = candidate
+			}
+		}
+	}
+	return role
+}
+
+func (a *JWTAuthenticator) verificationKey(ctx context.Context, t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	key, err := a.signingKey(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+	if key.Algorithm != "" && key.Algorithm != t.Method.Alg() {
+		return nil, fmt.Errorf("key %q is for %s, token is signed with %s", kid, key.Algorithm, t.Method.Alg())
+	}
+	return key.Key, nil
+}
+
+// signingKey looks the key ID up in the JWKS, a token without a key ID is only accepted when the set has one key
+func (a *JWTAuthenticator) signingKey(ctx context.Context, kid string) (utils.SigningKey, error) {
+	key, ok, fetchedAt := a.lookup(kid)
+	if a.opts.JWKSURL == "" {
+		if !ok {
+			return utils.SigningKey{}, errUnknownSigningKey
+		}
+		return key, nil
+	}
+
+	if ok && time.Since(fetchedAt) <= a.opts.JWKSRefreshInterval {
+		return key, nil
+	}
+	if !a.claimRefresh() {
+		// A fetch started less than JWKSMinRefreshInterval ago, failed or still running, another one is not due yet
+		if ok {
+			return key, nil
+		}
+		if err := a.lastFetchError(); err != nil {
+			return utils.SigningKey{}, err
+		}
+		return utils.SigningKey{}, errUnknownSigningKey
+	}
+
+	if err := a.refresh(ctx); err != nil {
+		// Keep verifying with the keys we have while the identity provider is down
+		if ok {
+			a.logger.Warnw("Failed to refresh JWKS, using the cached keys", "error", err)
+			return key, nil
+		}
+		return utils.SigningKey{}, err
+	}
+	if key, ok, _ = a.lookup(kid); !ok {
+		return utils.SigningKey{}, errUnknownSigningKey
+	}
+	return key, nil
+}
+
+func (a *JWTAuthenticator) lookup(kid string) (utils.SigningKey, bool, time.Time) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	key, ok := a.keys[kid]
+	if !ok && kid == "" && len(a.keys) == 1 {
+		for _, only := range a.keys {
+			key, ok = only, true
+		}
+	}
+	return key, ok, a.fetchedAt
+}
+
+// claimRefresh reserves the next fetch for the caller, it reports false while the last attempt, failed or still
+// running, is more recent than JWKSMinRefreshInterval
+func (a *JWTAuthenticator) claimRefresh() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if time.Since(a.attemptedAt) < a.opts.JWKSMinRefreshInterval {
+		return false
+	}
+	a.attemptedAt = time.Now()
+	return true
+}
+
+func (a *JWTAuthenticator) lastFetchError() error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.fetchErr
+}
+
+type jwksFetchError struct {
+	err error
+}
+
+func (e *jwksFetchError) Error() string {
+	return fmt.Sprintf("Failed to fetch JWKS: %s", e.err)
+}
+
+func (e *jwksFetchError) Unwrap() error {
+	return e.err
+}
+
+func (a *JWTAuthenticator) refresh(ctx context.Context) error {
+	a.mu.Lock()
+	a.attemptedAt = time.Now()
+	a.mu.Unlock()
+
+	keys, err := a.fetch(ctx)
+	if err != nil {
+		fetchErr := &jwksFetchError{err: err}
+		a.mu.Lock()
+		a.fetchErr = fetchErr
+		a.mu.Unlock()
+		return fetchErr
+	}
+
+	a.mu.Lock()
+	a.keys = keys
+	a.fetchedAt = time.Now()
+	a.fetchErr = nil
+	a.mu.Unlock()
+	a.logger.Infow("JWKS refreshed", "url", a.opts.JWKSURL, "keys", len(keys))
+	return nil
+}
+
+func (a *JWTAuthenticator) fetch(ctx context.Context) (map[string]utils.SigningKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, jwksFetchTimeout)
+	defer cancel()
+
+	resp, err := a.client.Get(ctx, a.opts.JWKSURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS responded %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	return utils.ParseJWKS(data)
+}
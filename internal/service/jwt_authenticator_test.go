@@ -0,0 +1,331 @@
+package service_test
+
+import (
+	"aviation-service/internal/client"
+	. "aviation-service/internal/service"
+	"aviation-service/pkg/logger"
+	"aviation-service/pkg/middleware"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func testJWKS(t *testing.T, kid string, key *rsa.PrivateKey) string {
+	t.Helper()
+	return fmt.Sprintf(`{"keys": [{"kty": "RSA", "kid": %q, "use": "sig", "alg": "RS256", "n": %q, "e": %q}]}`, kid,
+		base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()))
+}
+
+func testJWTOptions() JWTOptions {
+	return JWTOptions{
+		JWKSRefreshInterval: time.Hour,
+		Issuer:              "https://sso.example.com",
+		Audience:            "aviation-service",
+		ScopeClaim:          "scope",
+		RoleScopes: map[string]string{
+			middleware.RoleReader:   "aviation:read",
+			middleware.RoleEditor:   "aviation:write",
+			middleware.RoleOperator: "aviation:operate",
+		},
+	}
+}
+
+func TestJWTAuthenticator_Authenticate(t *testing.T) {
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(jwksFile, []byte(testJWKS(t, "sso-1", signingKey)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	validClaims := func(scope interface{}) jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":   "jdoe",
+			"iss":   "https://sso.example.com",
+			"aud":   "aviation-service",
+			"exp":   now.Add(time.Hour).Unix(),
+			"iat":   now.Unix(),
+			"scope": scope,
+		}
+	}
+	sign := func(method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + signed
+	}
+	withClaim := func(name string, value interface{}) jwt.MapClaims {
+		claims := validClaims("aviation:read")
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name             string
+		authorization    string
+		expectedIdentity *middleware.Identity
+		expectedErr      error
+	}{
+		{
+			name: "No bearer token",
+		},
+		{
+			name:          "Other authorization scheme",
+			authorization: "Basic amRvZTpzZWNyZXQ=",
+		},
+		{
+			name:             "Success reader scope",
+			authorization:    sign(jwt.SigningMethodRS256, signingKey, "sso-1", validClaims("openid aviation:read")),
+			expectedIdentity: &middleware.Identity{Subject: "jdoe", Role: middleware.RoleReader, Method: middleware.AuthMethodJWT},
+		},
+		{
+			name:             "Success highest scope wins",
+			authorization:    sign(jwt.SigningMethodRS256, signingKey, "sso-1", validClaims("aviation:operate aviation:read")),
+			expectedIdentity: &middleware.Identity{Subject: "jdoe", Role: middleware.RoleOperator, Method: middleware.AuthMethodJWT},
+		},
+		{
+			name:             "Success scope array",
+			authorization:    sign(jwt.SigningMethodRS256, signingKey, "sso-1", validClaims([]string{"aviation:write"})),
+			expectedIdentity: &middleware.Identity{Subject: "jdoe", Role: middleware.RoleEditor, Method: middleware.AuthMethodJWT},
+		},
+		{
+			name:             "Success without kid when the JWKS has one key",
+			authorization:    sign(jwt.SigningMethodRS256, signingKey, "", validClaims("aviation:read")),
+			expectedIdentity: &middleware.Identity{Subject: "jdoe", Role: middleware.RoleReader, Method: middleware.AuthMethodJWT},
+		},
+		{
+			name:             "Token without service scope has no role",
+			authorization:    sign(jwt.SigningMethodRS256, signingKey, "sso-1", validClaims("openid profile")),
+			expectedIdentity: &middleware.Identity{Subject: "jdoe", Role: "", Method: middleware.AuthMethodJWT},
+		},
+		{
+			name:          "Error expired",
+			authorization: sign(jwt.SigningMethodRS256, signingKey, "sso-1", withClaim("exp", now.Add(-time.Hour).Unix())),
+			expectedErr:   &middleware.CredentialsError{Reason: "Token expired"},
+		},
+		{
+			name:          "Error not valid yet",
+			authorization: sign(jwt.SigningMethodRS256, signingKey, "sso-1", withClaim("nbf", now.Add(time.Hour).Unix())),
+			expectedErr:   &middleware.CredentialsError{Reason: "Token not valid yet"},
+		},
+		{
+			name:          "Error without expiry",
+			authorization: sign(jwt.SigningMethodRS256, signingKey, "sso-1", withClaim("exp", nil)),
+			expectedErr:   &middleware.CredentialsError{Reason: "Invalid bearer token"},
+		},
+		{
+			name:          "Error other issuer",
+			authorization: sign(jwt.SigningMethodRS256, signingKey, "sso-1", withClaim("iss", "https://evil.example.com")),
+			expectedErr:   &middleware.CredentialsError{Reason: "Token issuer not accepted"},
+		},
+		{
+			name:          "Error other audience",
+			authorization: sign(jwt.SigningMethodRS256, signingKey, "sso-1", withClaim("aud", "billing-service")),
+			expectedErr:   &middleware.CredentialsError{Reason: "Token audience not accepted"},
+		},
+		{
+			name:          "Error without subject",
+			authorization: sign(jwt.SigningMethodRS256, signingKey, "sso-1", withClaim("sub", nil)),
+			expectedErr:   &middleware.CredentialsError{Reason: "Token has no subject"},
+		},
+		{
+			name:          "Error unknown kid",
+			authorization: sign(jwt.SigningMethodRS256, signingKey, "sso-2", validClaims("aviation:read")),
+			expectedErr:   &middleware.CredentialsError{Reason: "Token signing key not known"},
+		},
+		{
+			name:          "Error signed by another key",
+			authorization: sign(jwt.SigningMethodRS256, otherKey, "sso-1", validClaims("aviation:read")),
+			expectedErr:   &middleware.CredentialsError{Reason: "Invalid bearer token"},
+		},
+		{
+			name:          "Error algorithm other than the key pins",
+			authorization: sign(jwt.SigningMethodRS512, signingKey, "sso-1", validClaims("aviation:read")),
+			expectedErr:   &middleware.CredentialsError{Reason: "Invalid bearer token"},
+		},
+		{
+			name:          "Error symmetric algorithm",
+			authorization: sign(jwt.SigningMethodHS256, []byte("secret"), "sso-1", validClaims("aviation:read")),
+			expectedErr:   &middleware.CredentialsError{Reason: "Invalid bearer token"},
+		},
+		{
+			name:          "Error malformed token",
+			authorization: "Bearer not-a-jwt",
+			expectedErr:   &middleware.CredentialsError{Reason: "Invalid bearer token"},
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	opts := testJWTOptions()
+	opts.JWKSFile = jwksFile
+	authenticator, err := NewJWTAuthenticator(context.Background(), log, &mockHTTPClient{}, opts)
+	if err != nil {
+		t.Fatalf("Expected the JWKS file to load, got %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/airport", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			identity, err := authenticator.Authenticate(req)
+			if !reflect.DeepEqual(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if !reflect.DeepEqual(identity, tt.expectedIdentity) {
+				t.Errorf("Expected identity %+v, got %+v", tt.expectedIdentity, identity)
+			}
+		})
+	}
+}
+
+func TestNewJWTAuthenticator(t *testing.T) {
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missingFile := filepath.Join(t.TempDir(), "missing.json")
+
+	tests := []struct {
+		name        string
+		jwksFile    string
+		client      *mockHTTPClient
+		expectedErr error
+	}{
+		{
+			name:   "Success fetch JWKS URL",
+			client: &mockHTTPClient{response: testJWKS(t, "sso-1", signingKey)},
+		},
+		{
+			name:        "Error JWKS URL unavailable",
+			client:      &mockHTTPClient{statusCode: http.StatusServiceUnavailable},
+			expectedErr: fmt.Errorf("Failed to fetch JWKS: JWKS responded 503"),
+		},
+		{
+			name:        "Error JWKS URL unreachable",
+			client:      &mockHTTPClient{err: fmt.Errorf("connection refused")},
+			expectedErr: fmt.Errorf("Failed to fetch JWKS: connection refused"),
+		},
+		{
+			name:        "Error missing JWKS file",
+			jwksFile:    missingFile,
+			expectedErr: fmt.Errorf("open %s: no such file or directory", missingFile),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testJWTOptions()
+			opts.JWKSFile = tt.jwksFile
+			if tt.jwksFile == "" {
+				opts.JWKSURL = "https://sso.example.com/.well-known/jwks.json"
+			}
+
+			_, err := NewJWTAuthenticator(context.Background(), log, tt.client, opts)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticator_JWKSUnavailable(t *testing.T) {
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fetches atomic.Int32
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(testJWKS(t, "sso-1", signingKey)))
+	}))
+	defer server.Close()
+
+	sign := func(kid string) *http.Request {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"sub": "jdoe",
+			"iss": "https://sso.example.com",
+			"aud": "aviation-service",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(signingKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/airport", nil)
+		req.Header.Set("Authorization", "Bearer "+signed)
+		return req
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	opts := testJWTOptions()
+	opts.JWKSURL = server.URL
+	opts.JWKSMinRefreshInterval = 50 * time.Millisecond
+	authenticator, err := NewJWTAuthenticator(context.Background(), log, client.NewHTTPClient(server.Client(), "jwks"), opts)
+	if err != nil {
+		t.Fatalf("Expected the JWKS to load, got %v", err)
+	}
+
+	down.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		if _, err := authenticator.Authenticate(sign("sso-2")); err == nil || err.Error() != "Failed to fetch JWKS: JWKS responded 503" {
+			t.Errorf("Expected the JWKS fetch error, got %v", err)
+		}
+	}
+	// Tokens signed with a known key keep working from the cached set
+	if identity, err := authenticator.Authenticate(sign("sso-1")); err != nil || identity == nil {
+		t.Errorf("Expected the cached key to verify, got %v", err)
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("Expected 2 JWKS fetches, got %d", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	authenticator.Authenticate(sign("sso-2"))
+	if got := fetches.Load(); got != 3 {
+		t.Errorf("Expected a new JWKS fetch once the interval elapsed, got %d fetches", got)
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"aviation-service/internal/dto"
+	"aviation-service/internal/repository"
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// Postgres gets this long to answer the queries of a scrape
+const syncMetricsTimeout = 5 * time.Second
+
+var airportStatuses = []string{
+	dto.AirportStatusPending,
+	dto.AirportStatusSyncing,
+	dto.AirportStatusDone,
+	dto.AirportStatusRetrying,
+	dto.AirportStatusFailed,
+}
+
+// SyncMetrics is a Prometheus collector reading the sync state from Postgres on every scrape, so the syncs run by the
+// scheduler or another replica are reported as well
+type SyncMetrics struct {
+	logger      *zap.SugaredLogger
+	airportRepo repository.IAirportRepository
+	syncRunRepo repository.ISyncRunRepository
+	lastSuccess *prometheus.Desc
+	airports    *prometheus.Desc
+}
+
+func NewSyncMetrics(logger *zap.SugaredLogger, airportRepo repository.IAirportRepository, syncRunRepo repository.ISyncRunRepository) *SyncMetrics {
+	return &SyncMetrics{
+		logger:      logger,
+		airportRepo: airportRepo,
+		syncRunRepo: syncRunRepo,
+		lastSuccess: prometheus.NewDesc(
+			"aviation_sync_last_success_timestamp_seconds",
+			"Unix time the latest DONE sync run of a mode finished.",
+			[]string{"mode"}, nil,
+		),
+		airports: prometheus.NewDesc(
+			"aviation_sync_airports",
+			"Airports by sync status, status=\"PENDING\" is the backlog of the next pending sync.",
+			[]string{"status"}, nil,
+		),
+	}
+}
+
+func (m *SyncMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.lastSuccess
+	ch <- m.airports
+}
+
+// Collect leaves out the metrics of a failed query rather than failing the whole scrape
+func (m *SyncMetrics) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), syncMetricsTimeout)
+	defer cancel()
+
+	lastSuccess, err := m.syncRunRepo.GetLastSuccess(ctx)
+	if err != nil {
+		m.logger.Errorw("Failed to get last successful sync runs for metrics", "error", err)
+	}
+	for mode, finishedAt := range lastSuccess {
+		ch <- prometheus.MustNewConstMetric(m.lastSuccess, prometheus.GaugeValue, float64(finishedAt.Unix()), mode)
+	}
+
+	counts, err := m.airportRepo.CountByStatus(ctx)
+	if err != nil {
+		m.logger.Errorw("Failed to count airports by status for metrics", "error", err)
+		return
+	}
+	for _, status := range airportStatuses {
+		ch <- prometheus.MustNewConstMetric(m.airports, prometheus.GaugeValue, float64(counts[status]), status)
+	}
+}
@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/mock"
+	. "aviation-service/internal/service"
+	"aviation-service/pkg/logger"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSyncMetrics_Collect(t *testing.T) {
+	tests := []struct {
+		name        string
+		lastSuccess map[string]time.Time
+		counts      map[string]int
+		countErr    error
+		expected    string
+	}{
+		{
+			name:        "Success",
+			lastSuccess: map[string]time.Time{dto.SyncModePending: time.Unix(1772341800, 0)},
+			counts:      map[string]int{dto.AirportStatusPending: 12, dto.AirportStatusDone: 30},
+			expected: `
+# HELP aviation_sync_airports Airports by sync status, status="PENDING" is the backlog of the next pending sync.
+# TYPE aviation_sync_airports gauge
+aviation_sync_airports{status="DONE"} 30
+aviation_sync_airports{status="FAILED"} 0
+aviation_sync_airports{status="PENDING"} 12
+aviation_sync_airports{status="RETRYING"} 0
+aviation_sync_airports{status="SYNCING"} 0
+# HELP aviation_sync_last_success_timestamp_seconds Unix time the latest DONE sync run of a mode finished.
+# TYPE aviation_sync_last_success_timestamp_seconds gauge
+aviation_sync_last_success_timestamp_seconds{mode="PENDING"} 1.7723418e+09
+`,
+		},
+		{
+			name:     "Error DB leaves the airport counts out",
+			countErr: errors.New("DB error"),
+			expected: "",
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			airportRepo := &IAirportRepositoryMock{
+				CountByStatusFunc: func(ctx context.Context) (map[string]int, error) {
+					return tt.counts, tt.countErr
+				},
+			}
+			syncRunRepo := &ISyncRunRepositoryMock{
+				GetLastSuccessFunc: func(ctx context.Context) (map[string]time.Time, error) {
+					return tt.lastSuccess, nil
+				},
+			}
+
+			collector := NewSyncMetrics(log, airportRepo, syncRunRepo)
+			if err := testutil.CollectAndCompare(collector, strings.NewReader(tt.expected)); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"aviation-service/pkg/metrics"
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Every exported service method starts a span named <Service>.<Method>, the repository queries and upstream requests
+// it makes are traced below it
+var tracer = otel.Tracer("aviation-service/internal/service")
+
+// cacheLookup counts the lookup and marks the span of the calling method with its result
+func cacheLookup(ctx context.Context, keyspace string, hit bool) {
+	metrics.CacheLookup(keyspace, hit)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("cache.hit", hit))
+}
@@ -18,6 +18,7 @@ import (
 //go:generate moq -out ../mock/weather_service_mock.go -pkg=mock . IWeatherService
 type IWeatherService interface {
 	GetWeather(ctx context.Context, city string) (*dto.Weather, error)
+	GetWeatherByCoordinates(ctx context.Context, lat, lon float64) (*dto.Weather, error)
 }
 
 type WeatherService struct {
@@ -37,20 +38,36 @@ func NewWeatherService(logger *zap.SugaredLogger, cfg config.Config, client Clie
 }
 
 func (s *WeatherService) GetWeather(ctx context.Context, city string) (*dto.Weather, error) {
-	cacheKey := fmt.Sprintf("weather:%s", city)
+	ctx, span := tracer.Start(ctx, "WeatherService.GetWeather")
+	defer span.End()
+
+	return s.getWeather(ctx, fmt.Sprintf("weather:%s", city), city)
+}
+
+// GetWeatherByCoordinates queries WeatherAPI with "lat,lon" rounded to two decimals (about 1 km), so nearby airports share a cache entry
+func (s *WeatherService) GetWeatherByCoordinates(ctx context.Context, lat, lon float64) (*dto.Weather, error) {
+	ctx, span := tracer.Start(ctx, "WeatherService.GetWeatherByCoordinates")
+	defer span.End()
+
+	query := fmt.Sprintf("%.2f,%.2f", lat, lon)
+	return s.getWeather(ctx, "weather:coord:"+query, query)
+}
+
+func (s *WeatherService) getWeather(ctx context.Context, cacheKey, query string) (*dto.Weather, error) {
 	var weather dto.WeatherDataResponse
-	s.logger.Infow("Weather cache hit", "city", city)
 	cacheErr := utils.GetStruct(s.redisClient, ctx, cacheKey, &weather)
+	cacheLookup(ctx, "weather", cacheErr == nil)
 	if cacheErr == nil {
+		s.logger.Infow("Weather cache hit", "query", query)
 		return &weather.Current, nil
 	}
-	s.logger.Infow("No airport data from cache, fetching from API", "error", cacheErr)
+	s.logger.Infow("No weather data from cache, fetching from API", "error", cacheErr)
 
-	s.logger.Infow("Fetching weather data", "city", city)
+	s.logger.Infow("Fetching weather data", "query", query)
 	params := url.Values{}
 	params.Add("key", s.cfg.WEATHER_API_KEY)
-	params.Add("q", city)
-	resp, err := s.client.Get(s.cfg.WEATHER_API_URL + "/current.json?" + params.Encode())
+	params.Add("q", query)
+	resp, err := s.client.Get(ctx, s.cfg.WEATHER_API_URL+"/current.json?"+params.Encode())
 	if err != nil {
 		s.logger.Errorw("Error fetching weather data", "error", err)
 		return nil, err
@@ -126,3 +126,59 @@ func TestWeatherService_GetWeather(t *testing.T) {
 		})
 	}
 }
+
+func TestWeatherService_GetWeatherByCoordinates(t *testing.T) {
+	tests := []struct {
+		name           string
+		httpClient     *mockHTTPClient
+		redisClient    *MockRedis
+		expectedResult *dto.Weather
+		expectedErr    error
+	}{
+		{
+			name: "Success with data (cache miss)",
+			httpClient: &mockHTTPClient{
+				response: `{"current":{"last_updated":"2025-09-29 02:45","temp_c":21.5,"vis_km":16}}`,
+			},
+			redisClient:    &MockRedis{Store: make(map[string]string)},
+			expectedResult: &dto.Weather{LastUpdated: "2025-09-29 02:45", TempC: 21.5, VisKm: 16},
+		},
+		{
+			name:       "Success with data (cache hit of nearby point)",
+			httpClient: &mockHTTPClient{},
+			redisClient: &MockRedis{Store: map[string]string{
+				"weather:coord:33.94,-118.41": `{"current":{"last_updated":"2025-09-29 02:45","temp_c":21.5}}`,
+			}},
+			expectedResult: &dto.Weather{LastUpdated: "2025-09-29 02:45", TempC: 21.5},
+		},
+		{
+			name: "Error fetching weather data",
+			httpClient: &mockHTTPClient{
+				err: fmt.Errorf("Error fetching weather data"),
+			},
+			redisClient: &MockRedis{Store: make(map[string]string)},
+			expectedErr: fmt.Errorf("Error fetching weather data"),
+		},
+	}
+
+	log := logger.GetLogger()
+	defer log.Sync()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{WEATHER_API_URL: "http://123"}
+			s := NewWeatherService(log, cfg, tt.httpClient, tt.redisClient)
+
+			got, err := s.GetWeatherByCoordinates(context.Background(), 33.9425, -118.4081)
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedResult) {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+			if err == nil && tt.redisClient.Store["weather:coord:33.94,-118.41"] == "" {
+				t.Errorf("Expected weather cached under the rounded coordinate key")
+			}
+		})
+	}
+}
@@ -13,7 +13,8 @@ type ExpectedResult struct {
 	Status  int
 	Data    interface{}
 	Message string
-	Error   string
+	// Error is the error message, or a value compared with the error by its JSON like the field errors of a validation
+	Error interface{}
 }
 
 func AssertHandlerResponse(t *testing.T, rr *httptest.ResponseRecorder, expected ExpectedResult) error {
@@ -42,8 +43,14 @@ func AssertHandlerResponse(t *testing.T, rr *httptest.ResponseRecorder, expected
 		return fmt.Errorf("Expected message %q, got %q", expected.Message, body.Message)
 	}
 
-	if expected.Error != "" && body.Error != expected.Error {
-		return fmt.Errorf("Expected error %q, got %q", expected.Error, body.Error)
+	if expected.Error != nil && expected.Error != "" {
+		var expectedError interface{}
+		expectedBytes, _ := json.Marshal(expected.Error)
+		json.Unmarshal(expectedBytes, &expectedError)
+
+		if !reflect.DeepEqual(body.Error, expectedError) {
+			return fmt.Errorf("Expected error %v, got %v", expectedError, body.Error)
+		}
 	}
 	return nil
 }
@@ -0,0 +1,221 @@
+package utils
+
+import (
+	"aviation-service/internal/dto"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// AirportExporter writes airports one at a time, Close writes whatever the format needs after the last airport
+type AirportExporter interface {
+	Write(airport *dto.Airport) error
+	Close() error
+}
+
+// CSV headers use the airport JSON names so an export can be imported again
+var exportColumns = []string{
+	"id", "icao_ident", "faa_ident", "facility_name", "type", "region", "state_full", "county", "city",
+	"ownership", "use", "manager", "manager_phone", "latitude", "longitude", "latitude_deg", "longitude_deg", "status",
+}
+
+// NewAirportExporter returns the exporter for the format, it writes nothing until the first airport or Close
+func NewAirportExporter(format string, w io.Writer) (AirportExporter, error) {
+	switch format {
+	case dto.ExportFormatCSV:
+		return &csvExporter{writer: csv.NewWriter(w)}, nil
+	case dto.ExportFormatGeoJSON:
+		return &geoJSONExporter{w: w}, nil
+	case dto.ExportFormatKML:
+		return &kmlExporter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("Unsupported export format %q", format)
+	}
+}
+
+// ExportContentType returns the media type and file extension of the export format
+func ExportContentType(format string) (string, string) {
+	switch format {
+	case dto.ExportFormatGeoJSON:
+		return "application/geo+json", "geojson"
+	case dto.ExportFormatKML:
+		return "application/vnd.google-earth.kml+xml", "kml"
+	default:
+		return "text/csv", "csv"
+	}
+}
+
+// AirportPoint converts the stored DMS coordinates into decimal degrees, ok is false when either is missing or invalid
+func AirportPoint(airport *dto.Airport) (lat, lon float64, ok bool) {
+	if airport.Latitude == nil || airport.Longitude == nil {
+		return 0, 0, false
+	}
+	lat, latErr := ParseLatitude(*airport.Latitude)
+	lon, lonErr := ParseLongitude(*airport.Longitude)
+	if latErr != nil || lonErr != nil {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+type csvExporter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (e *csvExporter) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	return e.writer.Write(exportColumns)
+}
+
+func (e *csvExporter) Write(airport *dto.Airport) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	lat, lon, ok := AirportPoint(airport)
+	latDeg, lonDeg := "", ""
+	if ok {
+		latDeg = strconv.FormatFloat(lat, 'f', 6, 64)
+		lonDeg = strconv.FormatFloat(lon, 'f', 6, 64)
+	}
+	return e.writer.Write([]string{
+		strconv.Itoa(airport.ID), airport.ICAO, stringValue(airport.FAA), stringValue(airport.FacilityName), stringValue(airport.Type),
+		stringValue(airport.Region), stringValue(airport.State), stringValue(airport.County), stringValue(airport.City), stringValue(airport.Ownership),
+		stringValue(airport.Use), stringValue(airport.Manager), stringValue(airport.ManagerPhone), stringValue(airport.Latitude), stringValue(airport.Longitude),
+		latDeg, lonDeg, airport.Status,
+	})
+}
+
+func (e *csvExporter) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// geoJSONExporter writes a FeatureCollection of Point features, airports without valid coordinates get a null geometry
+type geoJSONExporter struct {
+	w       io.Writer
+	started bool
+}
+
+func (e *geoJSONExporter) start() error {
+	if e.started {
+		_, err := io.WriteString(e.w, ",")
+		return err
+	}
+	e.started = true
+	_, err := io.WriteString(e.w, `{"type":"FeatureCollection","features":[`)
+	return err
+}
+
+func (e *geoJSONExporter) Write(airport *dto.Airport) error {
+	feature := dto.GeoJSONFeature{Type: "Feature", Properties: airport}
+	if lat, lon, ok := AirportPoint(airport); ok {
+		// GeoJSON positions are longitude first, six decimals is about a tenth of a meter
+		feature.Geometry = &dto.GeoJSONPoint{Type: "Point", Coordinates: [2]float64{roundTo(lon, 6), roundTo(lat, 6)}}
+	}
+	data, err := json.Marshal(feature)
+	if err != nil {
+		return err
+	}
+	if err := e.start(); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *geoJSONExporter) Close() error {
+	if !e.started {
+		_, err := io.WriteString(e.w, `{"type":"FeatureCollection","features":[]}`)
+		return err
+	}
+	_, err := io.WriteString(e.w, "]}\n")
+	return err
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlPoint struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlPlacemark struct {
+	XMLName      xml.Name  `xml:"Placemark"`
+	Name         string    `xml:"name"`
+	Description  string    `xml:"description,omitempty"`
+	ExtendedData []kmlData `xml:"ExtendedData>Data"`
+	Point        kmlPoint  `xml:"Point"`
+}
+
+// kmlExporter writes one placemark per airport, a placemark needs a point so airports without valid coordinates are left out
+type kmlExporter struct {
+	w       io.Writer
+	encoder *xml.Encoder
+}
+
+func (e *kmlExporter) start() error {
+	if e.encoder != nil {
+		return nil
+	}
+	if _, err := io.WriteString(e.w, xml.Header+`<kml xmlns="http://www.opengis.net/kml/2.2"><Document><name>Airports</name>`); err != nil {
+		return err
+	}
+	e.encoder = xml.NewEncoder(e.w)
+	return nil
+}
+
+func (e *kmlExporter) Write(airport *dto.Airport) error {
+	lat, lon, ok := AirportPoint(airport)
+	if !ok {
+		return nil
+	}
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	placemark := kmlPlacemark{
+		Name:        airport.ICAO,
+		Description: stringValue(airport.FacilityName),
+		// KML coordinates are longitude,latitude
+		Point: kmlPoint{Coordinates: strconv.FormatFloat(lon, 'f', 6, 64) + "," + strconv.FormatFloat(lat, 'f', 6, 64)},
+	}
+	for _, data := range []kmlData{
+		{Name: "faa_ident", Value: stringValue(airport.FAA)},
+		{Name: "type", Value: stringValue(airport.Type)},
+		{Name: "city", Value: stringValue(airport.City)},
+		{Name: "state_full", Value: stringValue(airport.State)},
+		{Name: "status", Value: airport.Status},
+	} {
+		if data.Value != "" {
+			placemark.ExtendedData = append(placemark.ExtendedData, data)
+		}
+	}
+	return e.encoder.Encode(placemark)
+}
+
+func (e *kmlExporter) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, "</Document></kml>\n")
+	return err
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
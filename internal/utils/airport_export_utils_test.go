@@ -0,0 +1,101 @@
+package utils_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/utils"
+)
+
+func TestAirportExporter(t *testing.T) {
+	airports := []dto.Airport{
+		{ID: 1, ICAO: "KAVL", FacilityName: strPtr("ASHEVILLE RGNL"), FAA: strPtr("AVL"), City: strPtr("ASHEVILLE"),
+			Latitude: strPtr("35-26-08.4000N"), Longitude: strPtr("082-32-31.9000W"), Status: "DONE"},
+		{ID: 2, ICAO: "KXYZ", FacilityName: strPtr("NO, COORDINATES"), Latitude: strPtr("invalid"), Status: "PENDING"},
+	}
+	tests := []struct {
+		name           string
+		format         string
+		airports       []dto.Airport
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			name:     "Success CSV",
+			format:   dto.ExportFormatCSV,
+			airports: airports,
+			expectedOutput: "id,icao_ident,faa_ident,facility_name,type,region,state_full,county,city,ownership,use,manager,manager_phone,latitude,longitude,latitude_deg,longitude_deg,status\n" +
+				"1,KAVL,AVL,ASHEVILLE RGNL,,,,,ASHEVILLE,,,,,35-26-08.4000N,082-32-31.9000W,35.435667,-82.542194,DONE\n" +
+				"2,KXYZ,,\"NO, COORDINATES\",,,,,,,,,,invalid,,,,PENDING\n",
+		},
+		{
+			name:     "Success GeoJSON",
+			format:   dto.ExportFormatGeoJSON,
+			airports: airports,
+			expectedOutput: `{"type":"FeatureCollection","features":[` +
+				`{"type":"Feature","geometry":{"type":"Point","coordinates":[-82.542194,35.435667]},"properties":{"id":1,"facility_name":"ASHEVILLE RGNL","faa_ident":"AVL","icao_ident":"KAVL","city":"ASHEVILLE","latitude":"35-26-08.4000N","longitude":"082-32-31.9000W","status":"DONE"}},` +
+				`{"type":"Feature","geometry":null,"properties":{"id":2,"facility_name":"NO, COORDINATES","icao_ident":"KXYZ","latitude":"invalid","status":"PENDING"}}` +
+				"]}\n",
+		},
+		{
+			name:     "Success KML skips airports without coordinates",
+			format:   dto.ExportFormatKML,
+			airports: airports,
+			expectedOutput: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<kml xmlns="http://www.opengis.net/kml/2.2"><Document><name>Airports</name>` +
+				`<Placemark><name>KAVL</name><description>ASHEVILLE RGNL</description><ExtendedData>` +
+				`<Data name="faa_ident"><value>AVL</value></Data><Data name="city"><value>ASHEVILLE</value></Data><Data name="status"><value>DONE</value></Data>` +
+				`</ExtendedData><Point><coordinates>-82.542194,35.435667</coordinates></Point></Placemark>` +
+				"</Document></kml>\n",
+		},
+		{
+			name:           "Success empty CSV keeps header",
+			format:         dto.ExportFormatCSV,
+			expectedOutput: "id,icao_ident,faa_ident,facility_name,type,region,state_full,county,city,ownership,use,manager,manager_phone,latitude,longitude,latitude_deg,longitude_deg,status\n",
+		},
+		{
+			name:           "Success empty GeoJSON",
+			format:         dto.ExportFormatGeoJSON,
+			expectedOutput: `{"type":"FeatureCollection","features":[]}`,
+		},
+		{
+			name:   "Success empty KML",
+			format: dto.ExportFormatKML,
+			expectedOutput: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<kml xmlns="http://www.opengis.net/kml/2.2"><Document><name>Airports</name></Document></kml>` + "\n",
+		},
+		{
+			name:        "Error unsupported format",
+			format:      "xml",
+			expectedErr: fmt.Errorf(`Unsupported export format "xml"`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			exporter, err := NewAirportExporter(tt.format, &buf)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			for i := range tt.airports {
+				if err := exporter.Write(&tt.airports[i]); err != nil {
+					t.Fatalf("Unexpected write error %v", err)
+				}
+			}
+			if err := exporter.Close(); err != nil {
+				t.Fatalf("Unexpected close error %v", err)
+			}
+
+			if buf.String() != tt.expectedOutput {
+				t.Errorf("Expected output\n%s\ngot\n%s", tt.expectedOutput, buf.String())
+			}
+		})
+	}
+}
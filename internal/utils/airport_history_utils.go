@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"aviation-service/internal/dto"
+	"aviation-service/pkg/middleware"
+	"context"
+	"fmt"
+	"strings"
+)
+
+type actorKey struct{}
+
+type fieldSourceKey struct{}
+
+// WithActor records who is changing airports, the repository stores it with every history entry
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set with WithActor, otherwise the authenticated caller of the request.
+// Anonymous requests are attributed to the API
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	if identity := middleware.IdentityFromContext(ctx); identity != nil && identity.Method != middleware.AuthMethodAnonymous {
+		return identity.Actor()
+	}
+	return dto.ActorAPI
+}
+
+func SyncRunActor(runID int) string {
+	return fmt.Sprintf("sync_run:%d", runID)
+}
+
+// WithFieldSource overrides the provenance recorded for the fields changed by the next writes
+func WithFieldSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, fieldSourceKey{}, source)
+}
+
+// FieldSourceFromContext returns the provenance of the fields written with ctx, values written by a sync run
+// or fetched from AviationAPI are upstream and everything else is manual
+func FieldSourceFromContext(ctx context.Context) string {
+	if source, ok := ctx.Value(fieldSourceKey{}).(string); ok && source != "" {
+		return source
+	}
+	actor := ActorFromContext(ctx)
+	if actor == dto.ActorAviationAPI || strings.HasPrefix(actor, "sync_run:") {
+		return dto.FieldSourceUpstream
+	}
+	return dto.FieldSourceManual
+}
+
+// AirportChanges lists the fields that differ between two versions of an airport, a nil before or after
+// stands for an insert or a delete. Fields are named like the airport JSON
+func AirportChanges(before, after *dto.Airport) dto.FieldChanges {
+	var b, a dto.Airport
+	if before != nil {
+		b = *before
+	}
+	if after != nil {
+		a = *after
+	}
+
+	var changes dto.FieldChanges
+	add := func(field string, before, after *string) {
+		if stringValue(before) != stringValue(after) {
+			changes = append(changes, dto.FieldChange{Field: field, Before: before, After: after})
+		}
+	}
+
+	add("icao_ident", optional(b.ICAO), optional(a.ICAO))
+	afterFields := airportFields(&a)
+	for i, f := range airportFields(&b) {
+		add(f.name, *f.value, *afterFields[i].value)
+	}
+	add("status", optional(b.Status), optional(a.Status))
+	return changes
+}
+
+// MergeFieldSources marks every changed field with source, the other fields keep their previous provenance
+func MergeFieldSources(previous dto.FieldSources, changes dto.FieldChanges, source string) dto.FieldSources {
+	merged := dto.FieldSources{}
+	for field, src := range previous {
+		merged[field] = src
+	}
+	for _, change := range changes {
+		if change.Field == "icao_ident" || change.Field == "status" {
+			continue
+		}
+		if change.After == nil {
+			delete(merged, change.Field)
+		} else {
+			merged[change.Field] = source
+		}
+	}
+	return merged
+}
+
+type airportField struct {
+	name  string
+	value **string
+}
+
+// airportFields lists the optional airport fields that come from AviationAPI or can be curated by hand
+func airportFields(a *dto.Airport) []airportField {
+	return []airportField{
+		{"type", &a.Type},
+		{"facility_name", &a.FacilityName},
+		{"faa_ident", &a.FAA},
+		{"region", &a.Region},
+		{"state_full", &a.State},
+		{"county", &a.County},
+		{"city", &a.City},
+		{"ownership", &a.Ownership},
+		{"use", &a.Use},
+		{"manager", &a.Manager},
+		{"manager_phone", &a.ManagerPhone},
+		{"latitude", &a.Latitude},
+		{"longitude", &a.Longitude},
+	}
+}
@@ -0,0 +1,157 @@
+package utils_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/utils"
+	"aviation-service/pkg/middleware"
+)
+
+func TestAirportChanges(t *testing.T) {
+	before := &dto.Airport{ID: 1, ICAO: "KAVL", FacilityName: strPtr("ASHEVILLE RGNL"), ManagerPhone: strPtr("828-684-2226"), Status: "DONE"}
+	tests := []struct {
+		name     string
+		before   *dto.Airport
+		after    *dto.Airport
+		expected dto.FieldChanges
+	}{
+		{
+			name:  "Insert lists every set field",
+			after: before,
+			expected: dto.FieldChanges{
+				{Field: "icao_ident", After: strPtr("KAVL")},
+				{Field: "facility_name", After: strPtr("ASHEVILLE RGNL")},
+				{Field: "manager_phone", After: strPtr("828-684-2226")},
+				{Field: "status", After: strPtr("DONE")},
+			},
+		},
+		{
+			name:   "Update lists changed fields only",
+			before: before,
+			after:  &dto.Airport{ID: 1, ICAO: "KAVL", FacilityName: strPtr("ASHEVILLE RGNL"), City: strPtr("ASHEVILLE"), Status: "PENDING"},
+			expected: dto.FieldChanges{
+				{Field: "city", After: strPtr("ASHEVILLE")},
+				{Field: "manager_phone", Before: strPtr("828-684-2226")},
+				{Field: "status", Before: strPtr("DONE"), After: strPtr("PENDING")},
+			},
+		},
+		{
+			name:   "Delete lists every previous field",
+			before: &dto.Airport{ID: 1, ICAO: "KAVL", Status: "DONE"},
+			expected: dto.FieldChanges{
+				{Field: "icao_ident", Before: strPtr("KAVL")},
+				{Field: "status", Before: strPtr("DONE")},
+			},
+		},
+		{
+			name:   "No changes",
+			before: before,
+			after:  before,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AirportChanges(tt.before, tt.after)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected changes %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestActorFromContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{
+			name:     "Default to API",
+			ctx:      context.Background(),
+			expected: dto.ActorAPI,
+		},
+		{
+			name:     "Sync run actor",
+			ctx:      WithActor(context.Background(), SyncRunActor(12)),
+			expected: "sync_run:12",
+		},
+		{
+			name:     "Authenticated caller",
+			ctx:      middleware.WithIdentity(context.Background(), &middleware.Identity{Subject: "ci", Role: middleware.RoleEditor, Method: middleware.AuthMethodAPIKey}),
+			expected: "api_key:ci",
+		},
+		{
+			name:     "Anonymous caller is the API",
+			ctx:      middleware.WithIdentity(context.Background(), &middleware.Identity{Subject: "anonymous", Role: middleware.RoleReader, Method: middleware.AuthMethodAnonymous}),
+			expected: dto.ActorAPI,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ActorFromContext(tt.ctx); got != tt.expected {
+				t.Errorf("Expected actor %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFieldSourceFromContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{
+			name:     "API edits are manual",
+			ctx:      context.Background(),
+			expected: dto.FieldSourceManual,
+		},
+		{
+			name:     "Sync run writes are upstream",
+			ctx:      WithActor(context.Background(), SyncRunActor(12)),
+			expected: dto.FieldSourceUpstream,
+		},
+		{
+			name:     "AviationAPI fetches are upstream",
+			ctx:      WithActor(context.Background(), dto.ActorAviationAPI),
+			expected: dto.FieldSourceUpstream,
+		},
+		{
+			name:     "Explicit source wins",
+			ctx:      WithFieldSource(WithActor(context.Background(), dto.ActorAPI), dto.FieldSourceUpstream),
+			expected: dto.FieldSourceUpstream,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FieldSourceFromContext(tt.ctx); got != tt.expected {
+				t.Errorf("Expected source %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMergeFieldSources(t *testing.T) {
+	previous := dto.FieldSources{"city": dto.FieldSourceUpstream, "manager": dto.FieldSourceManual}
+	changes := dto.FieldChanges{
+		{Field: "icao_ident", After: strPtr("KAVL")},
+		{Field: "city", Before: strPtr("OLD CITY"), After: strPtr("ASHEVILLE")},
+		{Field: "manager", Before: strPtr("LEW BLEIWEIS")},
+		{Field: "status", Before: strPtr("PENDING"), After: strPtr("DONE")},
+	}
+
+	got := MergeFieldSources(previous, changes, dto.FieldSourceManual)
+	expected := dto.FieldSources{"city": dto.FieldSourceManual}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected sources %v, got %v", expected, got)
+	}
+	if previous["manager"] != dto.FieldSourceManual {
+		t.Errorf("Expected previous sources untouched, got %v", previous)
+	}
+}
@@ -0,0 +1,212 @@
+package utils
+
+import (
+	"aviation-service/internal/dto"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type AirportImportRow struct {
+	Row     int
+	Airport dto.Airport
+	// Skip marks NASR facilities without an ICAO identifier, they cannot be keyed and are not reported as errors
+	Skip bool
+}
+
+// Generic CSV headers, matched case-insensitively against the airport JSON and column names
+var csvColumns = map[string]func(a *dto.Airport, value string){
+	"icao_ident":    func(a *dto.Airport, v string) { a.ICAO = strings.ToUpper(v) },
+	"icao":          func(a *dto.Airport, v string) { a.ICAO = strings.ToUpper(v) },
+	"faa_ident":     func(a *dto.Airport, v string) { a.FAA = optional(strings.ToUpper(v)) },
+	"faa":           func(a *dto.Airport, v string) { a.FAA = optional(strings.ToUpper(v)) },
+	"facility_name": func(a *dto.Airport, v string) { a.FacilityName = optional(v) },
+	"type":          func(a *dto.Airport, v string) { a.Type = optional(v) },
+	"region":        func(a *dto.Airport, v string) { a.Region = optional(v) },
+	"state_full":    func(a *dto.Airport, v string) { a.State = optional(v) },
+	"state":         func(a *dto.Airport, v string) { a.State = optional(v) },
+	"county":        func(a *dto.Airport, v string) { a.County = optional(v) },
+	"city":          func(a *dto.Airport, v string) { a.City = optional(v) },
+	"ownership":     func(a *dto.Airport, v string) { a.Ownership = optional(strings.ToUpper(v)) },
+	"use":           func(a *dto.Airport, v string) { a.Use = optional(strings.ToUpper(v)) },
+	"manager":       func(a *dto.Airport, v string) { a.Manager = optional(v) },
+	"manager_phone": func(a *dto.Airport, v string) { a.ManagerPhone = optional(v) },
+	"latitude":      func(a *dto.Airport, v string) { a.Latitude = optionalCoordinate(v, FormatLatitude) },
+	"longitude":     func(a *dto.Airport, v string) { a.Longitude = optionalCoordinate(v, FormatLongitude) },
+}
+
+var nasrSiteTypes = map[string]string{
+	"A": "AIRPORT",
+	"B": "BALLOONPORT",
+	"C": "SEAPLANE BASE",
+	"G": "GLIDERPORT",
+	"H": "HELIPORT",
+	"U": "ULTRALIGHT",
+}
+
+// NASR distinguishes military and Coast Guard owners, the airport table only knows public and private
+var nasrOwnership = map[string]string{
+	"PU": "PU",
+	"PR": "PR",
+	"MA": "PU",
+	"MN": "PU",
+	"MR": "PU",
+	"CG": "PU",
+}
+
+// ImportFormatFromFilename picks the import format from the file extension, anything but .json is read as CSV
+func ImportFormatFromFilename(filename string) string {
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		return dto.ImportFormatJSON
+	}
+	return dto.ImportFormatCSV
+}
+
+// ParseAirportImport reads an import file into rows, it returns the format that was actually used since a CSV may turn out to be a NASR APT file
+func ParseAirportImport(format string, r io.Reader) (string, []AirportImportRow, error) {
+	switch format {
+	case dto.ImportFormatJSON:
+		rows, err := parseAirportJSON(r)
+		return format, rows, err
+	case dto.ImportFormatCSV, dto.ImportFormatNASR:
+		return parseAirportCSV(format, r)
+	default:
+		return format, nil, fmt.Errorf("Unsupported import format %q", format)
+	}
+}
+
+func parseAirportJSON(r io.Reader) ([]AirportImportRow, error) {
+	var airports []dto.Airport
+	if err := json.NewDecoder(r).Decode(&airports); err != nil {
+		return nil, fmt.Errorf("Invalid JSON import file: %s", err)
+	}
+
+	rows := make([]AirportImportRow, 0, len(airports))
+	for i, airport := range airports {
+		airport.ID = 0
+		airport.ICAO = strings.ToUpper(strings.TrimSpace(airport.ICAO))
+		rows = append(rows, AirportImportRow{Row: i + 1, Airport: airport})
+	}
+	return rows, nil
+}
+
+func parseAirportCSV(format string, r io.Reader) (string, []AirportImportRow, error) {
+	// NASR files start with a UTF-8 byte order mark, which would otherwise break the quoted first header
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(3); err == nil && string(bom) == "\ufeff" {
+		buffered.Discard(3)
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return format, nil, fmt.Errorf("Invalid CSV import file, missing header: %s", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		columns[strings.NewReplacer(" ", "_", "-", "_").Replace(name)] = i
+	}
+
+	_, hasSiteType := columns["site_type_code"]
+	_, hasArptID := columns["arpt_id"]
+	if hasSiteType && hasArptID {
+		format = dto.ImportFormatNASR
+	} else if format == dto.ImportFormatNASR {
+		return format, nil, errors.New("Invalid NASR import file, expected APT_BASE columns ARPT_ID and SITE_TYPE_CODE")
+	}
+
+	var rows []AirportImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return format, nil, fmt.Errorf("Invalid CSV import file: %s", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if format == dto.ImportFormatNASR {
+			rows = append(rows, nasrRow(line, get))
+		} else {
+			rows = append(rows, csvRow(line, columns, get))
+		}
+	}
+	return format, rows, nil
+}
+
+func csvRow(line int, columns map[string]int, get func(string) string) AirportImportRow {
+	var airport dto.Airport
+	for column := range columns {
+		if set, ok := csvColumns[column]; ok {
+			if value := get(column); value != "" {
+				set(&airport, value)
+			}
+		}
+	}
+	return AirportImportRow{Row: line, Airport: airport}
+}
+
+// nasrRow maps a row of the FAA NASR APT_BASE.csv, manager contacts live in APT_CON.csv so new airports stay incomplete
+// and stored ones keep their contacts
+func nasrRow(line int, get func(string) string) AirportImportRow {
+	icao := strings.ToUpper(get("icao_id"))
+	if icao == "" {
+		return AirportImportRow{Row: line, Skip: true}
+	}
+
+	airport := dto.Airport{
+		ICAO:         icao,
+		FAA:          optional(strings.ToUpper(get("arpt_id"))),
+		FacilityName: optional(get("arpt_name")),
+		Region:       optional(get("region_code")),
+		State:        optional(get("state_name")),
+		County:       optional(get("county_name")),
+		City:         optional(get("city")),
+		Use:          optional(get("facility_use_code")),
+		Latitude:     optionalCoordinate(get("lat_decimal"), FormatLatitude),
+		Longitude:    optionalCoordinate(get("long_decimal"), FormatLongitude),
+	}
+	if siteType, ok := nasrSiteTypes[get("site_type_code")]; ok {
+		airport.Type = &siteType
+	}
+	if ownership := get("ownership_type_code"); ownership != "" {
+		if mapped, ok := nasrOwnership[ownership]; ok {
+			ownership = mapped
+		}
+		airport.Ownership = &ownership
+	}
+	return AirportImportRow{Row: line, Airport: airport}
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// optionalCoordinate accepts either a DMS string, kept as is, or decimal degrees converted to DMS
+func optionalCoordinate(value string, format func(float64) string) *string {
+	if deg, err := strconv.ParseFloat(value, 64); err == nil {
+		value = format(deg)
+	}
+	return optional(value)
+}
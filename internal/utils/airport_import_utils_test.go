@@ -0,0 +1,121 @@
+package utils_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/utils"
+)
+
+func strPtr(v string) *string {
+	return &v
+}
+
+func TestParseAirportImport(t *testing.T) {
+	tests := []struct {
+		name           string
+		format         string
+		file           string
+		expectedFormat string
+		expectedResult []AirportImportRow
+		expectedErr    error
+	}{
+		{
+			name:   "Success generic CSV",
+			format: dto.ImportFormatCSV,
+			file: "ICAO_IDENT,Facility Name,City,Ownership,Latitude,Longitude,Notes\n" +
+				"kavl,ASHEVILLE RGNL,ASHEVILLE,pu,35-26-08.4000N,082-32-31.9000W,ignored\n" +
+				"KLAX,LOS ANGELES INTL,,PU,33.9425,-118.408056\n",
+			expectedFormat: dto.ImportFormatCSV,
+			expectedResult: []AirportImportRow{
+				{Row: 2, Airport: dto.Airport{ICAO: "KAVL", FacilityName: strPtr("ASHEVILLE RGNL"), City: strPtr("ASHEVILLE"), Ownership: strPtr("PU"),
+					Latitude: strPtr("35-26-08.4000N"), Longitude: strPtr("082-32-31.9000W")}},
+				{Row: 3, Airport: dto.Airport{ICAO: "KLAX", FacilityName: strPtr("LOS ANGELES INTL"), Ownership: strPtr("PU"),
+					Latitude: strPtr("33-56-33.0000N"), Longitude: strPtr("118-24-29.0016W")}},
+			},
+		},
+		{
+			name:   "Success NASR APT_BASE detected from CSV header",
+			format: dto.ImportFormatCSV,
+			file: "\ufeff\"SITE_TYPE_CODE\",\"STATE_NAME\",\"ARPT_ID\",\"CITY\",\"REGION_CODE\",\"COUNTY_NAME\",\"ARPT_NAME\",\"OWNERSHIP_TYPE_CODE\",\"FACILITY_USE_CODE\",\"LAT_DECIMAL\",\"LONG_DECIMAL\",\"ICAO_ID\"\n" +
+				"\"A\",\"NORTH CAROLINA\",\"AVL\",\"ASHEVILLE\",\"ASO\",\"BUNCOMBE\",\"ASHEVILLE RGNL\",\"PU\",\"PU\",\"35.43566667\",\"-82.54219444\",\"KAVL\"\n" +
+				"\"H\",\"NORTH CAROLINA\",\"1NC\",\"ASHEVILLE\",\"ASO\",\"BUNCOMBE\",\"MISSION HOSPITAL\",\"PR\",\"PR\",\"35.58\",\"-82.55\",\"\"\n" +
+				"\"A\",\"NORTH CAROLINA\",\"POB\",\"FORT BRAGG\",\"ASO\",\"CUMBERLAND\",\"POPE FLD\",\"MA\",\"PR\",\"35.17083333\",\"-79.01444444\",\"KPOB\"\n",
+			expectedFormat: dto.ImportFormatNASR,
+			expectedResult: []AirportImportRow{
+				{Row: 2, Airport: dto.Airport{ICAO: "KAVL", FAA: strPtr("AVL"), Type: strPtr("AIRPORT"), FacilityName: strPtr("ASHEVILLE RGNL"), Region: strPtr("ASO"),
+					State: strPtr("NORTH CAROLINA"), County: strPtr("BUNCOMBE"), City: strPtr("ASHEVILLE"), Ownership: strPtr("PU"), Use: strPtr("PU"),
+					Latitude: strPtr("35-26-08.4000N"), Longitude: strPtr("082-32-31.9000W")}},
+				{Row: 3, Skip: true},
+				{Row: 4, Airport: dto.Airport{ICAO: "KPOB", FAA: strPtr("POB"), Type: strPtr("AIRPORT"), FacilityName: strPtr("POPE FLD"), Region: strPtr("ASO"),
+					State: strPtr("NORTH CAROLINA"), County: strPtr("CUMBERLAND"), City: strPtr("FORT BRAGG"), Ownership: strPtr("PU"), Use: strPtr("PR"),
+					Latitude: strPtr("35-10-15.0000N"), Longitude: strPtr("079-00-52.0000W")}},
+			},
+		},
+		{
+			name:           "Success JSON",
+			format:         dto.ImportFormatJSON,
+			file:           `[{"id": 9, "icao_ident": " kavl ", "city": "ASHEVILLE", "status": "DONE"}]`,
+			expectedFormat: dto.ImportFormatJSON,
+			expectedResult: []AirportImportRow{
+				{Row: 1, Airport: dto.Airport{ICAO: "KAVL", City: strPtr("ASHEVILLE"), Status: "DONE"}},
+			},
+		},
+		{
+			name:           "Error NASR format without NASR columns",
+			format:         dto.ImportFormatNASR,
+			file:           "icao,city\nKAVL,ASHEVILLE\n",
+			expectedFormat: dto.ImportFormatNASR,
+			expectedErr:    fmt.Errorf("Invalid NASR import file, expected APT_BASE columns ARPT_ID and SITE_TYPE_CODE"),
+		},
+		{
+			name:           "Error empty CSV",
+			format:         dto.ImportFormatCSV,
+			file:           "",
+			expectedFormat: dto.ImportFormatCSV,
+			expectedErr:    fmt.Errorf("Invalid CSV import file, missing header: EOF"),
+		},
+		{
+			name:           "Error invalid JSON",
+			format:         dto.ImportFormatJSON,
+			file:           `{"icao_ident": "KAVL"}`,
+			expectedFormat: dto.ImportFormatJSON,
+			expectedErr:    fmt.Errorf("Invalid JSON import file: json: cannot unmarshal object into Go value of type []dto.Airport"),
+		},
+		{
+			name:           "Error unsupported format",
+			format:         "xml",
+			expectedFormat: "xml",
+			expectedErr:    fmt.Errorf(`Unsupported import format "xml"`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, got, err := ParseAirportImport(tt.format, strings.NewReader(tt.file))
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if format != tt.expectedFormat {
+				t.Errorf("Expected format %v, got %v", tt.expectedFormat, format)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedResult) {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+		})
+	}
+}
+
+func TestImportFormatFromFilename(t *testing.T) {
+	if got := ImportFormatFromFilename("airports.JSON"); got != dto.ImportFormatJSON {
+		t.Errorf("Expected format %v, got %v", dto.ImportFormatJSON, got)
+	}
+	if got := ImportFormatFromFilename("APT_BASE.csv"); got != dto.ImportFormatCSV {
+		t.Errorf("Expected format %v, got %v", dto.ImportFormatCSV, got)
+	}
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"aviation-service/internal/dto"
+	"fmt"
+)
+
+// MergeAirport applies upstream data onto the stored airport. Fields curated by hand are overwritten only with
+// upstream-wins, the other policies keep them and flag-conflict also reports every non-empty upstream value that differs
+func MergeAirport(existing, upstream *dto.Airport, policy string) (dto.Airport, []dto.AirportConflict) {
+	merged := *upstream
+	merged.ID = existing.ID
+	merged.FieldSources = existing.FieldSources
+	if policy == dto.MergePolicyUpstreamWins {
+		return merged, nil
+	}
+
+	var conflicts []dto.AirportConflict
+	mergedFields := airportFields(&merged)
+	for i, f := range airportFields(existing) {
+		if existing.FieldSources[f.name] != dto.FieldSourceManual {
+			continue
+		}
+		upstreamValue := *mergedFields[i].value
+		*mergedFields[i].value = *f.value
+
+		if policy == dto.MergePolicyFlagConflict && upstreamValue != nil && *upstreamValue != stringValue(*f.value) {
+			conflicts = append(conflicts, dto.AirportConflict{
+				AirportID:     existing.ID,
+				ICAO:          existing.ICAO,
+				Field:         f.name,
+				ManualValue:   *f.value,
+				UpstreamValue: upstreamValue,
+			})
+		}
+	}
+	return merged, conflicts
+}
+
+// FillAirport takes the fields partial does not carry from existing, an import format without manager contacts
+// keeps the stored ones
+func FillAirport(existing, partial *dto.Airport) dto.Airport {
+	filled := *partial
+	filledFields := airportFields(&filled)
+	for i, f := range airportFields(existing) {
+		if *filledFields[i].value == nil {
+			*filledFields[i].value = *f.value
+		}
+	}
+	return filled
+}
+
+// SetAirportField sets one of the optional airport fields by its JSON name
+func SetAirportField(airport *dto.Airport, field string, value *string) error {
+	for _, f := range airportFields(airport) {
+		if f.name == field {
+			*f.value = value
+			return nil
+		}
+	}
+	return fmt.Errorf("Unknown airport field %q", field)
+}
@@ -0,0 +1,99 @@
+package utils_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/utils"
+)
+
+func TestMergeAirport(t *testing.T) {
+	existing := &dto.Airport{ID: 1, ICAO: "KAVL", City: strPtr("OLD CITY"), Manager: strPtr("JANE DOE"),
+		ManagerPhone: strPtr("828-000-0000"), Status: "PENDING",
+		FieldSources: dto.FieldSources{"city": dto.FieldSourceUpstream, "manager": dto.FieldSourceManual, "manager_phone": dto.FieldSourceManual}}
+	upstream := &dto.Airport{ICAO: "KAVL", City: strPtr("ASHEVILLE"), ManagerPhone: strPtr("828-684-2226"), Status: "DONE"}
+
+	tests := []struct {
+		name              string
+		policy            string
+		expected          dto.Airport
+		expectedConflicts []dto.AirportConflict
+	}{
+		{
+			name:   "Upstream wins",
+			policy: dto.MergePolicyUpstreamWins,
+			expected: dto.Airport{ID: 1, ICAO: "KAVL", City: strPtr("ASHEVILLE"), ManagerPhone: strPtr("828-684-2226"),
+				Status: "DONE", FieldSources: existing.FieldSources},
+		},
+		{
+			name:   "Manual wins",
+			policy: dto.MergePolicyManualWins,
+			expected: dto.Airport{ID: 1, ICAO: "KAVL", City: strPtr("ASHEVILLE"), Manager: strPtr("JANE DOE"),
+				ManagerPhone: strPtr("828-000-0000"), Status: "DONE", FieldSources: existing.FieldSources},
+		},
+		{
+			name:   "Flag conflict reports differing upstream values only",
+			policy: dto.MergePolicyFlagConflict,
+			expected: dto.Airport{ID: 1, ICAO: "KAVL", City: strPtr("ASHEVILLE"), Manager: strPtr("JANE DOE"),
+				ManagerPhone: strPtr("828-000-0000"), Status: "DONE", FieldSources: existing.FieldSources},
+			expectedConflicts: []dto.AirportConflict{{AirportID: 1, ICAO: "KAVL", Field: "manager_phone",
+				ManualValue: strPtr("828-000-0000"), UpstreamValue: strPtr("828-684-2226")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := MergeAirport(existing, upstream, tt.policy)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected airport %+v, got %+v", tt.expected, got)
+			}
+			if !reflect.DeepEqual(conflicts, tt.expectedConflicts) {
+				t.Errorf("Expected conflicts %+v, got %+v", tt.expectedConflicts, conflicts)
+			}
+		})
+	}
+}
+
+func TestSetAirportField(t *testing.T) {
+	tests := []struct {
+		name        string
+		field       string
+		value       *string
+		expected    dto.Airport
+		expectedErr error
+	}{
+		{
+			name:     "Set field",
+			field:    "manager_phone",
+			value:    strPtr("828-684-2226"),
+			expected: dto.Airport{ICAO: "KAVL", City: strPtr("ASHEVILLE"), ManagerPhone: strPtr("828-684-2226")},
+		},
+		{
+			name:     "Clear field",
+			field:    "city",
+			expected: dto.Airport{ICAO: "KAVL"},
+		},
+		{
+			name:        "Unknown field",
+			field:       "status",
+			value:       strPtr("DONE"),
+			expected:    dto.Airport{ICAO: "KAVL", City: strPtr("ASHEVILLE")},
+			expectedErr: fmt.Errorf("Unknown airport field \"status\""),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			airport := dto.Airport{ICAO: "KAVL", City: strPtr("ASHEVILLE")}
+			err := SetAirportField(&airport, tt.field, tt.value)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if !reflect.DeepEqual(airport, tt.expected) {
+				t.Errorf("Expected airport %+v, got %+v", tt.expected, airport)
+			}
+		})
+	}
+}
@@ -2,11 +2,12 @@ package utils
 
 import (
 	"aviation-service/internal/dto"
-	"errors"
 	"regexp"
 	"strings"
 )
 
+var managerPhonePattern = regexp.MustCompile(`^\+?[0-9\-\(\)\s]{7,20}$`)
+
 type AirportValidator struct {}
 
 func NewAirportValidator() *AirportValidator {
@@ -30,42 +31,38 @@ func (v *AirportValidator) IsComplete(req *dto.Airport) bool {
 		req.Longitude != nil && *req.Longitude != ""
 }
 
+// Validate checks every field and reports all violations at once as dto.ValidationErrors
 func (v *AirportValidator) Validate(req *dto.Airport) error {
+	var errs dto.ValidationErrors
+	add := func(field, code, message string) {
+		errs = append(errs, dto.FieldError{Field: field, Code: code, Message: message})
+	}
+
 	if strings.TrimSpace(req.ICAO) == "" {
-		return errors.New("ICAO is required")
+		add("icao_ident", dto.ValidationCodeRequired, "ICAO is required")
 	}
 
 	// Ownership & Use must be PU or PR
 	if req.Ownership != nil && *req.Ownership != "" && *req.Ownership != "PU" && *req.Ownership != "PR" {
-		return errors.New("Ownership must be PU or PR")
+		add("ownership", dto.ValidationCodeInvalidValue, "Ownership must be PU or PR")
 	}
-	if  req.Use != nil && *req.Use != "" && *req.Use != "PU" && *req.Use != "PR" {
-		return errors.New("Use must be PU or PR")
+	if req.Use != nil && *req.Use != "" && *req.Use != "PU" && *req.Use != "PR" {
+		add("use", dto.ValidationCodeInvalidValue, "Use must be PU or PR")
 	}
 
-	// Latitude regex
-	if req.Latitude != nil && *req.Latitude != "" {
-		latPattern := regexp.MustCompile(`^\d{2}-\d{2}-\d{2}(\.\d+)?[NS]$`)
-		if !latPattern.MatchString(*req.Latitude) {
-			return errors.New("Invalid latitude format (expected DD-MM-SS.sssN/S)")
-		}
+	// Checked with the patterns ParseLatitude and ParseLongitude use
+	if req.Latitude != nil && *req.Latitude != "" && !latitudePattern.MatchString(*req.Latitude) {
+		add("latitude", dto.ValidationCodeInvalidFormat, "Invalid latitude format (expected DD-MM-SS.sssN/S)")
 	}
-
-	// Longitude regex
-	if req.Longitude != nil && *req.Longitude != "" {
-		longPattern := regexp.MustCompile(`^\d{3}-\d{2}-\d{2}(\.\d+)?[EW]$`)
-		if !longPattern.MatchString(*req.Longitude) {
-			return errors.New("Invalid longitude format (expected DDD-MM-SS.sssE/W)")
-		}
+	if req.Longitude != nil && *req.Longitude != "" && !longitudePattern.MatchString(*req.Longitude) {
+		add("longitude", dto.ValidationCodeInvalidFormat, "Invalid longitude format (expected DDD-MM-SS.sssE/W)")
 	}
-
-	// Manager phone regex
-	if req.ManagerPhone != nil && *req.ManagerPhone != "" {
-		phonePattern := regexp.MustCompile(`^\+?[0-9\-\(\)\s]{7,20}$`)
-		if !phonePattern.MatchString(*req.ManagerPhone) {
-			return errors.New("Invalid manager phone format")
-		}
+	if req.ManagerPhone != nil && *req.ManagerPhone != "" && !managerPhonePattern.MatchString(*req.ManagerPhone) {
+		add("manager_phone", dto.ValidationCodeInvalidFormat, "Invalid manager phone format")
 	}
 
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
-}
\ No newline at end of file
+}
@@ -1,7 +1,9 @@
 package utils_test
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"aviation-service/internal/dto"
@@ -112,3 +114,28 @@ func TestAirportValidator_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestAirportValidator_Validate_CollectsViolations(t *testing.T) {
+	falseOwnership := "A"
+	falseLatitude := "1"
+	falseManagerPhone := "62"
+	airport := &dto.Airport{Ownership: &falseOwnership, Use: &use, Latitude: &falseLatitude, Longitude: &longitude, ManagerPhone: &falseManagerPhone}
+
+	err := NewAirportValidator().Validate(airport)
+	var got dto.ValidationErrors
+	if !errors.As(err, &got) {
+		t.Fatalf("Expected validation errors, got %v", err)
+	}
+	expected := dto.ValidationErrors{
+		{Field: "icao_ident", Code: dto.ValidationCodeRequired, Message: "ICAO is required"},
+		{Field: "ownership", Code: dto.ValidationCodeInvalidValue, Message: "Ownership must be PU or PR"},
+		{Field: "latitude", Code: dto.ValidationCodeInvalidFormat, Message: "Invalid latitude format (expected DD-MM-SS.sssN/S)"},
+		{Field: "manager_phone", Code: dto.ValidationCodeInvalidFormat, Message: "Invalid manager phone format"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected violations %+v, got %+v", expected, got)
+	}
+	if err.Error() != "ICAO is required; Ownership must be PU or PR; Invalid latitude format (expected DD-MM-SS.sssN/S); Invalid manager phone format" {
+		t.Errorf("Expected the messages joined, got %q", err.Error())
+	}
+}
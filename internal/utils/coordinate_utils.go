@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+const EarthRadiusNm = 3440.065
+
+var (
+	latitudePattern  = regexp.MustCompile(`^(\d{2})-(\d{2})-(\d{2}(?:\.\d+)?)([NS])$`)
+	longitudePattern = regexp.MustCompile(`^(\d{3})-(\d{2})-(\d{2}(?:\.\d+)?)([EW])$`)
+)
+
+// ParseLatitude converts a DMS latitude such as 33-06-24.2800N into decimal degrees
+func ParseLatitude(dms string) (float64, error) {
+	match := latitudePattern.FindStringSubmatch(dms)
+	if match == nil {
+		return 0, errors.New("Invalid latitude format (expected DD-MM-SS.sssN/S)")
+	}
+	deg, err := dmsToDecimal(match[1], match[2], match[3], match[4] == "S")
+	if err != nil || math.Abs(deg) > 90 {
+		return 0, errors.New("Latitude out of range")
+	}
+	return deg, nil
+}
+
+// ParseLongitude converts a DMS longitude such as 088-11-49.8300W into decimal degrees
+func ParseLongitude(dms string) (float64, error) {
+	match := longitudePattern.FindStringSubmatch(dms)
+	if match == nil {
+		return 0, errors.New("Invalid longitude format (expected DDD-MM-SS.sssE/W)")
+	}
+	deg, err := dmsToDecimal(match[1], match[2], match[3], match[4] == "W")
+	if err != nil || math.Abs(deg) > 180 {
+		return 0, errors.New("Longitude out of range")
+	}
+	return deg, nil
+}
+
+// FormatLatitude converts decimal degrees into the DMS form stored in the airport table, such as 33-06-24.2800N
+func FormatLatitude(deg float64) string {
+	return decimalToDMS(deg, 2, "N", "S")
+}
+
+// FormatLongitude converts decimal degrees into the DMS form stored in the airport table, such as 088-11-49.8300W
+func FormatLongitude(deg float64) string {
+	return decimalToDMS(deg, 3, "E", "W")
+}
+
+// DistanceNm returns the great-circle distance between two points in nautical miles
+func DistanceNm(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	a := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Pow(math.Sin(dLon/2), 2)
+	return EarthRadiusNm * 2 * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func dmsToDecimal(degrees, minutes, seconds string, negative bool) (float64, error) {
+	d, err := strconv.ParseFloat(degrees, 64)
+	if err != nil {
+		return 0, err
+	}
+	m, err := strconv.ParseFloat(minutes, 64)
+	if err != nil {
+		return 0, err
+	}
+	s, err := strconv.ParseFloat(seconds, 64)
+	if err != nil {
+		return 0, err
+	}
+	if m >= 60 || s >= 60 {
+		return 0, errors.New("Minutes and seconds must be below 60")
+	}
+
+	deg := d + m/60 + s/3600
+	if negative {
+		deg = -deg
+	}
+	return deg, nil
+}
+
+func decimalToDMS(deg float64, degreeWidth int, positive, negative string) string {
+	hemisphere := positive
+	if deg < 0 {
+		hemisphere = negative
+		deg = -deg
+	}
+
+	// Work in ten-thousandths of a second so rounding carries into minutes and degrees
+	total := int64(math.Round(deg * 3600 * 10000))
+	degrees := total / (3600 * 10000)
+	total %= 3600 * 10000
+	minutes := total / (60 * 10000)
+	total %= 60 * 10000
+	return fmt.Sprintf("%0*d-%02d-%02d.%04d%s", degreeWidth, degrees, minutes, total/10000, total%10000, hemisphere)
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
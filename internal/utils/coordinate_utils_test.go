@@ -0,0 +1,160 @@
+package utils_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	. "aviation-service/internal/utils"
+)
+
+func TestParseLatitude(t *testing.T) {
+	tests := []struct {
+		name           string
+		dms            string
+		expectedResult float64
+		expectedErr    error
+	}{
+		{
+			name:           "Success parse north latitude",
+			dms:            "33-06-24.2800N",
+			expectedResult: 33.106744,
+		},
+		{
+			name:           "Success parse south latitude",
+			dms:            "12-30-00S",
+			expectedResult: -12.5,
+		},
+		{
+			name:        "Error invalid latitude format",
+			dms:         "33.1",
+			expectedErr: fmt.Errorf("Invalid latitude format (expected DD-MM-SS.sssN/S)"),
+		},
+		{
+			name:        "Error latitude out of range",
+			dms:         "95-00-00N",
+			expectedErr: fmt.Errorf("Latitude out of range"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLatitude(tt.dms)
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if math.Abs(got-tt.expectedResult) > 1e-6 {
+				t.Errorf("Expected result %v, got %v", tt.expectedResult, got)
+			}
+		})
+	}
+}
+
+func TestParseLongitude(t *testing.T) {
+	tests := []struct {
+		name           string
+		dms            string
+		expectedResult float64
+		expectedErr    error
+	}{
+		{
+			name:           "Success parse west longitude",
+			dms:            "088-11-49.8300W",
+			expectedResult: -88.197175,
+		},
+		{
+			name:           "Success parse east longitude",
+			dms:            "106-39-21.0000E",
+			expectedResult: 106.655833,
+		},
+		{
+			name:        "Error invalid longitude format",
+			dms:         "88-11-49.8300W",
+			expectedErr: fmt.Errorf("Invalid longitude format (expected DDD-MM-SS.sssE/W)"),
+		},
+		{
+			name:        "Error longitude minutes out of range",
+			dms:         "088-61-00.0000W",
+			expectedErr: fmt.Errorf("Longitude out of range"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLongitude(tt.dms)
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if math.Abs(got-tt.expectedResult) > 1e-6 {
+				t.Errorf("Expected result %v, got %v", tt.expectedResult, got)
+			}
+		})
+	}
+}
+
+func TestDistanceNm(t *testing.T) {
+	tests := []struct {
+		name           string
+		lat1, lon1     float64
+		lat2, lon2     float64
+		expectedResult float64
+	}{
+		{
+			name:           "Success same point",
+			lat1:           33.1,
+			lon1:           -88.2,
+			lat2:           33.1,
+			lon2:           -88.2,
+			expectedResult: 0,
+		},
+		{
+			name:           "Success one degree of latitude",
+			lat1:           10,
+			lon1:           20,
+			lat2:           11,
+			lon2:           20,
+			expectedResult: 60.04,
+		},
+		{
+			name:           "Success KLAX to KJFK",
+			lat1:           33.9425,
+			lon1:           -118.408056,
+			lat2:           40.639722,
+			lon2:           -73.778889,
+			expectedResult: 2145.9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistanceNm(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+			if math.Abs(got-tt.expectedResult) > 0.5 {
+				t.Errorf("Expected result %v, got %v", tt.expectedResult, got)
+			}
+		})
+	}
+}
+
+func TestFormatCoordinates(t *testing.T) {
+	tests := []struct {
+		name           string
+		format         func(float64) string
+		deg            float64
+		expectedResult string
+	}{
+		{name: "Success north latitude", format: FormatLatitude, deg: 33.106744, expectedResult: "33-06-24.2784N"},
+		{name: "Success south latitude", format: FormatLatitude, deg: -12.5, expectedResult: "12-30-00.0000S"},
+		{name: "Success west longitude", format: FormatLongitude, deg: -88.197175, expectedResult: "088-11-49.8300W"},
+		{name: "Success rounding carries into minutes", format: FormatLongitude, deg: 10.99999999, expectedResult: "011-00-00.0000E"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format(tt.deg); got != tt.expectedResult {
+				t.Errorf("Expected result %v, got %v", tt.expectedResult, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"aviation-service/internal/dto"
+)
+
+const kilometersPerStatuteMile = 1.609344
+
+// FlightCategory applies the FAA ceiling and visibility thresholds, the worse of the two decides.
+// An unknown ceiling or visibility does not lower the category, both unknown returns an empty string.
+func FlightCategory(ceilingFt *int, visibilitySm *float64) string {
+	if ceilingFt == nil && visibilitySm == nil {
+		return ""
+	}
+
+	switch {
+	case ceilingFt != nil && *ceilingFt < 500, visibilitySm != nil && *visibilitySm < 1:
+		return dto.FlightCategoryLIFR
+	case ceilingFt != nil && *ceilingFt < 1000, visibilitySm != nil && *visibilitySm < 3:
+		return dto.FlightCategoryIFR
+	case ceilingFt != nil && *ceilingFt <= 3000, visibilitySm != nil && *visibilitySm <= 5:
+		return dto.FlightCategoryMVFR
+	default:
+		return dto.FlightCategoryVFR
+	}
+}
+
+// CeilingFt returns the base of the lowest broken, overcast or vertical visibility layer, nil when there is no ceiling
+func CeilingFt(clouds []dto.CloudLayer) *int {
+	var ceiling *int
+	for _, layer := range clouds {
+		if layer.Cover != "BKN" && layer.Cover != "OVC" && layer.Cover != "VV" {
+			continue
+		}
+		if layer.BaseFt != nil && (ceiling == nil || *layer.BaseFt < *ceiling) {
+			ceiling = layer.BaseFt
+		}
+	}
+	return ceiling
+}
+
+// MetarFlightCategory derives the flight category from a decoded METAR, a sky without a ceiling counts as unlimited
+func MetarFlightCategory(metar *dto.Metar) string {
+	if metar == nil || (metar.Visibility == nil && len(metar.Clouds) == 0) {
+		return ""
+	}
+
+	var visibilitySm *float64
+	if metar.Visibility != nil {
+		visibilitySm = &metar.Visibility.StatuteMiles
+	}
+	ceiling := CeilingFt(metar.Clouds)
+	if ceiling == nil {
+		// Reported clear sky or only FEW/SCT layers, so the ceiling cannot lower the category
+		unlimited := 99999
+		ceiling = &unlimited
+	}
+	return FlightCategory(ceiling, visibilitySm)
+}
+
+// WeatherFlightCategory derives the flight category from WeatherAPI data, which has no cloud base so only visibility is used
+func WeatherFlightCategory(weather *dto.Weather) string {
+	if weather == nil {
+		return ""
+	}
+	visibilitySm := weather.VisKm / kilometersPerStatuteMile
+	return FlightCategory(nil, &visibilitySm)
+}
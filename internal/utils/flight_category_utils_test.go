@@ -0,0 +1,100 @@
+package utils_test
+
+import (
+	"testing"
+
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/utils"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestFlightCategory(t *testing.T) {
+	tests := []struct {
+		name           string
+		ceilingFt      *int
+		visibilitySm   *float64
+		expectedResult string
+	}{
+		{name: "VFR", ceilingFt: intPtr(5000), visibilitySm: floatPtr(10), expectedResult: dto.FlightCategoryVFR},
+		{name: "MVFR by ceiling", ceilingFt: intPtr(3000), visibilitySm: floatPtr(10), expectedResult: dto.FlightCategoryMVFR},
+		{name: "MVFR by visibility", ceilingFt: intPtr(5000), visibilitySm: floatPtr(5), expectedResult: dto.FlightCategoryMVFR},
+		{name: "IFR by ceiling", ceilingFt: intPtr(500), visibilitySm: floatPtr(10), expectedResult: dto.FlightCategoryIFR},
+		{name: "IFR by visibility", ceilingFt: intPtr(5000), visibilitySm: floatPtr(1), expectedResult: dto.FlightCategoryIFR},
+		{name: "LIFR worse of both", ceilingFt: intPtr(2000), visibilitySm: floatPtr(0.5), expectedResult: dto.FlightCategoryLIFR},
+		{name: "Visibility only", visibilitySm: floatPtr(2), expectedResult: dto.FlightCategoryIFR},
+		{name: "Unknown", expectedResult: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FlightCategory(tt.ceilingFt, tt.visibilitySm); got != tt.expectedResult {
+				t.Errorf("Expected result %q, got %q", tt.expectedResult, got)
+			}
+		})
+	}
+}
+
+func TestMetarFlightCategory(t *testing.T) {
+	tests := []struct {
+		name           string
+		metar          *dto.Metar
+		expectedResult string
+	}{
+		{
+			name: "Ceiling from lowest broken layer",
+			metar: &dto.Metar{Conditions: dto.Conditions{
+				Visibility: &dto.Visibility{StatuteMiles: 10},
+				Clouds: []dto.CloudLayer{
+					{Cover: "FEW", BaseFt: intPtr(400)},
+					{Cover: "BKN", BaseFt: intPtr(800)},
+					{Cover: "OVC", BaseFt: intPtr(1200)},
+				},
+			}},
+			expectedResult: dto.FlightCategoryIFR,
+		},
+		{
+			name: "Vertical visibility counts as ceiling",
+			metar: &dto.Metar{Conditions: dto.Conditions{
+				Visibility: &dto.Visibility{StatuteMiles: 0.5},
+				Clouds:     []dto.CloudLayer{{Cover: "VV", BaseFt: intPtr(200)}},
+			}},
+			expectedResult: dto.FlightCategoryLIFR,
+		},
+		{
+			name: "Clear sky",
+			metar: &dto.Metar{Conditions: dto.Conditions{
+				Visibility: &dto.Visibility{StatuteMiles: 10},
+				Clouds:     []dto.CloudLayer{{Cover: "CLR"}},
+			}},
+			expectedResult: dto.FlightCategoryVFR,
+		},
+		{
+			name:           "No visibility or clouds reported",
+			metar:          &dto.Metar{},
+			expectedResult: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MetarFlightCategory(tt.metar); got != tt.expectedResult {
+				t.Errorf("Expected result %q, got %q", tt.expectedResult, got)
+			}
+		})
+	}
+}
+
+func TestWeatherFlightCategory(t *testing.T) {
+	if got := WeatherFlightCategory(&dto.Weather{VisKm: 16}); got != dto.FlightCategoryVFR {
+		t.Errorf("Expected result %q, got %q", dto.FlightCategoryVFR, got)
+	}
+	if got := WeatherFlightCategory(&dto.Weather{VisKm: 4}); got != dto.FlightCategoryIFR {
+		t.Errorf("Expected result %q, got %q", dto.FlightCategoryIFR, got)
+	}
+	if got := WeatherFlightCategory(nil); got != "" {
+		t.Errorf("Expected empty result, got %q", got)
+	}
+}
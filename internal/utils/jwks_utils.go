@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// SigningKey is a public key of a JSON Web Key Set, Algorithm is empty when the key does not pin one
+type SigningKey struct {
+	Key       crypto.PublicKey
+	Algorithm string
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+var jwkCurves = map[string]elliptic.Curve{
+	"P-256": elliptic.P256(),
+	"P-384": elliptic.P384(),
+	"P-521": elliptic.P521(),
+}
+
+// ParseJWKS reads the RSA and EC signature keys of a JSON Web Key Set by key ID. Encryption keys and key types
+// tokens are not signed with (oct, OKP) are skipped, a set without any usable key is an error
+func ParseJWKS(data []byte) (map[string]SigningKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("Invalid JWKS: %s", err)
+	}
+
+	keys := map[string]SigningKey{}
+	for i, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		var key crypto.PublicKey
+		var err error
+		switch jwk.Kty {
+		case "RSA":
+			key, err = parseRSAKey(jwk)
+		case "EC":
+			key, err = parseECKey(jwk)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid JWKS key %d (kid %q): %s", i, jwk.Kid, err)
+		}
+		keys[jwk.Kid] = SigningKey{Key: key, Algorithm: jwk.Alg}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS has no RSA or EC signing key")
+	}
+	return keys, nil
+}
+
+func parseRSAKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := decodeJWKInt(jwk.N)
+	if err != nil {
+		return nil, fmt.Errorf("modulus: %s", err)
+	}
+	e, err := decodeJWKInt(jwk.E)
+	if err != nil {
+		return nil, fmt.Errorf("exponent: %s", err)
+	}
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("exponent out of range")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func parseECKey(jwk jsonWebKey) (*ecdsa.PublicKey, error) {
+	curve, ok := jwkCurves[jwk.Crv]
+	if !ok {
+		return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+	}
+	x, err := decodeJWKInt(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("x: %s", err)
+	}
+	y, err := decodeJWKInt(jwk.Y)
+	if err != nil {
+		return nil, fmt.Errorf("y: %s", err)
+	}
+	key := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+	if _, err := key.ECDH(); err != nil {
+		return nil, fmt.Errorf("point is not on curve %s", jwk.Crv)
+	}
+	return key, nil
+}
+
+// decodeJWKInt decodes a base64url big-endian integer without padding, as JWK encodes key parameters
+func decodeJWKInt(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, fmt.Errorf("missing")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
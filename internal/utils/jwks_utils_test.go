@@ -0,0 +1,101 @@
+package utils_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"testing"
+
+	. "aviation-service/internal/utils"
+)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaJWK := fmt.Sprintf(`{"kty": "RSA", "kid": "rsa-1", "use": "sig", "alg": "RS256", "n": %q, "e": %q}`,
+		b64(rsaKey.N.Bytes()), b64(big.NewInt(int64(rsaKey.E)).Bytes()))
+	ecJWK := fmt.Sprintf(`{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": %q, "y": %q}`,
+		b64(ecKey.X.Bytes()), b64(ecKey.Y.Bytes()))
+
+	tests := []struct {
+		name         string
+		jwks         string
+		expectedKIDs []string
+		expectedErr  error
+	}{
+		{
+			name:         "Success RSA and EC keys",
+			jwks:         `{"keys": [` + rsaJWK + `, ` + ecJWK + `]}`,
+			expectedKIDs: []string{"rsa-1", "ec-1"},
+		},
+		{
+			name:         "Skip encryption and symmetric keys",
+			jwks:         `{"keys": [` + rsaJWK + `, {"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"}, {"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"}]}`,
+			expectedKIDs: []string{"rsa-1"},
+		},
+		{
+			name:        "Error no signing key",
+			jwks:        `{"keys": [{"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"}]}`,
+			expectedErr: fmt.Errorf("JWKS has no RSA or EC signing key"),
+		},
+		{
+			name:        "Error not JSON",
+			jwks:        `keys`,
+			expectedErr: fmt.Errorf("Invalid JWKS: invalid character 'k' looking for beginning of value"),
+		},
+		{
+			name:        "Error missing modulus",
+			jwks:        `{"keys": [{"kty": "RSA", "kid": "rsa-1", "e": "AQAB"}]}`,
+			expectedErr: fmt.Errorf("Invalid JWKS key 0 (kid \"rsa-1\"): modulus: missing"),
+		},
+		{
+			name:        "Error point not on curve",
+			jwks:        `{"keys": [{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": "AQ", "y": "AQ"}]}`,
+			expectedErr: fmt.Errorf("Invalid JWKS key 0 (kid \"ec-1\"): point is not on curve P-256"),
+		},
+		{
+			name:        "Error unsupported curve",
+			jwks:        `{"keys": [{"kty": "EC", "kid": "ec-1", "crv": "secp256k1", "x": "AQ", "y": "AQ"}]}`,
+			expectedErr: fmt.Errorf("Invalid JWKS key 0 (kid \"ec-1\"): unsupported curve \"secp256k1\""),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseJWKS([]byte(tt.jwks))
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if len(keys) != len(tt.expectedKIDs) {
+				t.Fatalf("Expected keys %v, got %v", tt.expectedKIDs, keys)
+			}
+			for _, kid := range tt.expectedKIDs {
+				if _, ok := keys[kid]; !ok {
+					t.Errorf("Expected key %q, got %v", kid, keys)
+				}
+			}
+		})
+	}
+
+	keys, _ := ParseJWKS([]byte(`{"keys": [` + rsaJWK + `, ` + ecJWK + `]}`))
+	if !rsaKey.PublicKey.Equal(keys["rsa-1"].Key) || keys["rsa-1"].Algorithm != "RS256" {
+		t.Errorf("Expected the RSA public key pinned to RS256, got %+v", keys["rsa-1"])
+	}
+	if !ecKey.PublicKey.Equal(keys["ec-1"].Key) || keys["ec-1"].Algorithm != "" {
+		t.Errorf("Expected the EC public key, got %+v", keys["ec-1"])
+	}
+}
@@ -0,0 +1,379 @@
+package utils
+
+import (
+	"aviation-service/internal/dto"
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	metersPerStatuteMile = 1609.344
+	hPaPerInHg           = 33.8639
+	knotsPerMps          = 1.94384
+)
+
+var (
+	stationPattern            = regexp.MustCompile(`^[A-Z][A-Z0-9]{3}$`)
+	reportTimePattern         = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2})Z$`)
+	windPattern               = regexp.MustCompile(`^(\d{3}|VRB)(\d{2,3})(?:G(\d{2,3}))?(KT|MPS)$`)
+	windVariationPattern      = regexp.MustCompile(`^(\d{3})V(\d{3})$`)
+	visibilityMetersPattern   = regexp.MustCompile(`^(\d{4})(?:NDV)?$`)
+	visibilityMilesPattern    = regexp.MustCompile(`^([MP])?(\d{1,2})SM$`)
+	visibilityFractionPattern = regexp.MustCompile(`^(M)?(\d)/(\d{1,2})SM$`)
+	wholeMilesPattern         = regexp.MustCompile(`^\d$`)
+	runwayVisualRangePattern  = regexp.MustCompile(`^R\d{2}[LRC]?/`)
+	weatherPattern            = regexp.MustCompile(`^(?:-|\+|VC)?(?:MI|PR|BC|DR|BL|SH|TS|FZ)?(?:DZ|RA|SN|SG|IC|PL|GR|GS|UP|BR|FG|FU|VA|DU|SA|HZ|PY|PO|SQ|FC|SS|DS)*$`)
+	cloudPattern              = regexp.MustCompile(`^(FEW|SCT|BKN|OVC|VV)(\d{3}|///)(CB|TCU)?$`)
+	temperaturePattern        = regexp.MustCompile(`^(M?\d{2})/(M?\d{2})?$`)
+	altimeterPattern          = regexp.MustCompile(`^([AQ])(\d{4})$`)
+	tafValidityPattern        = regexp.MustCompile(`^(\d{2})(\d{2})/(\d{2})(\d{2})$`)
+	tafFromPattern            = regexp.MustCompile(`^FM(\d{2})(\d{2})(\d{2})$`)
+	tafProbabilityPattern     = regexp.MustCompile(`^PROB(\d{2})$`)
+)
+
+// ParseMetar decodes a raw METAR or SPECI, now is used to place the day-of-month timestamp in the right month
+func ParseMetar(raw string, now time.Time) (*dto.Metar, error) {
+	tokens := reportTokens(raw)
+	metar := &dto.Metar{Type: "METAR", Raw: strings.TrimSpace(raw)}
+
+	i := 0
+	if i < len(tokens) && (tokens[i] == "METAR" || tokens[i] == "SPECI") {
+		metar.Type = tokens[i]
+		i++
+	}
+	if i < len(tokens) && tokens[i] == "COR" {
+		i++
+	}
+	if i >= len(tokens) || !stationPattern.MatchString(tokens[i]) {
+		return nil, errors.New("Invalid METAR report, missing station")
+	}
+	metar.Station = tokens[i]
+	i++
+
+	if i >= len(tokens) {
+		return nil, errors.New("Invalid METAR report, missing observation time")
+	}
+	observedAt, err := parseReportTime(tokens[i], now)
+	if err != nil {
+		return nil, err
+	}
+	metar.ObservedAt = observedAt
+	i++
+
+	for ; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token == "AUTO":
+			metar.Auto = true
+		case token == "RMK":
+			metar.Remarks = strings.Join(tokens[i+1:], " ")
+			i = len(tokens)
+		case token == "NOSIG" || token == "BECMG" || token == "TEMPO":
+			end := remarksIndex(tokens, i)
+			metar.Trend = strings.Join(tokens[i:end], " ")
+			i = end - 1
+		case temperaturePattern.MatchString(token):
+			match := temperaturePattern.FindStringSubmatch(token)
+			metar.TemperatureC = parseTemperature(match[1])
+			metar.DewpointC = parseTemperature(match[2])
+		case altimeterPattern.MatchString(token):
+			metar.Altimeter = parseAltimeter(token)
+		default:
+			if consumed := parseConditions(tokens, i, &metar.Conditions); consumed > 1 {
+				i += consumed - 1
+			}
+		}
+	}
+	return metar, nil
+}
+
+// ParseTaf decodes a raw TAF into its base forecast followed by FM, BECMG, TEMPO and PROB change groups
+func ParseTaf(raw string, now time.Time) (*dto.Taf, error) {
+	tokens := reportTokens(raw)
+	taf := &dto.Taf{Raw: strings.TrimSpace(raw)}
+
+	i := 0
+	if i < len(tokens) && tokens[i] == "TAF" {
+		i++
+	}
+	for i < len(tokens) && (tokens[i] == "AMD" || tokens[i] == "COR") {
+		taf.Amended = taf.Amended || tokens[i] == "AMD"
+		i++
+	}
+	if i >= len(tokens) || !stationPattern.MatchString(tokens[i]) {
+		return nil, errors.New("Invalid TAF report, missing station")
+	}
+	taf.Station = tokens[i]
+	i++
+
+	if i >= len(tokens) {
+		return nil, errors.New("Invalid TAF report, missing issue time")
+	}
+	issuedAt, err := parseReportTime(tokens[i], now)
+	if err != nil {
+		return nil, err
+	}
+	taf.IssuedAt = issuedAt
+	i++
+
+	if i >= len(tokens) || !tafValidityPattern.MatchString(tokens[i]) {
+		return nil, errors.New("Invalid TAF report, missing validity period")
+	}
+	taf.ValidFrom, taf.ValidTo, err = parseValidity(tokens[i], issuedAt)
+	if err != nil {
+		return nil, err
+	}
+	i++
+
+	validFrom := taf.ValidFrom
+	current := dto.TafForecast{Change: dto.TafChangeBase, From: &validFrom}
+	for ; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token == "RMK":
+			taf.Remarks = strings.Join(tokens[i+1:], " ")
+			i = len(tokens)
+		case tafFromPattern.MatchString(token):
+			match := tafFromPattern.FindStringSubmatch(token)
+			from, err := resolveReportTime(issuedAt, atoi(match[1]), atoi(match[2]), atoi(match[3]))
+			if err != nil {
+				return nil, err
+			}
+			taf.Forecasts = append(taf.Forecasts, current)
+			current = dto.TafForecast{Change: dto.TafChangeFrom, From: &from}
+		case token == "BECMG" || token == "TEMPO" || tafProbabilityPattern.MatchString(token):
+			taf.Forecasts = append(taf.Forecasts, current)
+			current = dto.TafForecast{Change: token}
+			if match := tafProbabilityPattern.FindStringSubmatch(token); match != nil {
+				probability := atoi(match[1])
+				current.Change = dto.TafChangeProb
+				current.Probability = &probability
+				if i+1 < len(tokens) && tokens[i+1] == "TEMPO" {
+					current.Change = dto.TafChangeTempo
+					i++
+				}
+			}
+			if i+1 < len(tokens) && tafValidityPattern.MatchString(tokens[i+1]) {
+				from, to, err := parseValidity(tokens[i+1], issuedAt)
+				if err != nil {
+					return nil, err
+				}
+				current.From, current.To = &from, &to
+				i++
+			}
+		default:
+			if consumed := parseConditions(tokens, i, &current.Conditions); consumed > 1 {
+				i += consumed - 1
+			}
+		}
+	}
+	taf.Forecasts = append(taf.Forecasts, current)
+
+	// The base and FM periods run until the next FM group or the end of the TAF validity
+	for idx := range taf.Forecasts {
+		forecast := &taf.Forecasts[idx]
+		if forecast.Change != dto.TafChangeBase && forecast.Change != dto.TafChangeFrom {
+			continue
+		}
+		to := taf.ValidTo
+		for _, next := range taf.Forecasts[idx+1:] {
+			if next.Change == dto.TafChangeFrom {
+				to = *next.From
+				break
+			}
+		}
+		forecast.To = &to
+	}
+	return taf, nil
+}
+
+// parseConditions decodes a wind, visibility, weather or cloud group starting at tokens[i] and returns how many tokens it consumed
+func parseConditions(tokens []string, i int, c *dto.Conditions) int {
+	token := tokens[i]
+	switch {
+	case token == "CAVOK":
+		c.Visibility = metersVisibility(10000, dto.VisibilityGreaterThan)
+	case windPattern.MatchString(token):
+		c.Wind = parseWind(token)
+	case windVariationPattern.MatchString(token) && c.Wind != nil:
+		match := windVariationPattern.FindStringSubmatch(token)
+		from, to := atoi(match[1]), atoi(match[2])
+		c.Wind.VariableFromDeg, c.Wind.VariableToDeg = &from, &to
+	case visibilityMetersPattern.MatchString(token):
+		meters := atoi(visibilityMetersPattern.FindStringSubmatch(token)[1])
+		if meters == 9999 {
+			c.Visibility = metersVisibility(10000, dto.VisibilityGreaterThan)
+		} else {
+			c.Visibility = metersVisibility(meters, "")
+		}
+	case visibilityMilesPattern.MatchString(token):
+		match := visibilityMilesPattern.FindStringSubmatch(token)
+		c.Visibility = milesVisibility(float64(atoi(match[2])), visibilityQualifier(match[1]))
+	case wholeMilesPattern.MatchString(token) && i+1 < len(tokens) && visibilityFractionPattern.MatchString(tokens[i+1]):
+		// Split visibility such as "1 1/2SM"
+		match := visibilityFractionPattern.FindStringSubmatch(tokens[i+1])
+		miles := float64(atoi(token)) + float64(atoi(match[2]))/float64(atoi(match[3]))
+		c.Visibility = milesVisibility(miles, "")
+		return 2
+	case visibilityFractionPattern.MatchString(token):
+		match := visibilityFractionPattern.FindStringSubmatch(token)
+		c.Visibility = milesVisibility(float64(atoi(match[2]))/float64(atoi(match[3])), visibilityQualifier(match[1]))
+	case runwayVisualRangePattern.MatchString(token):
+		// Runway visual range is not decoded
+	case token == "SKC" || token == "CLR" || token == "NSC" || token == "NCD":
+		c.Clouds = append(c.Clouds, dto.CloudLayer{Cover: token})
+	case cloudPattern.MatchString(token):
+		match := cloudPattern.FindStringSubmatch(token)
+		layer := dto.CloudLayer{Cover: match[1], Type: match[3]}
+		if match[2] != "///" {
+			base := atoi(match[2]) * 100
+			layer.BaseFt = &base
+		}
+		c.Clouds = append(c.Clouds, layer)
+	case token == "NSW" || weatherPattern.MatchString(token):
+		c.Weather = append(c.Weather, token)
+	default:
+		return 0
+	}
+	return 1
+}
+
+func parseWind(token string) *dto.Wind {
+	match := windPattern.FindStringSubmatch(token)
+	wind := &dto.Wind{SpeedKt: windSpeedKt(match[2], match[4])}
+	if match[1] == "VRB" {
+		wind.Variable = true
+	} else {
+		direction := atoi(match[1])
+		wind.DirectionDeg = &direction
+	}
+	if match[3] != "" {
+		gust := windSpeedKt(match[3], match[4])
+		wind.GustKt = &gust
+	}
+	return wind
+}
+
+func windSpeedKt(speed, unit string) int {
+	if unit == "MPS" {
+		return int(math.Round(float64(atoi(speed)) * knotsPerMps))
+	}
+	return atoi(speed)
+}
+
+func milesVisibility(miles float64, qualifier string) *dto.Visibility {
+	return &dto.Visibility{
+		StatuteMiles: roundTo(miles, 2),
+		Meters:       int(math.Round(miles * metersPerStatuteMile)),
+		Qualifier:    qualifier,
+	}
+}
+
+func metersVisibility(meters int, qualifier string) *dto.Visibility {
+	return &dto.Visibility{
+		StatuteMiles: roundTo(float64(meters)/metersPerStatuteMile, 2),
+		Meters:       meters,
+		Qualifier:    qualifier,
+	}
+}
+
+func visibilityQualifier(prefix string) string {
+	switch prefix {
+	case "M":
+		return dto.VisibilityLessThan
+	case "P":
+		return dto.VisibilityGreaterThan
+	}
+	return ""
+}
+
+func parseTemperature(value string) *int {
+	if value == "" {
+		return nil
+	}
+	temperature := atoi(strings.TrimPrefix(value, "M"))
+	if strings.HasPrefix(value, "M") {
+		temperature = -temperature
+	}
+	return &temperature
+}
+
+func parseAltimeter(token string) *dto.Altimeter {
+	match := altimeterPattern.FindStringSubmatch(token)
+	value := float64(atoi(match[2]))
+	if match[1] == "A" {
+		inHg := value / 100
+		return &dto.Altimeter{InHg: inHg, HPa: roundTo(inHg*hPaPerInHg, 1)}
+	}
+	return &dto.Altimeter{InHg: roundTo(value/hPaPerInHg, 2), HPa: value}
+}
+
+func parseReportTime(token string, now time.Time) (time.Time, error) {
+	match := reportTimePattern.FindStringSubmatch(token)
+	if match == nil {
+		return time.Time{}, errors.New("Invalid report time format (expected DDHHMMZ)")
+	}
+	return resolveReportTime(now, atoi(match[1]), atoi(match[2]), atoi(match[3]))
+}
+
+func parseValidity(token string, issuedAt time.Time) (time.Time, time.Time, error) {
+	match := tafValidityPattern.FindStringSubmatch(token)
+	from, err := resolveReportTime(issuedAt, atoi(match[1]), atoi(match[2]), 0)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := resolveReportTime(issuedAt, atoi(match[3]), atoi(match[4]), 0)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
+
+// resolveReportTime places a day-of-month timestamp in the month closest to the reference time, reports never carry month or year
+func resolveReportTime(reference time.Time, day, hour, minute int) (time.Time, error) {
+	if day < 1 || day > 31 || hour > 24 || minute > 59 {
+		return time.Time{}, errors.New("Invalid report time")
+	}
+
+	reference = reference.UTC()
+	var resolved time.Time
+	for _, offset := range []int{-1, 0, 1} {
+		monthStart := time.Date(reference.Year(), reference.Month()+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+		if day > monthStart.AddDate(0, 1, -1).Day() {
+			continue
+		}
+		// Hour 24 is valid in TAF periods and means midnight at the end of the day
+		candidate := monthStart.AddDate(0, 0, day-1).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+		if resolved.IsZero() || candidate.Sub(reference).Abs() < resolved.Sub(reference).Abs() {
+			resolved = candidate
+		}
+	}
+	return resolved, nil
+}
+
+func reportTokens(raw string) []string {
+	return strings.Fields(strings.TrimSuffix(strings.TrimSpace(raw), "="))
+}
+
+func remarksIndex(tokens []string, from int) int {
+	for i := from; i < len(tokens); i++ {
+		if tokens[i] == "RMK" {
+			return i
+		}
+	}
+	return len(tokens)
+}
+
+func atoi(value string) int {
+	n, _ := strconv.Atoi(value)
+	return n
+}
+
+func roundTo(value float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(value*factor) / factor
+}
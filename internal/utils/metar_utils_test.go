@@ -0,0 +1,241 @@
+package utils_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"aviation-service/internal/dto"
+	. "aviation-service/internal/utils"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func timePtr(v time.Time) *time.Time {
+	return &v
+}
+
+func TestParseMetar(t *testing.T) {
+	now := time.Date(2025, time.October, 16, 19, 10, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		raw            string
+		now            time.Time
+		expectedResult *dto.Metar
+		expectedErr    error
+	}{
+		{
+			name: "Success parse US METAR",
+			raw:  "KAVL 161854Z 36006G15KT 320V040 1 1/2SM -RA BR FEW008 BKN015 OVC030 18/M06 A3012 RMK AO2 SLP204",
+			now:  now,
+			expectedResult: &dto.Metar{
+				Station:    "KAVL",
+				Type:       "METAR",
+				ObservedAt: time.Date(2025, time.October, 16, 18, 54, 0, 0, time.UTC),
+				Conditions: dto.Conditions{
+					Wind:       &dto.Wind{DirectionDeg: intPtr(360), SpeedKt: 6, GustKt: intPtr(15), VariableFromDeg: intPtr(320), VariableToDeg: intPtr(40)},
+					Visibility: &dto.Visibility{StatuteMiles: 1.5, Meters: 2414},
+					Weather:    []string{"-RA", "BR"},
+					Clouds: []dto.CloudLayer{
+						{Cover: "FEW", BaseFt: intPtr(800)},
+						{Cover: "BKN", BaseFt: intPtr(1500)},
+						{Cover: "OVC", BaseFt: intPtr(3000)},
+					},
+				},
+				TemperatureC: intPtr(18),
+				DewpointC:    intPtr(-6),
+				Altimeter:    &dto.Altimeter{InHg: 30.12, HPa: 1020},
+				Remarks:      "AO2 SLP204",
+				Raw:          "KAVL 161854Z 36006G15KT 320V040 1 1/2SM -RA BR FEW008 BKN015 OVC030 18/M06 A3012 RMK AO2 SLP204",
+			},
+		},
+		{
+			name: "Success parse ICAO SPECI with trend",
+			raw:  "SPECI EGLL 302350Z AUTO VRB03KT 0800 FG VV002 M01/M01 Q1013 NOSIG=",
+			now:  time.Date(2025, time.October, 1, 0, 5, 0, 0, time.UTC),
+			expectedResult: &dto.Metar{
+				Station:    "EGLL",
+				Type:       "SPECI",
+				ObservedAt: time.Date(2025, time.September, 30, 23, 50, 0, 0, time.UTC),
+				Auto:       true,
+				Conditions: dto.Conditions{
+					Wind:       &dto.Wind{Variable: true, SpeedKt: 3},
+					Visibility: &dto.Visibility{StatuteMiles: 0.5, Meters: 800},
+					Weather:    []string{"FG"},
+					Clouds:     []dto.CloudLayer{{Cover: "VV", BaseFt: intPtr(200)}},
+				},
+				TemperatureC: intPtr(-1),
+				DewpointC:    intPtr(-1),
+				Altimeter:    &dto.Altimeter{InHg: 29.91, HPa: 1013},
+				Trend:        "NOSIG",
+				Raw:          "SPECI EGLL 302350Z AUTO VRB03KT 0800 FG VV002 M01/M01 Q1013 NOSIG=",
+			},
+		},
+		{
+			name: "Success parse CAVOK",
+			raw:  "LFPG 161830Z 05004MPS CAVOK 12/08 Q1021",
+			now:  now,
+			expectedResult: &dto.Metar{
+				Station:    "LFPG",
+				Type:       "METAR",
+				ObservedAt: time.Date(2025, time.October, 16, 18, 30, 0, 0, time.UTC),
+				Conditions: dto.Conditions{
+					Wind:       &dto.Wind{DirectionDeg: intPtr(50), SpeedKt: 8},
+					Visibility: &dto.Visibility{StatuteMiles: 6.21, Meters: 10000, Qualifier: dto.VisibilityGreaterThan},
+				},
+				TemperatureC: intPtr(12),
+				DewpointC:    intPtr(8),
+				Altimeter:    &dto.Altimeter{InHg: 30.15, HPa: 1021},
+				Raw:          "LFPG 161830Z 05004MPS CAVOK 12/08 Q1021",
+			},
+		},
+		{
+			name:        "Error missing station",
+			raw:         "161854Z 36006KT",
+			now:         now,
+			expectedErr: fmt.Errorf("Invalid METAR report, missing station"),
+		},
+		{
+			name:        "Error invalid observation time",
+			raw:         "KAVL 1618Z 36006KT",
+			now:         now,
+			expectedErr: fmt.Errorf("Invalid report time format (expected DDHHMMZ)"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMetar(tt.raw, tt.now)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedResult) {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+		})
+	}
+}
+
+func TestParseTaf(t *testing.T) {
+	now := time.Date(2025, time.October, 16, 19, 10, 0, 0, time.UTC)
+	day := func(d, h int) time.Time {
+		return time.Date(2025, time.October, d, h, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name           string
+		raw            string
+		expectedResult *dto.Taf
+		expectedErr    error
+	}{
+		{
+			name: "Success parse TAF with change groups",
+			raw:  "TAF AMD KAVL 161720Z 1618/1718 36006KT P6SM SCT250 FM170000 VRB03KT P6SM SKC TEMPO 1702/1706 3SM BR BKN008 PROB30 1710/1714 2SM -SHRA OVC015CB",
+			expectedResult: &dto.Taf{
+				Station:   "KAVL",
+				IssuedAt:  time.Date(2025, time.October, 16, 17, 20, 0, 0, time.UTC),
+				ValidFrom: day(16, 18),
+				ValidTo:   day(17, 18),
+				Amended:   true,
+				Forecasts: []dto.TafForecast{
+					{
+						Change: dto.TafChangeBase,
+						From:   timePtr(day(16, 18)),
+						To:     timePtr(day(17, 0)),
+						Conditions: dto.Conditions{
+							Wind:       &dto.Wind{DirectionDeg: intPtr(360), SpeedKt: 6},
+							Visibility: &dto.Visibility{StatuteMiles: 6, Meters: 9656, Qualifier: dto.VisibilityGreaterThan},
+							Clouds:     []dto.CloudLayer{{Cover: "SCT", BaseFt: intPtr(25000)}},
+						},
+					},
+					{
+						Change: dto.TafChangeFrom,
+						From:   timePtr(day(17, 0)),
+						To:     timePtr(day(17, 18)),
+						Conditions: dto.Conditions{
+							Wind:       &dto.Wind{Variable: true, SpeedKt: 3},
+							Visibility: &dto.Visibility{StatuteMiles: 6, Meters: 9656, Qualifier: dto.VisibilityGreaterThan},
+							Clouds:     []dto.CloudLayer{{Cover: "SKC"}},
+						},
+					},
+					{
+						Change: dto.TafChangeTempo,
+						From:   timePtr(day(17, 2)),
+						To:     timePtr(day(17, 6)),
+						Conditions: dto.Conditions{
+							Visibility: &dto.Visibility{StatuteMiles: 3, Meters: 4828},
+							Weather:    []string{"BR"},
+							Clouds:     []dto.CloudLayer{{Cover: "BKN", BaseFt: intPtr(800)}},
+						},
+					},
+					{
+						Change:      dto.TafChangeProb,
+						Probability: intPtr(30),
+						From:        timePtr(day(17, 10)),
+						To:          timePtr(day(17, 14)),
+						Conditions: dto.Conditions{
+							Visibility: &dto.Visibility{StatuteMiles: 2, Meters: 3219},
+							Weather:    []string{"-SHRA"},
+							Clouds:     []dto.CloudLayer{{Cover: "OVC", BaseFt: intPtr(1500), Type: "CB"}},
+						},
+					},
+				},
+				Raw: "TAF AMD KAVL 161720Z 1618/1718 36006KT P6SM SCT250 FM170000 VRB03KT P6SM SKC TEMPO 1702/1706 3SM BR BKN008 PROB30 1710/1714 2SM -SHRA OVC015CB",
+			},
+		},
+		{
+			name: "Success parse TAF ending at hour 24",
+			raw:  "EGLL 161100Z 1612/1724 24010KT 9999 BKN035 PROB30 TEMPO 1614/1618 4000 RA",
+			expectedResult: &dto.Taf{
+				Station:   "EGLL",
+				IssuedAt:  time.Date(2025, time.October, 16, 11, 0, 0, 0, time.UTC),
+				ValidFrom: day(16, 12),
+				ValidTo:   day(18, 0),
+				Forecasts: []dto.TafForecast{
+					{
+						Change: dto.TafChangeBase,
+						From:   timePtr(day(16, 12)),
+						To:     timePtr(day(18, 0)),
+						Conditions: dto.Conditions{
+							Wind:       &dto.Wind{DirectionDeg: intPtr(240), SpeedKt: 10},
+							Visibility: &dto.Visibility{StatuteMiles: 6.21, Meters: 10000, Qualifier: dto.VisibilityGreaterThan},
+							Clouds:     []dto.CloudLayer{{Cover: "BKN", BaseFt: intPtr(3500)}},
+						},
+					},
+					{
+						Change:      dto.TafChangeTempo,
+						Probability: intPtr(30),
+						From:        timePtr(day(16, 14)),
+						To:          timePtr(day(16, 18)),
+						Conditions: dto.Conditions{
+							Visibility: &dto.Visibility{StatuteMiles: 2.49, Meters: 4000},
+							Weather:    []string{"RA"},
+						},
+					},
+				},
+				Raw: "EGLL 161100Z 1612/1724 24010KT 9999 BKN035 PROB30 TEMPO 1614/1618 4000 RA",
+			},
+		},
+		{
+			name:        "Error missing validity period",
+			raw:         "TAF KAVL 161720Z 36006KT P6SM SCT250",
+			expectedErr: fmt.Errorf("Invalid TAF report, missing validity period"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTaf(tt.raw, now)
+			if (err == nil) != (tt.expectedErr == nil) || (err != nil && err.Error() != tt.expectedErr.Error()) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expectedResult) {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"aviation-service/pkg/redis"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrLockHeld = errors.New("Lock is held by another owner")
+
+// The scripts only touch the key while it still holds the token of this owner, so an expired lock taken over by someone else is left alone
+const (
+	renewLockScript   = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("PEXPIRE", KEYS[1], ARGV[2]) else return 0 end`
+	releaseLockScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+)
+
+type RedisLock struct {
+	client  redis.RedisClient
+	key     string
+	token   string
+	ttl     time.Duration
+	cancel  context.CancelFunc
+	stop    chan struct{}
+	stopped sync.WaitGroup
+	once    sync.Once
+}
+
+// AcquireLock takes key with SET NX PX and renews it every third of ttl until Release, it returns ErrLockHeld when
+// another owner has it. The returned context is cancelled when the lock is lost, so work done under it stops
+func AcquireLock(r redis.RedisClient, ctx context.Context, key string, ttl time.Duration) (*RedisLock, context.Context, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, nil, err
+	}
+
+	lock := &RedisLock{client: r, key: key, token: hex.EncodeToString(token), ttl: ttl, stop: make(chan struct{})}
+	acquired, err := r.SetNX(ctx, key, lock.token, ttl).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !acquired {
+		return nil, nil, ErrLockHeld
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+	lock.cancel = cancel
+	lock.stopped.Add(1)
+	go lock.renew(lockCtx)
+	return lock, lockCtx, nil
+}
+
+// Release stops the renewal and deletes the lock if it is still held, it is safe to call more than once
+func (l *RedisLock) Release(ctx context.Context) error {
+	var err error
+	l.once.Do(func() {
+		close(l.stop)
+		l.stopped.Wait()
+		l.cancel()
+		err = l.client.Eval(ctx, releaseLockScript, []string{l.key}, l.token).Err()
+	})
+	return err
+}
+
+func (l *RedisLock) renew(ctx context.Context) {
+	defer l.stopped.Done()
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A failed renewal is retried on the next tick, the lock is only given up once the key no longer holds our token
+			renewed, err := l.client.Eval(context.WithoutCancel(ctx), renewLockScript, []string{l.key}, l.token, l.ttl.Milliseconds()).Int64()
+			if err == nil && renewed == 0 {
+				l.cancel()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package utils_test
+
+import (
+	. "aviation-service/internal/mock"
+	"aviation-service/internal/utils"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAcquireLock(t *testing.T) {
+	ctx := context.Background()
+	redisClient := &MockRedis{Store: map[string]string{}}
+
+	lock, _, err := utils.AcquireLock(redisClient, ctx, "sync:lock", time.Minute)
+	if err != nil {
+		t.Fatalf("Expected lock acquired, got %v", err)
+	}
+
+	if _, _, err := utils.AcquireLock(redisClient, ctx, "sync:lock", time.Minute); !errors.Is(err, utils.ErrLockHeld) {
+		t.Errorf("Expected %v while the lock is held, got %v", utils.ErrLockHeld, err)
+	}
+
+	if err := lock.Release(ctx); err != nil {
+		t.Fatalf("Expected lock released, got %v", err)
+	}
+	if err := lock.Release(ctx); err != nil {
+		t.Errorf("Expected a second release to be a no-op, got %v", err)
+	}
+
+	again, _, err := utils.AcquireLock(redisClient, ctx, "sync:lock", time.Minute)
+	if err != nil {
+		t.Fatalf("Expected lock acquired after release, got %v", err)
+	}
+	again.Release(ctx)
+}
+
+func TestAcquireLock_Lost(t *testing.T) {
+	ctx := context.Background()
+	redisClient := &MockRedis{Store: map[string]string{}}
+
+	lock, lockCtx, err := utils.AcquireLock(redisClient, ctx, "sync:lock", 30*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Expected lock acquired, got %v", err)
+	}
+	defer lock.Release(ctx)
+
+	// Another owner took the key over after it expired
+	redisClient.Set(ctx, "sync:lock", "other-owner", time.Minute)
+
+	select {
+	case <-lockCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the lock context cancelled once the lock is lost")
+	}
+
+	lock.Release(ctx)
+	if owner := redisClient.Get(ctx, "sync:lock").Val(); owner != "other-owner" {
+		t.Errorf("Expected the lock of the other owner kept, got %q", owner)
+	}
+}
+
+func TestAcquireLock_Error(t *testing.T) {
+	if _, _, err := utils.AcquireLock(&MockRedisSetError{}, context.Background(), "sync:lock", time.Minute); err == nil || err.Error() != "Cache set failed" {
+		t.Errorf("Expected error Cache set failed, got %v", err)
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"aviation-service/pkg/redis"
 	"context"
 	"encoding/json"
+	"errors"
+	"strconv"
 	"time"
+
+	goredis "github.com/redis/go-redis/v9"
 )
 
 func GetStruct(r redis.RedisClient, ctx context.Context, key string, out interface{}) error {
@@ -28,4 +32,21 @@ func SetStruct(r redis.RedisClient, ctx context.Context, key string, in interfac
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// GetGeneration returns the current value of a cache generation counter, 0 when it was never bumped
+func GetGeneration(r redis.RedisClient, ctx context.Context, key string) (int64, error) {
+	val, err := r.Get(ctx, key).Result()
+	if errors.Is(err, goredis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(val, 10, 64)
+}
+
+// BumpGeneration increments a cache generation counter so every key built from the previous value is orphaned
+func BumpGeneration(r redis.RedisClient, ctx context.Context, key string) error {
+	return r.Incr(ctx, key).Err()
+}
@@ -117,3 +117,78 @@ func TestSetStruct(t *testing.T) {
 		})
 	}
 }
+
+func TestGetGeneration(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		redisClient    r.RedisClient
+		expectedErr    error
+		expectedResult int64
+	}{
+		{
+			name:           "Success get generation",
+			redisClient:    &MockRedis{Store: map[string]string{"airport:generation": "3"}},
+			expectedResult: 3,
+		},
+		{
+			name:           "Success get generation never bumped",
+			redisClient:    &MockRedis{Store: map[string]string{}},
+			expectedResult: 0,
+		},
+		{
+			name:        "Error get generation not a number",
+			redisClient: &MockRedis{Store: map[string]string{"airport:generation": "A"}},
+			expectedErr: fmt.Errorf(`strconv.ParseInt: parsing "A": invalid syntax`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := utils.GetGeneration(tt.redisClient, ctx, "airport:generation")
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if got != tt.expectedResult {
+				t.Errorf("Expected result %v, got %v", tt.expectedResult, got)
+			}
+		})
+	}
+}
+
+func TestBumpGeneration(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		redisClient  r.RedisClient
+		expectedErr  error
+		expectedData string
+	}{
+		{
+			name:         "Success bump generation",
+			redisClient:  &MockRedis{Store: map[string]string{"airport:generation": "3"}},
+			expectedData: "4",
+		},
+		{
+			name:        "Error bump generation",
+			redisClient: &MockRedisSetError{},
+			expectedErr: fmt.Errorf("Cache incr failed"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := utils.BumpGeneration(tt.redisClient, ctx, "airport:generation")
+			if err != nil && err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			got, _ := tt.redisClient.Get(ctx, "airport:generation").Result()
+			if got != tt.expectedData {
+				t.Errorf("Expected data %v, got %v", tt.expectedData, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_airport_coordinates;
+
+ALTER TABLE airport
+    DROP COLUMN IF EXISTS latitude_deg,
+    DROP COLUMN IF EXISTS longitude_deg;
@@ -0,0 +1,19 @@
+ALTER TABLE airport
+    ADD COLUMN IF NOT EXISTS latitude_deg DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS longitude_deg DOUBLE PRECISION;
+
+UPDATE airport SET latitude_deg = (
+        split_part(latitude, '-', 1)::DOUBLE PRECISION
+        + split_part(latitude, '-', 2)::DOUBLE PRECISION / 60
+        + rtrim(split_part(latitude, '-', 3), 'NS')::DOUBLE PRECISION / 3600
+    ) * CASE WHEN right(latitude, 1) = 'S' THEN -1 ELSE 1 END
+WHERE latitude ~ '^\d{2}-\d{2}-\d{2}(\.\d+)?[NS]$';
+
+UPDATE airport SET longitude_deg = (
+        split_part(longitude, '-', 1)::DOUBLE PRECISION
+        + split_part(longitude, '-', 2)::DOUBLE PRECISION / 60
+        + rtrim(split_part(longitude, '-', 3), 'EW')::DOUBLE PRECISION / 3600
+    ) * CASE WHEN right(longitude, 1) = 'W' THEN -1 ELSE 1 END
+WHERE longitude ~ '^\d{3}-\d{2}-\d{2}(\.\d+)?[EW]$';
+
+CREATE INDEX IF NOT EXISTS idx_airport_coordinates ON airport (latitude_deg, longitude_deg);
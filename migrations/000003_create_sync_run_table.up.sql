@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS sync_run (
+    id SERIAL PRIMARY KEY,
+    trigger VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    total INT NOT NULL DEFAULT 0,
+    success INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    error INT NOT NULL DEFAULT 0,
+    error_message TEXT
+);
+
+CREATE TABLE IF NOT EXISTS sync_run_item (
+    id SERIAL PRIMARY KEY,
+    sync_run_id INT NOT NULL REFERENCES sync_run (id) ON DELETE CASCADE,
+    batch INT NOT NULL,
+    icao VARCHAR(10) NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_run_item_sync_run_id ON sync_run_item (sync_run_id);
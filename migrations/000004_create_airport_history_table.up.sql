@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS airport_history (
+    id SERIAL PRIMARY KEY,
+    airport_id INT NOT NULL,
+    icao VARCHAR(10) NOT NULL,
+    action VARCHAR(10) NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_airport_history_airport_id ON airport_history (airport_id, created_at DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS airport_conflict;
+ALTER TABLE airport DROP COLUMN IF EXISTS field_sources;
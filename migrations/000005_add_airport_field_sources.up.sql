@@ -0,0 +1,17 @@
+ALTER TABLE airport ADD COLUMN IF NOT EXISTS field_sources JSONB NOT NULL DEFAULT '{}';
+
+CREATE TABLE IF NOT EXISTS airport_conflict (
+    id SERIAL PRIMARY KEY,
+    airport_id INT NOT NULL REFERENCES airport (id) ON DELETE CASCADE,
+    icao VARCHAR(10) NOT NULL,
+    field VARCHAR(50) NOT NULL,
+    manual_value TEXT,
+    upstream_value TEXT,
+    sync_run_id INT REFERENCES sync_run (id) ON DELETE SET NULL,
+    resolution VARCHAR(20),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ
+);
+
+-- At most one open conflict per airport field, a later sync refreshes it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_airport_conflict_open ON airport_conflict (airport_id, field) WHERE resolved_at IS NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE sync_run_item DROP COLUMN IF EXISTS changes;
+ALTER TABLE sync_run DROP COLUMN IF EXISTS mode;
+DROP INDEX IF EXISTS idx_airport_last_synced_at;
+ALTER TABLE airport DROP COLUMN IF EXISTS last_synced_at;
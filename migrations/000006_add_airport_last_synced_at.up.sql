@@ -0,0 +1,8 @@
+ALTER TABLE airport ADD COLUMN IF NOT EXISTS last_synced_at TIMESTAMPTZ;
+
+-- Stale refreshes scan DONE airports by their last sync, never synced rows first
+CREATE INDEX IF NOT EXISTS idx_airport_last_synced_at ON airport (last_synced_at NULLS FIRST) WHERE status = 'DONE';
+
+ALTER TABLE sync_run ADD COLUMN IF NOT EXISTS mode VARCHAR(20) NOT NULL DEFAULT 'PENDING';
+
+ALTER TABLE sync_run_item ADD COLUMN IF NOT EXISTS changes JSONB;
@@ -0,0 +1,6 @@
+UPDATE airport SET status = 'PENDING' WHERE status IN ('SYNCING', 'RETRYING');
+
+DROP INDEX IF EXISTS idx_airport_sync_due;
+ALTER TABLE airport DROP COLUMN IF EXISTS next_attempt_at;
+ALTER TABLE airport DROP COLUMN IF EXISTS last_error;
+ALTER TABLE airport DROP COLUMN IF EXISTS attempt_count;
@@ -0,0 +1,8 @@
+ALTER TABLE airport ADD COLUMN IF NOT EXISTS attempt_count INT NOT NULL DEFAULT 0;
+ALTER TABLE airport ADD COLUMN IF NOT EXISTS last_error TEXT;
+ALTER TABLE airport ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_airport_sync_due ON airport (status, next_attempt_at);
+
+-- Airports that came back empty used to be FAILED for good, they get one more attempt under the retry policy
+UPDATE airport SET status = 'RETRYING', attempt_count = 1, next_attempt_at = NOW() WHERE status = 'FAILED';
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS api_key (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    -- The first characters of the key, enough to tell keys apart in listings and logs
+    prefix VARCHAR(16) NOT NULL,
+    -- SHA-256 of the key, the key itself is only shown once when it is issued
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ
+);
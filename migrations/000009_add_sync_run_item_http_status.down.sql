@@ -0,0 +1 @@
+ALTER TABLE sync_run_item DROP COLUMN IF EXISTS http_status;
@@ -0,0 +1,2 @@
+-- The status code the upstream rejected a failed batch with, NULL when it failed without a response
+ALTER TABLE sync_run_item ADD COLUMN IF NOT EXISTS http_status INT;
@@ -2,7 +2,9 @@ package httpserver
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -17,9 +19,15 @@ type Server struct {
 	server *http.Server
 }
 
-func NewRouter(handlers ...Handler) *chi.Mux {
+// NewRouter mounts the handlers behind authenticate, which puts the caller identity in the request context before
+// anything logs the request, and rateLimit, which is keyed by that identity. Limited requests are still logged and counted
+func NewRouter(authenticate, rateLimit func(http.Handler) http.Handler, handlers ...Handler) *chi.Mux {
 	r := chi.NewRouter()
+	r.Use(mid.Tracing)
+	r.Use(authenticate)
 	r.Use(mid.ZapLogger)
+	r.Use(mid.Metrics)
+	r.Use(rateLimit)
 	r.Use(middleware.Recoverer)
 
 	for _, h := range handlers {
@@ -29,17 +37,33 @@ func NewRouter(handlers ...Handler) *chi.Mux {
 	return r
 }
 
-func NewServer(router *chi.Mux, port string) *Server {
+// Timeouts bound how long a client may take to send a request and to read the response, and how long idle keep-alive connections stay open
+type Timeouts struct {
+	Read       time.Duration
+	ReadHeader time.Duration
+	Write      time.Duration
+	Idle       time.Duration
+}
+
+func NewServer(router *chi.Mux, port string, timeouts Timeouts) *Server {
 	return &Server{
 		server: &http.Server{
-			Addr:    ":" + port,
-			Handler: router,
+			Addr:              ":" + port,
+			Handler:           router,
+			ReadTimeout:       timeouts.Read,
+			ReadHeaderTimeout: timeouts.ReadHeader,
+			WriteTimeout:      timeouts.Write,
+			IdleTimeout:       timeouts.Idle,
 		},
 	}
 }
 
+// Start serves until Shutdown is called, it returns nil once the server was shut down
 func (s *Server) Start() error {
-	return s.server.ListenAndServe()
+	if err := s.server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Roles are ordered, every role is also granted what the roles before it are
+const (
+	RoleReader   = "reader"
+	RoleEditor   = "editor"
+	RoleOperator = "operator"
+)
+
+var roleRanks = map[string]int{
+	RoleReader:   1,
+	RoleEditor:   2,
+	RoleOperator: 3,
+}
+
+const (
+	AuthMethodAPIKey    = "api_key"
+	AuthMethodJWT       = "jwt"
+	AuthMethodAnonymous = "anonymous"
+)
+
+func ValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// Identity is the caller of a request. Subject names the caller within its method, the key name for an API key
+// and the sub claim for a JWT
+type Identity struct {
+	Subject string
+	Role    string
+	Method  string
+}
+
+// Grants reports whether the identity may call a route that requires role
+func (i *Identity) Grants(role string) bool {
+	return i != nil && roleRanks[i.Role] >= roleRanks[role] && roleRanks[role] > 0
+}
+
+// Actor is how the caller is recorded in logs and in the airport history, like api_key:ci
+func (i *Identity) Actor() string {
+	return i.Method + ":" + i.Subject
+}
+
+// Authenticator resolves the caller from the credentials of one kind, it returns nil and no error when the
+// request carries none of them
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Identity, error)
+}
+
+// CredentialsError rejects credentials that were sent but are not valid, Reason is safe to show to the caller
+type CredentialsError struct {
+	Reason string
+}
+
+func (e *CredentialsError) Error() string {
+	return e.Reason
+}
+
+type identityKey struct{}
+
+type authErrorKey struct{}
+
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the caller resolved by Authenticate, nil when there is none
+func IdentityFromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityKey{}).(*Identity)
+	return identity
+}
+
+// AuthErrorFromContext returns why the credentials of the request were rejected
+func AuthErrorFromContext(ctx context.Context) error {
+	err, _ := ctx.Value(authErrorKey{}).(error)
+	return err
+}
+
+// Authenticate resolves the caller with the first authenticator that finds credentials. It never rejects a request
+// itself, the routes decide which role they require. A request without credentials gets anonymousRole, or no
+// identity at all when anonymousRole is empty
+func Authenticate(anonymousRole string, authenticators ...Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			var identity *Identity
+			for _, authenticator := range authenticators {
+				var err error
+				identity, err = authenticator.Authenticate(r)
+				if err != nil {
+					ctx = context.WithValue(ctx, authErrorKey{}, err)
+					break
+				}
+				if identity != nil {
+					break
+				}
+			}
+			if identity == nil && AuthErrorFromContext(ctx) == nil && anonymousRole != "" {
+				identity = &Identity{Subject: "anonymous", Role: anonymousRole, Method: AuthMethodAnonymous}
+			}
+			if identity != nil {
+				ctx = WithIdentity(ctx, identity)
+				trace.SpanFromContext(ctx).SetAttributes(
+					attribute.String("enduser.id", identity.Actor()),
+					attribute.String("enduser.role", identity.Role),
+				)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"aviation-service/pkg/logger"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type statusWriter struct {
@@ -16,6 +18,11 @@ func (w *statusWriter) WriteHeader(statusCode int) {
 	w.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Unwrap lets http.ResponseController reach the connection, to flush or move the deadlines of a streamed response
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func ZapLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -24,12 +31,20 @@ func ZapLogger(next http.Handler) http.Handler {
 		ww := &statusWriter{ResponseWriter: w, status: http.StatusOK}
 		next.ServeHTTP(ww, r)
 
-		logger.With(
+		fields := []interface{}{
 			"method", r.Method,
 			"path", r.URL.Path,
 			"status", ww.status,
 			"duration", time.Since(start),
 			"remote_addr", r.RemoteAddr,
-		).Info("HTTP Request")
+		}
+		if identity := IdentityFromContext(r.Context()); identity != nil {
+			fields = append(fields, "actor", identity.Actor())
+		}
+		// Lets the log line be looked up from its trace
+		if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+			fields = append(fields, "trace_id", sc.TraceID().String())
+		}
+		logger.With(fields...).Info("HTTP Request")
 	})
 }
\ No newline at end of file
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"aviation-service/pkg/logger"
+	"aviation-service/pkg/redis"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// DefaultRouteCosts weigh routes by the upstream calls and database work behind them, every other route costs 1.
+// Keys are the method and the chi route pattern, a cost of 0 leaves the route unlimited
+var DefaultRouteCosts = map[string]int{
+	"GET /healthz":               0,
+	"GET /readyz":                0,
+	"GET /metrics":               0,
+	"GET /airport-weather":       10,
+	"GET /weather":               2,
+	"GET /airport/{icao}/metar":  2,
+	"GET /airport/{icao}/taf":    2,
+	"GET /airport/export":        5,
+	"POST /airport/import":       10,
+	"POST /airport/{id}/refresh": 2,
+	"POST /sync":                 5,
+}
+
+type RateLimitOptions struct {
+	// Rate is the number of tokens a client gets back per second, zero or less disables the limit
+	Rate float64
+	// Burst is the size of the bucket, the most a client can spend at once
+	Burst int
+	// Costs are the tokens a request takes by "METHOD /route/{pattern}"
+	Costs map[string]int
+	// TrustForwardedFor keys anonymous clients by the last X-Forwarded-For address, only safe behind a proxy that sets it
+	TrustForwardedFor bool
+}
+
+// ParseRouteCosts reads "GET /airport-weather=10,POST /sync=5" over the default costs
+func ParseRouteCosts(value string) (map[string]int, error) {
+	costs := map[string]int{}
+	for route, cost := range DefaultRouteCosts {
+		costs[route] = cost
+	}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, costValue, found := strings.Cut(entry, "=")
+		method, pattern, hasPattern := strings.Cut(strings.TrimSpace(route), " ")
+		cost, err := strconv.Atoi(strings.TrimSpace(costValue))
+		if !found || !hasPattern || !strings.HasPrefix(pattern, "/") || err != nil || cost < 0 {
+			return nil, fmt.Errorf("Invalid route cost %q (expected METHOD /pattern=cost)", entry)
+		}
+		costs[strings.ToUpper(method)+" "+pattern] = cost
+	}
+	return costs, nil
+}
+
+// RateLimit gives every client a token bucket in Redis, callers authenticated with a key or a token are limited by
+// identity and everyone else by address. It has to run after Authenticate. While Redis is unreachable requests are
+// let through, the upstream rate limits still protect the quotas
+func RateLimit(redisClient redis.RedisClient, opts RateLimitOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if opts.Rate <= 0 || opts.Burst < 1 {
+			return next
+		}
+		policy := fmt.Sprintf("%d;w=%d", opts.Burst, int(math.Ceil(float64(opts.Burst)/opts.Rate)))
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cost := routeCost(r, opts.Costs)
+			if cost == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			client := rateLimitClient(r, opts.TrustForwardedFor)
+			bucket, err := redis.TakeTokens(r.Context(), redisClient, "ratelimit:client:"+client, opts.Rate, opts.Burst, cost, false)
+			if err != nil {
+				logger.Errorw("Failed to take rate limit tokens, letting the request through", "error", err, "client", client)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Policy", policy)
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(opts.Burst))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(int(math.Floor(bucket.Tokens))))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(int(math.Ceil((float64(opts.Burst)-bucket.Tokens)/opts.Rate))))
+			if bucket.Allowed {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			retryAfter := int(math.Ceil(bucket.Wait.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			logger.Infow("Rate limited request", "client", client, "cost", cost, "retry_after", retryAfter, "path", r.URL.Path)
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			respondRateLimited(w)
+		})
+	}
+}
+
+// routeCost finds the route the router will match, the middleware runs before routing so the pattern is not set yet
+func routeCost(r *http.Request, costs map[string]int) int {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return 1
+	}
+	match := chi.NewRouteContext()
+	if !rctx.Routes.Match(match, r.Method, r.URL.Path) {
+		return 1
+	}
+	if cost, ok := costs[r.Method+" "+match.RoutePattern()]; ok {
+		return cost
+	}
+	return 1
+}
+
+func rateLimitClient(r *http.Request, trustForwardedFor bool) string {
+	if identity := IdentityFromContext(r.Context()); identity != nil && identity.Method != AuthMethodAnonymous {
+		return identity.Actor()
+	}
+	if trustForwardedFor {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			addresses := strings.Split(forwarded, ",")
+			return "ip:" + strings.TrimSpace(addresses[len(addresses)-1])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// respondRateLimited answers in the shape of dto.Response, which this package can not import
+func respondRateLimited(w http.ResponseWriter) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"success": false,
+		"message": "Error occurred",
+		"error":   "Rate limit exceeded",
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	w.Write(body)
+}
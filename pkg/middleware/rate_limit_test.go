@@ -0,0 +1,358 @@
+package middleware_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	. "aviation-service/pkg/middleware"
+	"aviation-service/pkg/redis"
+
+	"github.com/go-chi/chi/v5"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// bucketRedis answers the token bucket script with reply or err and records the bucket keys and arguments
+type bucketRedis struct {
+	redis.RedisClient
+	reply []interface{}
+	err   error
+	keys  []string
+	args  []interface{}
+	calls int
+}
+
+func (r *bucketRedis) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *goredis.Cmd {
+	r.calls++
+	r.keys = keys
+	r.args = args
+	return goredis.NewCmdResult(r.reply, r.err)
+}
+
+func newRateLimitRouter(redisClient redis.RedisClient, opts RateLimitOptions, identity *Identity) http.Handler {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if identity != nil {
+				r = r.WithContext(WithIdentity(r.Context(), identity))
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	r.Use(RateLimit(redisClient, opts))
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	r.Get("/healthz", ok)
+	r.Get("/airport", ok)
+	r.Get("/airport/{id}", ok)
+	r.Post("/sync", ok)
+	return r
+}
+
+func TestRateLimit(t *testing.T) {
+	opts := RateLimitOptions{
+		Rate:  2,
+		Burst: 10,
+		Costs: map[string]int{"GET /healthz": 0, "GET /airport/{id}": 3, "POST /sync": 5},
+	}
+	allowed := []interface{}{int64(1), "7.5", int64(0)}
+
+	tests := []struct {
+		name              string
+		method            string
+		path              string
+		identity          *Identity
+		forwardedFor      string
+		trustForwardedFor bool
+		rate              float64
+		reply             []interface{}
+		evalErr           error
+		expectedStatus    int
+		expectedKey       string
+		expectedCost      int
+		expectedHeaders   map[string]string
+	}{
+		{
+			name:           "API key is limited by key name",
+			method:         http.MethodGet,
+			path:           "/airport",
+			identity:       &Identity{Subject: "ci", Role: RoleEditor, Method: AuthMethodAPIKey},
+			reply:          allowed,
+			expectedStatus: http.StatusOK,
+			expectedKey:    "ratelimit:client:api_key:ci",
+			expectedCost:   1,
+		},
+		{
+			name:           "JWT is limited by subject",
+			method:         http.MethodGet,
+			path:           "/airport",
+			identity:       &Identity{Subject: "user-1", Role: RoleReader, Method: AuthMethodJWT},
+			reply:          allowed,
+			expectedStatus: http.StatusOK,
+			expectedKey:    "ratelimit:client:jwt:user-1",
+			expectedCost:   1,
+		},
+		{
+			name:           "Anonymous is limited by address",
+			method:         http.MethodGet,
+			path:           "/airport",
+			identity:       &Identity{Subject: "anonymous", Role: RoleReader, Method: AuthMethodAnonymous},
+			reply:          allowed,
+			expectedStatus: http.StatusOK,
+			expectedKey:    "ratelimit:client:ip:192.0.2.1",
+			expectedCost:   1,
+		},
+		{
+			name:           "X-Forwarded-For is ignored unless trusted",
+			method:         http.MethodGet,
+			path:           "/airport",
+			forwardedFor:   "198.51.100.7, 203.0.113.9",
+			reply:          allowed,
+			expectedStatus: http.StatusOK,
+			expectedKey:    "ratelimit:client:ip:192.0.2.1",
+			expectedCost:   1,
+		},
+		{
+			name:              "Trusted X-Forwarded-For uses the last address",
+			method:            http.MethodGet,
+			path:              "/airport",
+			forwardedFor:      "198.51.100.7, 203.0.113.9",
+			trustForwardedFor: true,
+			reply:             allowed,
+			expectedStatus:    http.StatusOK,
+			expectedKey:       "ratelimit:client:ip:203.0.113.9",
+			expectedCost:      1,
+		},
+		{
+			name:           "Route cost by pattern",
+			method:         http.MethodGet,
+			path:           "/airport/42",
+			reply:          allowed,
+			expectedStatus: http.StatusOK,
+			expectedKey:    "ratelimit:client:ip:192.0.2.1",
+			expectedCost:   3,
+		},
+		{
+			name:           "Route cost by method",
+			method:         http.MethodPost,
+			path:           "/sync",
+			reply:          allowed,
+			expectedStatus: http.StatusOK,
+			expectedKey:    "ratelimit:client:ip:192.0.2.1",
+			expectedCost:   5,
+		},
+		{
+			name:           "Unmatched route costs 1",
+			method:         http.MethodGet,
+			path:           "/unknown",
+			reply:          allowed,
+			expectedStatus: http.StatusNotFound,
+			expectedKey:    "ratelimit:client:ip:192.0.2.1",
+			expectedCost:   1,
+		},
+		{
+			name:           "Route of cost 0 is not limited",
+			method:         http.MethodGet,
+			path:           "/healthz",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Disabled limit",
+			method:         http.MethodGet,
+			path:           "/airport",
+			rate:           -1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Rate limit headers",
+			method:         http.MethodGet,
+			path:           "/airport",
+			reply:          allowed,
+			expectedStatus: http.StatusOK,
+			expectedKey:    "ratelimit:client:ip:192.0.2.1",
+			expectedCost:   1,
+			expectedHeaders: map[string]string{
+				"RateLimit-Policy":    "10;w=5",
+				"RateLimit-Limit":     "10",
+				"RateLimit-Remaining": "7",
+				"RateLimit-Reset":     "2",
+				"Retry-After":         "",
+			},
+		},
+		{
+			name:           "Empty bucket",
+			method:         http.MethodPost,
+			path:           "/sync",
+			reply:          []interface{}{int64(0), "0.5", int64(2250)},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedKey:    "ratelimit:client:ip:192.0.2.1",
+			expectedCost:   5,
+			expectedHeaders: map[string]string{
+				"RateLimit-Limit":     "10",
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     "5",
+				"Retry-After":         "3",
+				"Content-Type":        "application/json",
+			},
+		},
+		{
+			name:           "Retry-After is at least a second",
+			method:         http.MethodGet,
+			path:           "/airport",
+			reply:          []interface{}{int64(0), "0.9", int64(50)},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedKey:    "ratelimit:client:ip:192.0.2.1",
+			expectedCost:   1,
+			expectedHeaders: map[string]string{
+				"Retry-After": "1",
+			},
+		},
+		{
+			name:           "Redis error lets the request through",
+			method:         http.MethodGet,
+			path:           "/airport",
+			evalErr:        fmt.Errorf("Cache eval failed"),
+			expectedStatus: http.StatusOK,
+			expectedKey:    "ratelimit:client:ip:192.0.2.1",
+			expectedCost:   1,
+			expectedHeaders: map[string]string{
+				"RateLimit-Limit": "",
+				"Retry-After":     "",
+			},
+		},
+		{
+			name:           "Unexpected reply lets the request through",
+			method:         http.MethodGet,
+			path:           "/airport",
+			reply:          []interface{}{int64(1)},
+			expectedStatus: http.StatusOK,
+			expectedKey:    "ratelimit:client:ip:192.0.2.1",
+			expectedCost:   1,
+			expectedHeaders: map[string]string{
+				"RateLimit-Limit": "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisClient := &bucketRedis{reply: tt.reply, err: tt.evalErr}
+			testOpts := opts
+			testOpts.TrustForwardedFor = tt.trustForwardedFor
+			if tt.rate != 0 {
+				testOpts.Rate = tt.rate
+			}
+			router := newRateLimitRouter(redisClient, testOpts, tt.identity)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.RemoteAddr = "192.0.2.1:54321"
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+			if tt.expectedKey == "" {
+				if redisClient.calls != 0 {
+					t.Errorf("Expected no token bucket call, got %d", redisClient.calls)
+				}
+			} else {
+				if !reflect.DeepEqual(redisClient.keys, []string{tt.expectedKey}) {
+					t.Errorf("Expected bucket key %s, got %v", tt.expectedKey, redisClient.keys)
+				}
+				expectedArgs := []interface{}{testOpts.Rate, testOpts.Burst, tt.expectedCost, 0}
+				if !reflect.DeepEqual(redisClient.args, expectedArgs) {
+					t.Errorf("Expected script arguments %v, got %v", expectedArgs, redisClient.args)
+				}
+			}
+			for header, expected := range tt.expectedHeaders {
+				if got := rr.Header().Get(header); got != expected {
+					t.Errorf("Expected header %s %q, got %q", header, expected, got)
+				}
+			}
+			if tt.expectedStatus == http.StatusTooManyRequests {
+				expectedBody := `{"error":"Rate limit exceeded","message":"Error occurred","success":false}`
+				if rr.Body.String() != expectedBody {
+					t.Errorf("Expected body %s, got %s", expectedBody, rr.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestParseRouteCosts(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expected      map[string]int
+		expectedError string
+	}{
+		{
+			name:     "Empty keeps the defaults",
+			value:    "",
+			expected: DefaultRouteCosts,
+		},
+		{
+			name:  "Overrides and adds routes",
+			value: "GET /airport-weather=3, post /airport/{id}/history=4,,GET /healthz=1",
+			expected: func() map[string]int {
+				costs := map[string]int{}
+				for route, cost := range DefaultRouteCosts {
+					costs[route] = cost
+				}
+				costs["GET /airport-weather"] = 3
+				costs["POST /airport/{id}/history"] = 4
+				costs["GET /healthz"] = 1
+				return costs
+			}(),
+		},
+		{
+			name:          "Missing cost",
+			value:         "GET /sync",
+			expectedError: `Invalid route cost "GET /sync" (expected METHOD /pattern=cost)`,
+		},
+		{
+			name:          "Missing method",
+			value:         "/sync=5",
+			expectedError: `Invalid route cost "/sync=5" (expected METHOD /pattern=cost)`,
+		},
+		{
+			name:          "Pattern without leading slash",
+			value:         "POST sync=5",
+			expectedError: `Invalid route cost "POST sync=5" (expected METHOD /pattern=cost)`,
+		},
+		{
+			name:          "Cost is not a number",
+			value:         "POST /sync=five",
+			expectedError: `Invalid route cost "POST /sync=five" (expected METHOD /pattern=cost)`,
+		},
+		{
+			name:          "Negative cost",
+			value:         "GET /weather=2,POST /sync=-1",
+			expectedError: `Invalid route cost "POST /sync=-1" (expected METHOD /pattern=cost)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			costs, err := ParseRouteCosts(tt.value)
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Fatalf("Expected error %q, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(costs, tt.expected) {
+				t.Errorf("Expected costs %v, got %v", tt.expected, costs)
+			}
+		})
+	}
+}
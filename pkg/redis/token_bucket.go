@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// The bucket is refilled from the Redis clock, so replicas with skewed clocks still share one budget. A reservation
+// (ARGV[4] = 1) always takes its cost and may leave the bucket in debt, otherwise a request is only let through when
+// the bucket holds its whole cost and a refused request takes nothing
+const takeTokensScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local cost = math.min(tonumber(ARGV[3]), burst)
+local reserve = ARGV[4] == "1"
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "last")
+local tokens = tonumber(bucket[1]) or burst
+local last = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + (now - last) * rate / 1000)
+local allowed = 1
+local wait = 0
+if reserve or tokens >= cost then
+	tokens = tokens - cost
+	if tokens < 0 then
+		wait = math.ceil(-tokens * 1000 / rate)
+	end
+else
+	allowed = 0
+	wait = math.ceil((cost - tokens) * 1000 / rate)
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "last", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) * 1000 / rate) + 1000)
+return {allowed, tostring(tokens), wait}`
+
+// TokenBucketResult is what is left in a bucket after taking from it, Wait is how long until the taken tokens are covered
+// or, when the request was refused, until the bucket holds its cost
+type TokenBucketResult struct {
+	Allowed bool
+	Tokens  float64
+	Wait    time.Duration
+}
+
+// TakeTokens takes cost tokens from the bucket at key, refilled by rate tokens per second up to burst. With reserve the
+// tokens are always taken and the caller waits for them, without it the request is refused when the bucket is short
+func TakeTokens(ctx context.Context, client RedisClient, key string, rate float64, burst, cost int, reserve bool) (TokenBucketResult, error) {
+	reserveFlag := 0
+	if reserve {
+		reserveFlag = 1
+	}
+	result, err := client.Eval(ctx, takeTokensScript, []string{key}, rate, burst, cost, reserveFlag).Slice()
+	if err != nil {
+		return TokenBucketResult{}, err
+	}
+	if len(result) != 3 {
+		return TokenBucketResult{}, fmt.Errorf("Unexpected token bucket reply %v", result)
+	}
+	allowed, _ := result[0].(int64)
+	tokens, err := strconv.ParseFloat(fmt.Sprint(result[1]), 64)
+	if err != nil {
+		return TokenBucketResult{}, fmt.Errorf("Unexpected token bucket reply %v", result)
+	}
+	waitMs, _ := result[2].(int64)
+	return TokenBucketResult{Allowed: allowed == 1, Tokens: tokens, Wait: time.Duration(waitMs) * time.Millisecond}, nil
+}